@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// AccessLogOptions writes each request as an Apache/NGINX combined log
+// format line, for legacy analyzers and grepping during incidents:
+//
+//	127.0.0.1 - u1 [10/Oct/2000:13:55:36 -0700] "GET /orders?page=2 HTTP/1.1" 200 2326 "https://shop.example/" "Mozilla/5.0"
+type AccessLogOptions struct {
+	// Writer receives the lines, e.g. an access.log file (required)
+	Writer io.Writer
+	// Only writes the combined line instead of the JSON request entry
+	Only bool
+}
+
+// accessRecord holds the request details of a combined log line
+type accessRecord struct {
+	ip        string
+	user      string
+	time      time.Time
+	method    string
+	uri       string
+	protocol  string
+	status    int
+	bytes     int
+	referer   string
+	userAgent string
+}
+
+// accessLog serializes lines written by concurrent requests
+type accessLog struct {
+	mu          sync.Mutex
+	writer      io.Writer
+	anonymizeIP IPAnonymization
+	salt        []byte
+}
+
+func newAccessLog(opts *AccessLogOptions, logger *Logger) *accessLog {
+	if opts == nil || opts.Writer == nil {
+		return nil
+	}
+	return &accessLog{writer: opts.Writer, anonymizeIP: logger.config.AnonymizeIP, salt: logger.salt}
+}
+
+// write writes the record as one line; write errors are ignored like
+// other output errors
+func (a *accessLog) write(record accessRecord) {
+	if a == nil {
+		return
+	}
+	if a.anonymizeIP != "" {
+		record.ip = anonymizeIP(a.anonymizeIP, a.salt, record.ip)
+	}
+	line := appendCombined(make([]byte, 0, 256), record)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, _ = a.writer.Write(line)
+}
+
+// appendCombined formats record in the combined log format. Empty values
+// and zero byte counts are written as "-", like Apache's %b.
+func appendCombined(b []byte, r accessRecord) []byte {
+	b = appendAccessField(b, r.ip)
+	b = append(b, " - "...)
+	b = appendAccessField(b, r.user)
+	b = append(b, " ["...)
+	b = r.time.AppendFormat(b, "02/Jan/2006:15:04:05 -0700")
+	b = append(b, "] \""...)
+	b = appendEscaped(b, r.method+" "+r.uri+" "+r.protocol)
+	b = append(b, "\" "...)
+	b = strconv.AppendInt(b, int64(r.status), 10)
+	b = append(b, ' ')
+	if r.bytes > 0 {
+		b = strconv.AppendInt(b, int64(r.bytes), 10)
+	} else {
+		b = append(b, '-')
+	}
+	b = append(b, " \""...)
+	b = appendEscaped(b, orDash(r.referer))
+	b = append(b, "\" \""...)
+	b = appendEscaped(b, orDash(r.userAgent))
+	return append(b, "\"\n"...)
+}
+
+// appendAccessField appends an unquoted field, "-" when empty
+func appendAccessField(b []byte, value string) []byte {
+	if value == "" {
+		return append(b, '-')
+	}
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c <= ' ' || c >= 0x7f {
+			b = appendHexEscape(b, c)
+		} else {
+			b = append(b, c)
+		}
+	}
+	return b
+}
+
+// appendEscaped appends a quoted field's content, escaping quotes,
+// backslashes and control characters as Apache does, so a line cannot be
+// split or forged
+func appendEscaped(b []byte, value string) []byte {
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '"' || c == '\\':
+			b = append(b, '\\', c)
+		case c < ' ' || c >= 0x7f:
+			b = appendHexEscape(b, c)
+		default:
+			b = append(b, c)
+		}
+	}
+	return b
+}
+
+func appendHexEscape(b []byte, c byte) []byte {
+	const digits = "0123456789abcdef"
+	return append(b, '\\', 'x', digits[c>>4], digits[c&0xf])
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAppendCombined(t *testing.T) {
+	start := time.Date(2000, time.October, 10, 13, 55, 36, 0, time.FixedZone("", -7*60*60))
+
+	tests := []struct {
+		name     string
+		record   accessRecord
+		expected string
+	}{
+		{
+			name: "full record",
+			record: accessRecord{
+				ip: "127.0.0.1", user: "u1", time: start,
+				method: "GET", uri: "/orders?page=2", protocol: "HTTP/1.1",
+				status: 200, bytes: 2326,
+				referer: "https://shop.example/", userAgent: "Mozilla/5.0",
+			},
+			expected: `127.0.0.1 - u1 [10/Oct/2000:13:55:36 -0700] "GET /orders?page=2 HTTP/1.1" 200 2326 "https://shop.example/" "Mozilla/5.0"` + "\n",
+		},
+		{
+			name: "empty values become dashes",
+			record: accessRecord{
+				ip: "10.0.0.1", time: start,
+				method: "HEAD", uri: "/", protocol: "HTTP/1.1", status: 204,
+			},
+			expected: `10.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "HEAD / HTTP/1.1" 204 - "-" "-"` + "\n",
+		},
+		{
+			name: "quotes and control characters are escaped",
+			record: accessRecord{
+				ip: "10.0.0.1", user: "a b", time: start,
+				method: "GET", uri: "/", protocol: "HTTP/1.1", status: 200, bytes: 1,
+				userAgent: "evil\" \n10.0.0.2 - - [fake]\\",
+			},
+			expected: `10.0.0.1 - a\x20b [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.1" 200 1 "-" "evil\" \x0a10.0.0.2 - - [fake]\\"` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(appendCombined(nil, tt.record)); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFiberMiddlewareAccessLog(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "access-log-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	t.Run("should write a combined line alongside the JSON entry", func(t *testing.T) {
+		observedLogs.TakeAll()
+		var buf bytes.Buffer
+
+		app := fiber.New()
+		app.Use(FiberMiddleware(&MiddlewareOptions{AccessLog: &AccessLogOptions{Writer: &buf}}))
+		app.Get("/orders", func(c *fiber.Ctx) error {
+			return c.SendString("hello")
+		})
+
+		req := httptest.NewRequest("GET", "/orders?page=2&token=secret", nil)
+		req.Header.Set("User-Agent", "curl/8.0")
+		_, _ = app.Test(req)
+
+		line := buf.String()
+		if !strings.Contains(line, `"GET /orders?page=2&token=[REDACTED] HTTP/1.1" 200 5 "-" "curl/8.0"`) {
+			t.Errorf("Unexpected access line: %q", line)
+		}
+		if strings.Count(line, "\n") != 1 {
+			t.Errorf("Expected exactly one line, got %q", line)
+		}
+		if n := observedLogs.Len(); n != 1 {
+			t.Errorf("Expected 1 JSON entry, got %d", n)
+		}
+	})
+
+	t.Run("should write only the combined line with Only", func(t *testing.T) {
+		observedLogs.TakeAll()
+		var buf bytes.Buffer
+
+		app := fiber.New()
+		app.Use(FiberMiddleware(&MiddlewareOptions{AccessLog: &AccessLogOptions{Writer: &buf, Only: true}}))
+		app.Get("/orders", func(c *fiber.Ctx) error {
+			return c.SendStatus(404)
+		})
+
+		_, _ = app.Test(httptest.NewRequest("GET", "/orders", nil))
+
+		if !strings.Contains(buf.String(), `"GET /orders HTTP/1.1" 404`) {
+			t.Errorf("Unexpected access line: %q", buf.String())
+		}
+		if n := observedLogs.Len(); n != 0 {
+			t.Errorf("Expected no JSON entries, got %d", n)
+		}
+	})
+}
+
+func TestHTTPMiddlewareAccessLog(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "access-log-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	var buf bytes.Buffer
+	handler := HTTPMiddleware(&HTTPMiddlewareOptions{
+		AccessLog: &AccessLogOptions{Writer: &buf, Only: true},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("created"))
+	}))
+
+	req := httptest.NewRequest("POST", "/orders", nil)
+	req.Header.Set("Referer", "https://shop.example/cart")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	expected := `"POST /orders HTTP/1.1" 200 7 "https://shop.example/cart" "-"` + "\n"
+	if line := buf.String(); !strings.HasPrefix(line, "192.0.2.1 - - [") || !strings.HasSuffix(line, expected) {
+		t.Errorf("Unexpected access line: %q", line)
+	}
+	if n := observedLogs.Len(); n != 0 {
+		t.Errorf("Expected no JSON entries, got %d", n)
+	}
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LevelHandlerOptions configures the admin level handler
+type LevelHandlerOptions struct {
+	// Token must be sent as "Authorization: Bearer <token>". Requests are
+	// rejected when it is empty, so the handler is never left open.
+	Token string
+}
+
+// levelRequest is the body of a level change
+type levelRequest struct {
+	Level string `json:"level"`
+}
+
+// LevelHandler returns a Fiber handler for runtime level management, to be
+// mounted under an admin route:
+//
+//	app.All("/admin/log-level", logger.LevelHandler(&logger.LevelHandlerOptions{Token: token}))
+//
+// GET returns the current level and effective configuration, and PUT with
+// {"level":"DEBUG"} changes the level. Both require the bearer token.
+func LevelHandler(opts *LevelHandlerOptions) fiber.Handler {
+	if opts == nil {
+		opts = &LevelHandlerOptions{}
+	}
+
+	logger := GetInstance()
+
+	return func(c *fiber.Ctx) error {
+		if !validToken(c.Get(fiber.HeaderAuthorization), opts.Token) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+		}
+
+		switch c.Method() {
+		case fiber.MethodGet:
+		case fiber.MethodPut:
+			var request levelRequest
+			if err := c.BodyParser(&request); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+			}
+			level, err := ParseLevel(request.Level)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			}
+			logger.SetLevel(level, "admin")
+		default:
+			c.Set(fiber.HeaderAllow, "GET, PUT")
+			return c.Status(fiber.StatusMethodNotAllowed).JSON(fiber.Map{"error": "method not allowed"})
+		}
+
+		return c.JSON(fiber.Map{
+			"level":  logger.Level(),
+			"config": logger.configSummary(),
+		})
+	}
+}
+
+// validToken reports whether the Authorization header carries the bearer
+// token, comparing in constant time
+func validToken(header, token string) bool {
+	if token == "" {
+		return false
+	}
+	provided, ok := strings.CutPrefix(header, "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestLevelHandler(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "admin-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelINFO,
+	})
+
+	app := fiber.New()
+	app.All("/admin/log-level", LevelHandler(&LevelHandlerOptions{Token: "s3cret"}))
+
+	request := func(method, token, body string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(method, "/admin/log-level", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		raw, _ := io.ReadAll(resp.Body)
+		var decoded map[string]interface{}
+		_ = json.Unmarshal(raw, &decoded)
+		return resp.StatusCode, decoded
+	}
+
+	t.Run("should reject requests without the token", func(t *testing.T) {
+		if status, _ := request("GET", "", ""); status != 401 {
+			t.Errorf("Expected 401 without token, got %d", status)
+		}
+		if status, _ := request("PUT", "wrong", `{"level":"DEBUG"}`); status != 401 {
+			t.Errorf("Expected 401 with wrong token, got %d", status)
+		}
+		if logger.Level() != LevelINFO {
+			t.Errorf("Expected level unchanged, got %s", logger.Level())
+		}
+	})
+
+	t.Run("should return the current level and config", func(t *testing.T) {
+		status, body := request("GET", "s3cret", "")
+		if status != 200 || body["level"] != "INFO" {
+			t.Fatalf("Expected 200 with level INFO, got %d %v", status, body)
+		}
+		if config, _ := body["config"].(map[string]interface{}); config["service_name"] != "admin-test" {
+			t.Errorf("Expected config summary, got %v", body["config"])
+		}
+	})
+
+	t.Run("should change the level", func(t *testing.T) {
+		observedLogs.TakeAll()
+		status, body := request("PUT", "s3cret", `{"level":"debug"}`)
+		if status != 200 || body["level"] != "DEBUG" {
+			t.Fatalf("Expected 200 with level DEBUG, got %d %v", status, body)
+		}
+		if logger.Level() != LevelDEBUG {
+			t.Errorf("Expected DEBUG, got %s", logger.Level())
+		}
+
+		logs := observedLogs.FilterMessage("Log level changed").All()
+		if len(logs) != 1 || logs[0].ContextMap()["previous_level"] != "INFO" || logs[0].ContextMap()["source"] != "admin" {
+			t.Errorf("Expected level change entry, got %v", logs)
+		}
+	})
+
+	t.Run("should reject invalid levels and methods", func(t *testing.T) {
+		if status, _ := request("PUT", "s3cret", `{"level":"verbose"}`); status != 400 {
+			t.Errorf("Expected 400 for invalid level, got %d", status)
+		}
+		if status, _ := request("DELETE", "s3cret", ""); status != 405 {
+			t.Errorf("Expected 405, got %d", status)
+		}
+	})
+
+	t.Run("should reject all requests without a configured token", func(t *testing.T) {
+		open := fiber.New()
+		open.Get("/", LevelHandler(nil))
+		resp, _ := open.Test(httptest.NewRequest("GET", "/", nil))
+		if resp.StatusCode != 401 {
+			t.Errorf("Expected 401, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// entryBuffer collects entries logged during a request so they can be
+// emitted as a single aggregated entry
+type entryBuffer struct {
+	mu       sync.Mutex
+	entries  []map[string]interface{}
+	maxLevel zapcore.Level
+	omit     LogContext
+	closed   bool
+}
+
+// withBuffer returns a child logger whose entries are held in buf until it
+// is drained. Keys in omit (e.g. request_id) are dropped from buffered
+// entries since the aggregated entry already carries them.
+func (l *Logger) withBuffer(buf *entryBuffer) *Logger {
+	child := *l
+	child.zap = l.zap.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &bufferCore{next: core, buf: buf}
+	}))
+	return &child
+}
+
+// drain returns the buffered entries and the highest level seen. Entries
+// logged afterwards bypass the buffer.
+func (b *entryBuffer) drain() ([]map[string]interface{}, zapcore.Level) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	entries := b.entries
+	b.entries = nil
+	return entries, b.maxLevel
+}
+
+// add stores an encoded entry, reporting false once the buffer is drained
+func (b *entryBuffer) add(entry zapcore.Entry, fields []zapcore.Field) bool {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		if _, ok := b.omit[field.Key]; !ok {
+			field.AddTo(encoder)
+		}
+	}
+	encoder.Fields["@timestamp"] = entry.Time.Format(time.RFC3339Nano)
+	encoder.Fields["log.level"] = entry.Level.CapitalString()
+	encoder.Fields["message"] = entry.Message
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return false
+	}
+	if len(b.entries) == 0 || entry.Level > b.maxLevel {
+		b.maxLevel = entry.Level
+	}
+	b.entries = append(b.entries, encoder.Fields)
+	return true
+}
+
+// bufferCore diverts entries into an entryBuffer, using the wrapped core
+// for level checks, for audit and security entries and for entries written
+// after the buffer is drained
+type bufferCore struct {
+	next   zapcore.Core
+	buf    *entryBuffer
+	fields []zapcore.Field
+}
+
+func (c *bufferCore) Enabled(level zapcore.Level) bool {
+	return c.next.Enabled(level)
+}
+
+func (c *bufferCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &bufferCore{next: c.next.With(fields), buf: c.buf, fields: merged}
+}
+
+func (c *bufferCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *bufferCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	// Audit and security entries are written as they happen, through the
+	// wrapped core's own checks so the audit chain and sink see them
+	if hasLogType(fields, TypeAudit) || hasLogType(fields, TypeSecurity) {
+		if checked := c.next.Check(entry, nil); checked != nil {
+			checked.Write(fields...)
+		}
+		return nil
+	}
+
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(all, c.fields...)
+	all = append(all, fields...)
+	if c.buf.add(entry, all) {
+		return nil
+	}
+	return c.next.Write(entry, fields)
+}
+
+func (c *bufferCore) Sync() error {
+	return c.next.Sync()
+}
+
+// logLevel converts a zapcore.Level to the closest LogLevel
+func logLevel(level zapcore.Level) LogLevel {
+	switch {
+	case level >= zapcore.ErrorLevel:
+		return LevelERROR
+	case level == zapcore.WarnLevel:
+		return LevelWARN
+	case level == zapcore.InfoLevel:
+		return LevelINFO
+	default:
+		return LevelDEBUG
+	}
+}
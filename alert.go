@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrorRateAlert configures a callback fired when ERROR entries exceed a
+// threshold within a time window
+type ErrorRateAlert struct {
+	// Threshold is the number of ERROR entries allowed per window; the
+	// callback fires on the first entry above it
+	Threshold int
+	// Window is the length of each counting window (default 1 minute)
+	Window time.Duration
+	// PerErrorCode counts entries separately for each error_code field value
+	PerErrorCode bool
+	// OnAlert is called at most once per window (and error code). It runs on
+	// the logging goroutine, so it should return quickly.
+	OnAlert func(event ErrorRateEvent)
+}
+
+// ErrorRateEvent describes an exceeded error rate
+type ErrorRateEvent struct {
+	// ErrorCode is the error_code the entries were counted under, empty
+	// unless PerErrorCode is set
+	ErrorCode string
+	Count     int
+	Window    time.Duration
+}
+
+// errorRateWindow is the count for one key in the current window
+type errorRateWindow struct {
+	start   time.Time
+	count   int
+	alerted bool
+}
+
+// errorRateTracker counts ERROR entries in fixed windows
+type errorRateTracker struct {
+	alert   ErrorRateAlert
+	now     func() time.Time
+	mu      sync.Mutex
+	windows map[string]*errorRateWindow
+}
+
+func newErrorRateTracker(alert ErrorRateAlert) *errorRateTracker {
+	if alert.Window <= 0 {
+		alert.Window = time.Minute
+	}
+	return &errorRateTracker{
+		alert:   alert,
+		now:     time.Now,
+		windows: make(map[string]*errorRateWindow),
+	}
+}
+
+// record counts an ERROR entry and fires the alert when the threshold is
+// first exceeded in the current window
+func (t *errorRateTracker) record(errorCode string) {
+	if !t.alert.PerErrorCode {
+		errorCode = ""
+	}
+	now := t.now()
+
+	t.mu.Lock()
+	window, ok := t.windows[errorCode]
+	if !ok || now.Sub(window.start) >= t.alert.Window {
+		window = &errorRateWindow{start: now}
+		t.windows[errorCode] = window
+	}
+	window.count++
+
+	fire := !window.alerted && window.count > t.alert.Threshold
+	if fire {
+		window.alerted = true
+	}
+	count := window.count
+	t.mu.Unlock()
+
+	if fire && t.alert.OnAlert != nil {
+		t.alert.OnAlert(ErrorRateEvent{ErrorCode: errorCode, Count: count, Window: t.alert.Window})
+	}
+}
+
+// errorRateCore feeds ERROR entries written by the wrapped core to a tracker
+type errorRateCore struct {
+	zapcore.Core
+	tracker *errorRateTracker
+}
+
+func (c *errorRateCore) With(fields []zapcore.Field) zapcore.Core {
+	return &errorRateCore{Core: c.Core.With(fields), tracker: c.tracker}
+}
+
+func (c *errorRateCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *errorRateCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if entry.Level >= zapcore.ErrorLevel {
+		var errorCode string
+		for _, field := range fields {
+			if field.Key == "error_code" && field.Type == zapcore.StringType {
+				errorCode = field.String
+				break
+			}
+		}
+		c.tracker.record(errorCode)
+	}
+	return c.Core.Write(entry, fields)
+}
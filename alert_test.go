@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestErrorRateAlert(t *testing.T) {
+	var events []ErrorRateEvent
+	tracker := newErrorRateTracker(ErrorRateAlert{
+		Threshold: 2,
+		Window:    time.Minute,
+		OnAlert: func(event ErrorRateEvent) {
+			events = append(events, event)
+		},
+	})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	observedCore, _ := observer.New(zapcore.DebugLevel)
+	l := &Logger{zap: zap.New(&errorRateCore{Core: observedCore, tracker: tracker})}
+	ctx := context.Background()
+
+	l.Error(ctx, "one", nil)
+	l.Error(ctx, "two", nil)
+	l.Warn(ctx, "not counted", nil)
+	if len(events) != 0 {
+		t.Fatalf("Expected no alert at the threshold, got %v", events)
+	}
+
+	l.Error(ctx, "three", nil)
+	l.Error(ctx, "four", nil)
+	if len(events) != 1 || events[0].Count != 3 || events[0].Window != time.Minute {
+		t.Fatalf("Expected one alert on the first entry above the threshold, got %v", events)
+	}
+
+	now = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		l.Error(ctx, "next window", nil)
+	}
+	if len(events) != 2 {
+		t.Errorf("Expected a new alert in the next window, got %d alerts", len(events))
+	}
+}
+
+func TestErrorRateAlertPerErrorCode(t *testing.T) {
+	var events []ErrorRateEvent
+	tracker := newErrorRateTracker(ErrorRateAlert{
+		Threshold:    1,
+		PerErrorCode: true,
+		OnAlert: func(event ErrorRateEvent) {
+			events = append(events, event)
+		},
+	})
+
+	observedCore, _ := observer.New(zapcore.DebugLevel)
+	l := &Logger{zap: zap.New(&errorRateCore{Core: observedCore, tracker: tracker})}
+	ctx := context.Background()
+
+	l.Error(ctx, "payment failed", LogContext{"error_code": "PAYMENT_DECLINED"})
+	l.Error(ctx, "stock check failed", LogContext{"error_code": "OUT_OF_STOCK"})
+	l.Error(ctx, "payment failed", LogContext{"error_code": "PAYMENT_DECLINED"})
+
+	if len(events) != 1 || events[0].ErrorCode != "PAYMENT_DECLINED" || events[0].Count != 2 {
+		t.Errorf("Expected one alert for PAYMENT_DECLINED, got %v", events)
+	}
+	if tracker.alert.Window != time.Minute {
+		t.Errorf("Expected default window of 1 minute, got %v", tracker.alert.Window)
+	}
+}
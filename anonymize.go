@@ -0,0 +1,63 @@
+package logger
+
+import "net"
+
+// IPAnonymization selects how client IPs are anonymized
+type IPAnonymization string
+
+const (
+	// IPTruncate zeroes the last octet of IPv4 and the last 80 bits of
+	// IPv6 addresses (e.g. 198.51.100.0), keeping the network for coarse
+	// correlation
+	IPTruncate IPAnonymization = "truncate"
+	// IPHash replaces addresses with salted hashes (see
+	// Config.SensitiveSalt), so requests from one address still correlate
+	IPHash IPAnonymization = "hash"
+)
+
+// anonymizeIPFields anonymizes the client IP fields and each address of
+// the forwarded_for chain of an entry in place
+func anonymizeIPFields(mode IPAnonymization, salt []byte, fields LogContext) {
+	for _, key := range clientIPKeys {
+		if value, ok := fields[key].(string); ok && value != "" {
+			fields[key] = anonymizeIP(mode, salt, value)
+		}
+	}
+
+	// The chain is copied since the slice may be shared with the caller
+	if chain, ok := fields["forwarded_for"].([]string); ok {
+		anonymized := make([]string, len(chain))
+		for i, address := range chain {
+			anonymized[i] = anonymizeIP(mode, salt, address)
+		}
+		fields["forwarded_for"] = anonymized
+	}
+}
+
+// anonymizeIP anonymizes an address, dropping any port. Values that are
+// not IP addresses are hashed with salt in IPHash mode and kept otherwise.
+func anonymizeIP(mode IPAnonymization, salt []byte, value string) string {
+	host := value
+	if h, _, err := net.SplitHostPort(value); err == nil {
+		host = h
+	}
+	ip := net.ParseIP(host)
+
+	switch mode {
+	case IPHash:
+		if ip != nil {
+			host = ip.String()
+		}
+		return sensitiveValue{value: host, salt: salt}.String()
+	case IPTruncate:
+		if ip == nil {
+			return value
+		}
+		if ipv4 := ip.To4(); ipv4 != nil {
+			return ipv4.Mask(net.CIDRMask(24, 32)).String()
+		}
+		return ip.Mask(net.CIDRMask(48, 128)).String()
+	default:
+		return value
+	}
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAnonymizeIP(t *testing.T) {
+	tests := []struct {
+		mode     IPAnonymization
+		value    string
+		expected string
+	}{
+		{IPTruncate, "198.51.100.74", "198.51.100.0"},
+		{IPTruncate, "198.51.100.74:51234", "198.51.100.0"},
+		{IPTruncate, "2001:db8:85a3:8d3:1319:8a2e:370:7348", "2001:db8:85a3::"},
+		{IPTruncate, "[2001:db8:85a3::1]:443", "2001:db8:85a3::"},
+		{IPTruncate, "unknown", "unknown"},
+		{IPHash, "198.51.100.74:51234", sensitiveValue{value: "198.51.100.74"}.String()},
+	}
+
+	for _, tt := range tests {
+		if got := anonymizeIP(tt.mode, nil, tt.value); got != tt.expected {
+			t.Errorf("anonymizeIP(%s, %q) = %q, expected %q", tt.mode, tt.value, got, tt.expected)
+		}
+	}
+
+	if anonymizeIP(IPHash, nil, "198.51.100.74") == anonymizeIP(IPHash, nil, "198.51.100.75") {
+		t.Error("Expected different addresses to hash differently")
+	}
+}
+
+func TestAnonymizeIPFields(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "anonymize-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+		AnonymizeIP:    IPTruncate,
+	})
+
+	logger.SecurityLoginFailure(context.Background(), "alice", "203.0.113.195", nil)
+	logger.Info(context.Background(), "semconv", LogContext{"client.address": "2001:db8::1"})
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 2 {
+		t.Fatalf("Expected 2 log entries, got %d", len(logs))
+	}
+	if ip := logs[0].ContextMap()["ip"]; ip != "203.0.113.0" {
+		t.Errorf("Expected ip=203.0.113.0, got %v", ip)
+	}
+	if ip := logs[1].ContextMap()["client.address"]; ip != "2001:db8::" {
+		t.Errorf("Expected client.address=2001:db8::, got %v", ip)
+	}
+}
+
+func TestAccessLogAnonymizeIP(t *testing.T) {
+	setupObservedLogger(Config{
+		ServiceName:    "anonymize-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+		AnonymizeIP:    IPTruncate,
+	})
+
+	var buf bytes.Buffer
+	handler := HTTPMiddleware(&HTTPMiddlewareOptions{
+		AccessLog: &AccessLogOptions{Writer: &buf, Only: true},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.195:40000"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if line := buf.String(); !strings.HasPrefix(line, "203.0.113.0 - - [") {
+		t.Errorf("Expected an anonymized access line, got %q", line)
+	}
+}
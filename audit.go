@@ -0,0 +1,213 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AuditOutcome is the result of an audited action
+type AuditOutcome string
+
+const (
+	AuditSuccess AuditOutcome = "success"
+	AuditFailure AuditOutcome = "failure"
+	AuditDenied  AuditOutcome = "denied"
+)
+
+// ErrInvalidAuditEvent is returned by RecordAudit for events missing
+// required fields
+var ErrInvalidAuditEvent = errors.New("invalid audit event")
+
+// AuditEvent is a structured audit trail record
+type AuditEvent struct {
+	// Actor identifies who performed the action (required)
+	Actor string
+	// Action is what was done, e.g. "user.update" (required)
+	Action string
+	// Resource is the type of object acted on, e.g. "user" (required)
+	Resource string
+	// ResourceID identifies the object acted on
+	ResourceID string
+	// Outcome is AuditSuccess, AuditFailure or AuditDenied (required)
+	Outcome AuditOutcome
+	// Before and After hold the state of the resource around a change
+	Before interface{}
+	After  interface{}
+	// Reason explains the action or its outcome
+	Reason string
+	// Metadata holds additional fields
+	Metadata LogContext
+}
+
+// Validate checks that the required fields are set and the outcome is known
+func (e AuditEvent) Validate() error {
+	var missing []string
+	if e.Actor == "" {
+		missing = append(missing, "actor")
+	}
+	if e.Action == "" {
+		missing = append(missing, "action")
+	}
+	if e.Resource == "" {
+		missing = append(missing, "resource")
+	}
+	if e.Outcome == "" {
+		missing = append(missing, "outcome")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrInvalidAuditEvent, strings.Join(missing, ", "))
+	}
+
+	switch e.Outcome {
+	case AuditSuccess, AuditFailure, AuditDenied:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown outcome %q", ErrInvalidAuditEvent, e.Outcome)
+	}
+}
+
+// RecordAudit validates and logs a structured audit event (log_type =
+// "audit"). Invalid events are not logged.
+func (l *Logger) RecordAudit(ctx context.Context, event AuditEvent) error {
+	if err := event.Validate(); err != nil {
+		return err
+	}
+
+	context := make(LogContext, len(event.Metadata)+8)
+	for key, value := range event.Metadata {
+		context[key] = value
+	}
+	context["actor"] = event.Actor
+	context["action"] = event.Action
+	context["resource"] = event.Resource
+	context["outcome"] = string(event.Outcome)
+	if event.ResourceID != "" {
+		context["resource_id"] = event.ResourceID
+	}
+	if event.Before != nil {
+		context["before"] = event.Before
+	}
+	if event.After != nil {
+		context["after"] = event.After
+	}
+	if event.Reason != "" {
+		context["reason"] = event.Reason
+	}
+
+	message := fmt.Sprintf("%s %s %s", event.Actor, event.Action, event.Resource)
+	if event.ResourceID != "" {
+		message += "/" + event.ResourceID
+	}
+
+	l.log(ctx, 0, LevelINFO, TypeAudit, message, context)
+	return nil
+}
+
+// auditCore hash-chains audit entries and delivers them to the audit sink,
+// independently of the level of the wrapped output core
+type auditCore struct {
+	zapcore.Core
+	enc      zapcore.Encoder
+	chain    *auditChain
+	delivery *auditDelivery
+	// auditOnly skips the wrapped core for entries below its level
+	auditOnly bool
+}
+
+func (c *auditCore) Enabled(level zapcore.Level) bool {
+	return c.Core.Enabled(level) || (c.delivery != nil && level >= zapcore.InfoLevel)
+}
+
+func (c *auditCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	return &auditCore{Core: c.Core.With(fields), enc: enc, chain: c.chain, delivery: c.delivery}
+}
+
+func (c *auditCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	if c.delivery != nil && entry.Level >= zapcore.InfoLevel {
+		auditOnly := *c
+		auditOnly.auditOnly = true
+		return checked.AddCore(entry, &auditOnly)
+	}
+	return checked
+}
+
+func (c *auditCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if !isAuditEntry(fields) {
+		if c.auditOnly {
+			return nil
+		}
+		return c.Core.Write(entry, fields)
+	}
+
+	// Hold the chain lock until written so entries are chained in order
+	if c.chain != nil {
+		c.chain.mu.Lock()
+		defer c.chain.mu.Unlock()
+
+		fields = append(fields[:len(fields):len(fields)], zap.String("audit_prev_hash", c.chain.prev))
+		sum, err := c.hash(entry, fields)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, zap.String("audit_hash", sum))
+	}
+
+	var deliveryErr error
+	if c.delivery != nil {
+		buf, err := c.enc.EncodeEntry(entry, fields)
+		if err != nil {
+			return err
+		}
+		line := append([]byte(nil), buf.Bytes()...)
+		buf.Free()
+		deliveryErr = c.delivery.deliver(line)
+	}
+
+	if !c.auditOnly {
+		if err := c.Core.Write(entry, fields); err != nil {
+			return err
+		}
+	}
+	if deliveryErr != nil {
+		return deliveryErr
+	}
+
+	if c.chain != nil {
+		c.chain.prev = fields[len(fields)-1].String
+	}
+	return nil
+}
+
+// hash computes the chain hash of an entry as it will be encoded
+func (c *auditCore) hash(entry zapcore.Entry, fields []zapcore.Field) (string, error) {
+	buf, err := c.enc.EncodeEntry(entry, fields)
+	if err != nil {
+		return "", err
+	}
+	defer buf.Free()
+	return hashEntry(c.chain.key, buf.Bytes())
+}
+
+func (c *auditCore) Sync() error {
+	if c.delivery != nil {
+		c.delivery.mu.Lock()
+		err := c.delivery.flush()
+		c.delivery.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return c.Core.Sync()
+}
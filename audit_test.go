@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRecordAudit(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "audit-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelINFO,
+	})
+
+	err := logger.RecordAudit(context.Background(), AuditEvent{
+		Actor:      "admin-7",
+		Action:     "user.update_role",
+		Resource:   "user",
+		ResourceID: "u-42",
+		Outcome:    AuditSuccess,
+		Before:     LogContext{"role": "viewer"},
+		After:      LogContext{"role": "editor"},
+		Reason:     "ticket OPS-1",
+		Metadata:   LogContext{"tenant_id": "t-1"},
+	})
+	if err != nil {
+		t.Fatalf("Expected valid event to be recorded, got %v", err)
+	}
+
+	entry := observedLogs.TakeAll()[0]
+	if entry.Message != "admin-7 user.update_role user/u-42" {
+		t.Errorf("Unexpected message %q", entry.Message)
+	}
+
+	fields := entry.ContextMap()
+	expected := map[string]interface{}{
+		"log_type":    "audit",
+		"actor":       "admin-7",
+		"action":      "user.update_role",
+		"resource":    "user",
+		"resource_id": "u-42",
+		"outcome":     "success",
+		"reason":      "ticket OPS-1",
+		"tenant_id":   "t-1",
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, fields[key])
+		}
+	}
+	if before, ok := fields["before"].(LogContext); !ok || before["role"] != "viewer" {
+		t.Errorf("Expected before state, got %v", fields["before"])
+	}
+}
+
+func TestRecordAuditValidation(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "audit-validation-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelINFO,
+	})
+
+	tests := []struct {
+		name    string
+		event   AuditEvent
+		message string
+	}{
+		{"missing fields", AuditEvent{Action: "delete"}, "invalid audit event: missing actor, resource, outcome"},
+		{"unknown outcome", AuditEvent{Actor: "a", Action: "b", Resource: "c", Outcome: "maybe"}, `invalid audit event: unknown outcome "maybe"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := logger.RecordAudit(context.Background(), tt.event)
+			if !errors.Is(err, ErrInvalidAuditEvent) || err.Error() != tt.message {
+				t.Errorf("Expected %q, got %v", tt.message, err)
+			}
+		})
+	}
+
+	if logs := observedLogs.TakeAll(); len(logs) != 0 {
+		t.Errorf("Expected invalid events not to be logged, got %d entries", len(logs))
+	}
+}
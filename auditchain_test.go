@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newChainedLogger returns a logger writing hash-chained JSON lines to buf
+func newChainedLogger(buf *bytes.Buffer, opts *AuditChainOptions) *Logger {
+	core := zapcore.NewCore(newEncoder(), zapcore.AddSync(buf), zapcore.DebugLevel)
+	chained := &auditCore{Core: core, enc: newEncoder(), chain: newAuditChain(opts)}
+	return &Logger{zap: zap.New(chained).With(zap.String("service.name", "billing"))}
+}
+
+func TestAuditChain(t *testing.T) {
+	opts := &AuditChainOptions{Key: []byte("audit-key")}
+
+	var buf bytes.Buffer
+	l := newChainedLogger(&buf, opts)
+	ctx := context.Background()
+
+	l.Audit(ctx, "refund issued", LogContext{"amount": 12.5, "note": "<ok>"})
+	l.Info(ctx, "unrelated", nil)
+	_ = l.RecordAudit(ctx, AuditEvent{Actor: "a", Action: "delete", Resource: "invoice", Outcome: AuditSuccess})
+	l.Audit(ctx, "export", nil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 lines, got %d", len(lines))
+	}
+	if !strings.Contains(lines[0], `"audit_prev_hash":""`) || !strings.Contains(lines[0], `"audit_hash":"`) {
+		t.Errorf("Expected chain fields on audit entry, got %s", lines[0])
+	}
+	if strings.Contains(lines[1], "audit_hash") {
+		t.Errorf("Expected non-audit entry to be left unchained, got %s", lines[1])
+	}
+
+	if err := VerifyAuditChain(strings.NewReader(buf.String()), opts); err != nil {
+		t.Fatalf("Expected intact chain to verify, got %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		lines []string
+		opts  *AuditChainOptions
+	}{
+		{"modified entry", []string{lines[0], lines[1], strings.Replace(lines[2], `"actor":"a"`, `"actor":"b"`, 1), lines[3]}, opts},
+		{"removed entry", []string{lines[0], lines[1], lines[3]}, opts},
+		{"reordered entries", []string{lines[2], lines[0], lines[3]}, opts},
+		{"wrong key", lines, &AuditChainOptions{Key: []byte("other")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyAuditChain(strings.NewReader(strings.Join(tt.lines, "\n")), tt.opts)
+			if !errors.Is(err, ErrAuditChainBroken) {
+				t.Errorf("Expected ErrAuditChainBroken, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAuditChainResume(t *testing.T) {
+	var first, second bytes.Buffer
+
+	newChainedLogger(&first, &AuditChainOptions{}).Audit(context.Background(), "before restart", nil)
+	last, err := hashEntry(nil, bytes.TrimSpace(first.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newChainedLogger(&second, &AuditChainOptions{PreviousHash: last}).Audit(context.Background(), "after restart", nil)
+
+	if err := VerifyAuditChain(strings.NewReader(first.String()+second.String()), nil); err != nil {
+		t.Errorf("Expected resumed chain to verify, got %v", err)
+	}
+}
+
+func TestAuditChainEpochMillis(t *testing.T) {
+	opts := &AuditChainOptions{Key: []byte("audit-key")}
+
+	var buf bytes.Buffer
+	l := New(Config{ServiceName: "billing", Level: LevelINFO, Output: &buf, TimeFormat: TimeFormatEpochMillis, AuditChain: opts})
+	l.Audit(context.Background(), "refund issued", nil)
+	l.Audit(context.Background(), "export", nil)
+
+	if strings.Contains(buf.String(), `"@timestamp":"`) {
+		t.Fatalf("Expected numeric timestamps, got %s", buf.String())
+	}
+	if err := VerifyAuditChain(strings.NewReader(buf.String()), opts); err != nil {
+		t.Errorf("Expected the epoch_millis chain to verify, got %v", err)
+	}
+}
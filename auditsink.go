@@ -0,0 +1,177 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrAuditQueueFull is reported when an audit entry cannot be delivered and
+// the retry queue is full
+var ErrAuditQueueFull = errors.New("audit retry queue full")
+
+// AuditSink durably stores audit entries. WriteAudit must return only once
+// the entry is persisted (e.g. fsynced or acknowledged).
+type AuditSink interface {
+	WriteAudit(line []byte) error
+}
+
+// FileAuditSink appends audit entries to a file, fsyncing after each one
+type FileAuditSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileAuditSink opens (or creates) path for appending audit entries
+func NewFileAuditSink(path string) (*FileAuditSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileAuditSink{file: file}, nil
+}
+
+// WriteAudit appends line and fsyncs the file
+func (s *FileAuditSink) WriteAudit(line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(line); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Close closes the file
+func (s *FileAuditSink) Close() error {
+	return s.file.Close()
+}
+
+// AuditDeliveryOptions configures guaranteed delivery of audit entries
+type AuditDeliveryOptions struct {
+	// Sink stores audit entries (required)
+	Sink AuditSink
+	// QueueSize is the number of entries held for retry while the sink is
+	// failing (default 1000)
+	QueueSize int
+	// RetryInterval is the delay between delivery retries (default 1 second)
+	RetryInterval time.Duration
+	// OnFailure is called when the sink fails and when the retry queue is
+	// full, with the number of entries pending delivery
+	OnFailure func(err error, pending int)
+}
+
+// auditDelivery writes audit entries to the sink in order, queueing them
+// for retry while it fails
+type auditDelivery struct {
+	opts     AuditDeliveryOptions
+	metrics  EntryMetrics
+	mu       sync.Mutex
+	queue    [][]byte
+	retrying bool
+}
+
+func newAuditDelivery(opts *AuditDeliveryOptions, metrics EntryMetrics) *auditDelivery {
+	d := &auditDelivery{opts: *opts, metrics: metrics}
+	if d.opts.QueueSize <= 0 {
+		d.opts.QueueSize = 1000
+	}
+	if d.opts.RetryInterval <= 0 {
+		d.opts.RetryInterval = time.Second
+	}
+	return d
+}
+
+// deliver writes line to the sink, or queues it behind earlier undelivered
+// entries. It fails only when the entry cannot be queued.
+func (d *auditDelivery) deliver(line []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var err error
+	if len(d.queue) == 0 {
+		if err = d.opts.Sink.WriteAudit(line); err == nil {
+			return nil
+		}
+		if d.metrics != nil {
+			d.metrics.SinkError()
+		}
+	}
+
+	if len(d.queue) >= d.opts.QueueSize {
+		d.alarm(ErrAuditQueueFull)
+		if d.metrics != nil {
+			d.metrics.EntryDropped("audit_queue_full")
+		}
+		return ErrAuditQueueFull
+	}
+
+	d.queue = append(d.queue, line)
+	if err != nil {
+		d.alarm(err)
+	}
+	if !d.retrying {
+		d.retrying = true
+		go d.retry()
+	}
+	return nil
+}
+
+// retry redelivers queued entries until the queue is empty
+func (d *auditDelivery) retry() {
+	for {
+		time.Sleep(d.opts.RetryInterval)
+
+		d.mu.Lock()
+		err := d.flush()
+		if err == nil {
+			d.retrying = false
+			d.mu.Unlock()
+			return
+		}
+		d.alarm(err)
+		d.mu.Unlock()
+	}
+}
+
+// flush writes queued entries in order, stopping at the first failure.
+// Callers must hold d.mu.
+func (d *auditDelivery) flush() error {
+	for len(d.queue) > 0 {
+		if err := d.opts.Sink.WriteAudit(d.queue[0]); err != nil {
+			return err
+		}
+		d.queue = d.queue[1:]
+	}
+	return nil
+}
+
+// drain retries delivery of queued entries until the queue is empty or ctx
+// is done
+func (d *auditDelivery) drain(ctx context.Context) error {
+	for {
+		d.mu.Lock()
+		err := d.flush()
+		pending := len(d.queue)
+		d.mu.Unlock()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("audit delivery: %d entries undelivered: %w", pending, err)
+		case <-time.After(d.opts.RetryInterval):
+		}
+	}
+}
+
+// alarm reports a delivery failure. Callers must hold d.mu.
+func (d *auditDelivery) alarm(err error) {
+	if d.opts.OnFailure != nil {
+		d.opts.OnFailure(err, len(d.queue))
+	}
+}
@@ -0,0 +1,207 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// flakySink records audit lines and fails while fail is set
+type flakySink struct {
+	mu    sync.Mutex
+	fail  bool
+	lines []string
+}
+
+func (s *flakySink) WriteAudit(line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return errors.New("sink down")
+	}
+	s.lines = append(s.lines, string(line))
+	return nil
+}
+
+func (s *flakySink) setFail(fail bool) {
+	s.mu.Lock()
+	s.fail = fail
+	s.mu.Unlock()
+}
+
+func (s *flakySink) delivered() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.lines...)
+}
+
+// newDeliveringLogger returns a logger writing to buf at level and
+// delivering audit entries as configured by opts
+func newDeliveringLogger(buf *bytes.Buffer, level zapcore.Level, opts *AuditDeliveryOptions) *Logger {
+	core := zapcore.NewCore(newEncoder(), zapcore.AddSync(buf), level)
+	audit := &auditCore{Core: core, enc: newEncoder(), delivery: newAuditDelivery(opts, nil)}
+	return &Logger{zap: zap.New(audit)}
+}
+
+func TestAuditDelivery(t *testing.T) {
+	sink := &flakySink{}
+	var buf bytes.Buffer
+	l := newDeliveringLogger(&buf, zapcore.ErrorLevel, &AuditDeliveryOptions{Sink: sink})
+	ctx := context.Background()
+
+	l.Info(ctx, "not audit", nil)
+	l.Audit(ctx, "role granted", LogContext{"role": "admin"})
+
+	lines := sink.delivered()
+	if len(lines) != 1 || !strings.Contains(lines[0], `"message":"role granted"`) {
+		t.Fatalf("Expected only the audit entry to be delivered, got %v", lines)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected entries below the output level to be skipped, got %s", buf.String())
+	}
+}
+
+func TestAuditDeliveryBypassesAggregation(t *testing.T) {
+	sink := &flakySink{}
+	var buf bytes.Buffer
+	l := newDeliveringLogger(&buf, zapcore.ErrorLevel, &AuditDeliveryOptions{Sink: sink})
+	buffer := &entryBuffer{}
+	buffered := l.withBuffer(buffer)
+	ctx := context.Background()
+
+	buffered.Audit(ctx, "role granted", nil)
+	buffered.Security(ctx, "login failed", nil)
+	buffered.Error(ctx, "cache unavailable", nil)
+
+	if lines := sink.delivered(); len(lines) != 1 || !strings.Contains(lines[0], `"message":"role granted"`) {
+		t.Errorf("Expected the buffered logger's audit entry to be delivered, got %v", lines)
+	}
+	if entries, _ := buffer.drain(); len(entries) != 1 || entries[0]["message"] != "cache unavailable" {
+		t.Errorf("Expected only the error entry to be buffered, got %v", entries)
+	}
+}
+
+func TestAuditDeliveryRetry(t *testing.T) {
+	sink := &flakySink{fail: true}
+	var failures []int
+	var mu sync.Mutex
+	opts := &AuditDeliveryOptions{
+		Sink:          sink,
+		RetryInterval: 10 * time.Millisecond,
+		OnFailure: func(err error, pending int) {
+			mu.Lock()
+			failures = append(failures, pending)
+			mu.Unlock()
+		},
+	}
+
+	var buf bytes.Buffer
+	l := newDeliveringLogger(&buf, zapcore.InfoLevel, opts)
+	ctx := context.Background()
+
+	l.Audit(ctx, "first", nil)
+	l.Audit(ctx, "second", nil)
+
+	mu.Lock()
+	if len(failures) == 0 || failures[0] != 1 {
+		t.Errorf("Expected failure alarm with 1 pending entry, got %v", failures)
+	}
+	mu.Unlock()
+	if strings.Count(buf.String(), "\n") != 2 {
+		t.Errorf("Expected entries written to output while the sink fails, got %s", buf.String())
+	}
+
+	sink.setFail(false)
+	deadline := time.Now().Add(time.Second)
+	for len(sink.delivered()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	lines := sink.delivered()
+	if len(lines) != 2 || !strings.Contains(lines[0], "first") || !strings.Contains(lines[1], "second") {
+		t.Fatalf("Expected queued entries delivered in order, got %v", lines)
+	}
+}
+
+func TestAuditDeliveryQueueFull(t *testing.T) {
+	sink := &flakySink{fail: true}
+	var lastErr error
+	opts := &AuditDeliveryOptions{
+		Sink:          sink,
+		QueueSize:     1,
+		RetryInterval: time.Hour,
+		OnFailure:     func(err error, pending int) { lastErr = err },
+	}
+	d := newAuditDelivery(opts, nil)
+
+	if err := d.deliver([]byte("a\n")); err != nil {
+		t.Fatalf("Expected entry to be queued, got %v", err)
+	}
+	if err := d.deliver([]byte("b\n")); !errors.Is(err, ErrAuditQueueFull) {
+		t.Fatalf("Expected ErrAuditQueueFull, got %v", err)
+	}
+	if !errors.Is(lastErr, ErrAuditQueueFull) {
+		t.Errorf("Expected queue full alarm, got %v", lastErr)
+	}
+
+	sink.setFail(false)
+	core := &auditCore{Core: zapcore.NewNopCore(), delivery: d}
+	if err := core.Sync(); err != nil {
+		t.Fatalf("Expected Sync to flush the queue, got %v", err)
+	}
+	if lines := sink.delivered(); len(lines) != 1 || lines[0] != "a\n" {
+		t.Errorf("Expected queued entry flushed on Sync, got %v", lines)
+	}
+}
+
+func TestAuditDeliveryIgnoresDrop(t *testing.T) {
+	sink := &flakySink{}
+	var buf bytes.Buffer
+	opts := &AuditDeliveryOptions{Sink: sink}
+	l := newDeliveringLogger(&buf, zapcore.InfoLevel, opts)
+	l.config.AuditDelivery = opts
+	l.RegisterHook(func(entry Entry) Entry {
+		entry.Drop = true
+		return entry
+	})
+
+	l.Audit(context.Background(), "deleted user", nil)
+
+	if len(sink.delivered()) != 1 {
+		t.Errorf("Expected hooks not to drop audit entries when delivery is configured")
+	}
+}
+
+func TestFileAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileAuditSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"one\n", "two\n"} {
+		if err := sink.WriteAudit([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "one\ntwo\n" {
+		t.Errorf("Expected appended entries, got %q", data)
+	}
+}
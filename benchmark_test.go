@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newBenchmarkLogger returns a standalone logger encoding to io.Discard
+func newBenchmarkLogger(config Config) *Logger {
+	config.ServiceName = "benchmark"
+	config.ServiceVersion = "1.0.0"
+	config.Env = "test"
+	if config.Output == nil {
+		config.Output = io.Discard
+	}
+	if config.Level == "" {
+		config.Level = LevelINFO
+	}
+	return New(config)
+}
+
+// benchmarkContext carries a sampled span, as requests behind the
+// OpenTelemetry middleware do
+func benchmarkContext() context.Context {
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	return trace.ContextWithSpanContext(context.Background(), spanContext)
+}
+
+func BenchmarkInfo(b *testing.B) {
+	logger := newBenchmarkLogger(Config{})
+	ctx := benchmarkContext()
+
+	b.Run("no fields", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.Info(ctx, "Order created", nil)
+		}
+	})
+
+	b.Run("typed fields", func(b *testing.B) {
+		fields := LogContext{
+			"order_id":    "ord-123",
+			"items":       3,
+			"total":       99.5,
+			"paid":        true,
+			"duration_ms": int64(12),
+			"timeout":     2 * time.Second,
+		}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.Info(ctx, "Order created", fields)
+		}
+	})
+
+	b.Run("child and context fields", func(b *testing.B) {
+		child := logger.With(LogContext{"component": "checkout", "region": "eu"})
+		scoped := WithContextFields(ctx, LogContext{"request_id": "req-1", "user_id": "u1"})
+		fields := LogContext{"order_id": "ord-123", "items": 3}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			child.Info(scoped, "Order created", fields)
+		}
+	})
+
+	b.Run("nested fields", func(b *testing.B) {
+		fields := LogContext{"order": map[string]interface{}{"id": "ord-123", "lines": []int{1, 2, 3}}}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.Info(ctx, "Order created", fields)
+		}
+	})
+}
+
+func BenchmarkError(b *testing.B) {
+	logger := newBenchmarkLogger(Config{})
+	ctx := benchmarkContext()
+	err := errors.New("card declined")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Error(ctx, "Payment failed", LogContext{"error": err, "order_id": "ord-123"})
+	}
+}
+
+func BenchmarkDisabled(b *testing.B) {
+	logger := newBenchmarkLogger(Config{Level: LevelWARN})
+	ctx := benchmarkContext()
+	fields := LogContext{"order_id": "ord-123"}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Debug(ctx, "Cache hit", fields)
+	}
+}
+
+func BenchmarkHooks(b *testing.B) {
+	logger := newBenchmarkLogger(Config{Redactor: NewRedactor([]string{"password"})})
+	ctx := benchmarkContext()
+	fields := LogContext{"order_id": "ord-123", "items": 3}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info(ctx, "Order created", fields)
+	}
+}
+
+func BenchmarkParallel(b *testing.B) {
+	logger := newBenchmarkLogger(Config{})
+	ctx := benchmarkContext()
+	fields := LogContext{"order_id": "ord-123", "items": 3, "total": 99.5}
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info(ctx, "Order created", fields)
+		}
+	})
+}
+
+func BenchmarkEncoding(b *testing.B) {
+	ctx := benchmarkContext()
+	fields := LogContext{
+		"order_id":    "ord-123",
+		"items":       3,
+		"total":       99.5,
+		"paid":        true,
+		"duration_ms": int64(12),
+	}
+
+	for _, encoding := range []string{EncodingJSON, EncodingMsgpack, EncodingOTLP} {
+		b.Run(encoding, func(b *testing.B) {
+			counter := &countingWriter{}
+			logger := newBenchmarkLogger(Config{Encoding: encoding, Output: counter})
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				logger.Info(ctx, "Order created", fields)
+			}
+			b.ReportMetric(float64(counter.n)/float64(b.N), "bytes/entry")
+		})
+	}
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter struct {
+	n int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += len(p)
+	return len(p), nil
+}
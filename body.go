@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultMaxBodyBytes is the body capture limit when MaxBodyBytes is unset
+const defaultMaxBodyBytes = 4096
+
+// defaultBodyContentTypes lists the media types captured when
+// BodyContentTypes is unset; multipart and binary payloads are skipped
+var defaultBodyContentTypes = []string{
+	"application/json",
+	"application/xml",
+	"application/x-www-form-urlencoded",
+	"text/",
+}
+
+// defaultBodyRedactor masks captured bodies when Config.Redactor is unset,
+// so passwords and tokens in payloads are never logged in plain text
+var defaultBodyRedactor = DefaultRedactor()
+
+// captureBody returns the loggable form of a body, redacted with redactor
+// or else DefaultRedactor, or false when the body is empty or its content
+// type is not allowed
+func captureBody(contentType string, body []byte, allowed []string, maxBytes int, redactor *Redactor) (string, bool) {
+	if len(body) == 0 || !bodyContentTypeAllowed(contentType, allowed) {
+		return "", false
+	}
+
+	if redactor == nil {
+		redactor = defaultBodyRedactor
+	}
+	body = redactor.RedactBody(contentType, body)
+
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBodyBytes
+	}
+
+	return truncateString(string(body), maxBytes), true
+}
+
+// bodyContentTypeAllowed matches the media type against an allow-list of
+// exact types or prefixes ending in "/", accepting structured +json/+xml suffixes
+func bodyContentTypeAllowed(contentType string, allowed []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	if len(allowed) == 0 {
+		allowed = defaultBodyContentTypes
+		if strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
+			return true
+		}
+	}
+
+	for _, candidate := range allowed {
+		candidate = strings.ToLower(candidate)
+		if mediaType == candidate || (strings.HasSuffix(candidate, "/") && strings.HasPrefix(mediaType, candidate)) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateString cuts s to at most maxBytes without splitting a UTF-8
+// sequence and appends a marker with the original size
+func truncateString(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+
+	return fmt.Sprintf("%s...(truncated, %d bytes)", s[:cut], len(s))
+}
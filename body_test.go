@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCaptureBody(t *testing.T) {
+	t.Run("should capture allowed content types", func(t *testing.T) {
+		body, ok := captureBody("application/json; charset=utf-8", []byte(`{"a":1}`), nil, 0, nil)
+		if !ok || body != `{"a":1}` {
+			t.Errorf("Expected JSON body to be captured, got %q (%v)", body, ok)
+		}
+	})
+
+	t.Run("should capture structured suffix types by default", func(t *testing.T) {
+		if _, ok := captureBody("application/problem+json", []byte(`{}`), nil, 0, nil); !ok {
+			t.Error("Expected +json body to be captured")
+		}
+	})
+
+	t.Run("should skip multipart and binary bodies", func(t *testing.T) {
+		for _, contentType := range []string{"multipart/form-data; boundary=x", "application/octet-stream", "image/png", ""} {
+			if _, ok := captureBody(contentType, []byte("data"), nil, 0, nil); ok {
+				t.Errorf("Expected %q body to be skipped", contentType)
+			}
+		}
+	})
+
+	t.Run("should redact sensitive keys without a redactor", func(t *testing.T) {
+		body, ok := captureBody("application/json", []byte(`{"user":"ana","password":"hunter2"}`), nil, 0, nil)
+		if !ok || strings.Contains(body, "hunter2") || !strings.Contains(body, "ana") {
+			t.Errorf("Expected the password to be redacted by default, got %q", body)
+		}
+	})
+
+	t.Run("should skip empty bodies", func(t *testing.T) {
+		if _, ok := captureBody("application/json", nil, nil, 0, nil); ok {
+			t.Error("Expected empty body to be skipped")
+		}
+	})
+
+	t.Run("should honor custom allow-list", func(t *testing.T) {
+		allowed := []string{"application/vnd.api"}
+		if _, ok := captureBody("application/vnd.api", []byte("x"), allowed, 0, nil); !ok {
+			t.Error("Expected custom type to be captured")
+		}
+		if _, ok := captureBody("application/json", []byte("x"), allowed, 0, nil); ok {
+			t.Error("Expected JSON to be skipped with custom allow-list")
+		}
+	})
+
+	t.Run("should truncate oversized bodies", func(t *testing.T) {
+		body, ok := captureBody("text/plain", []byte(strings.Repeat("a", 20)), nil, 8, nil)
+		if !ok {
+			t.Fatal("Expected body to be captured")
+		}
+		if body != "aaaaaaaa...(truncated, 20 bytes)" {
+			t.Errorf("Unexpected truncated body %q", body)
+		}
+	})
+}
+
+func TestTruncateString(t *testing.T) {
+	t.Run("should keep short strings", func(t *testing.T) {
+		if got := truncateString("short", 10); got != "short" {
+			t.Errorf("Expected 'short', got %q", got)
+		}
+	})
+
+	t.Run("should not split multi-byte runes", func(t *testing.T) {
+		got := truncateString("héllo", 2)
+		if got != "h...(truncated, 6 bytes)" {
+			t.Errorf("Unexpected truncation %q", got)
+		}
+	})
+}
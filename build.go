@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"runtime/debug"
+
+	"go.uber.org/zap"
+)
+
+// Revision and BuildTime identify the build when injected with -ldflags,
+// taking precedence over the VCS stamp in the binary's build info:
+//
+//	go build -ldflags "-X github.com/rcommerz/logger-go.Revision=$(git rev-parse HEAD) \
+//		-X github.com/rcommerz/logger-go.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Revision  string
+	BuildTime string
+)
+
+// buildInfo describes the running binary
+type buildInfo struct {
+	Module   string
+	Version  string
+	Revision string
+	Time     string
+	Modified bool
+}
+
+// readBuildInfo returns the module and VCS stamp embedded by the Go
+// toolchain, overridden by Revision and BuildTime when they are set. The
+// VCS stamp is empty for binaries built without VCS information (e.g. go
+// run, -buildvcs=false or Docker builds without .git).
+func readBuildInfo() buildInfo {
+	var build buildInfo
+	if info, ok := debug.ReadBuildInfo(); ok {
+		build.Module, build.Version = info.Main.Path, info.Main.Version
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				build.Revision = setting.Value
+			case "vcs.time":
+				build.Time = setting.Value
+			case "vcs.modified":
+				build.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	if Revision != "" {
+		build.Revision, build.Modified = Revision, false
+	}
+	if BuildTime != "" {
+		build.Time = BuildTime
+	}
+	return build
+}
+
+// buildMetadataFields returns service.revision and service.build_time
+// for the running build, omitting those that are unknown
+func buildMetadataFields() []zap.Field {
+	build := readBuildInfo()
+
+	var fields []zap.Field
+	if build.Revision != "" {
+		fields = append(fields, zap.String("service.revision", build.Revision))
+	}
+	if build.Time != "" {
+		fields = append(fields, zap.String("service.build_time", build.Time))
+	}
+	return fields
+}
@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"testing"
+)
+
+func TestBuildMetadataFields(t *testing.T) {
+	defer func() { Revision, BuildTime = "", "" }()
+
+	t.Run("should prefer ldflags-injected values", func(t *testing.T) {
+		Revision, BuildTime = "4f2a9c1", "2026-10-16T08:00:00Z"
+
+		fields := buildMetadataFields()
+		if len(fields) != 2 {
+			t.Fatalf("Expected 2 fields, got %d", len(fields))
+		}
+		if fields[0].Key != "service.revision" || fields[0].String != "4f2a9c1" {
+			t.Errorf("Expected service.revision=4f2a9c1, got %s=%s", fields[0].Key, fields[0].String)
+		}
+		if fields[1].Key != "service.build_time" || fields[1].String != "2026-10-16T08:00:00Z" {
+			t.Errorf("Expected service.build_time, got %s=%s", fields[1].Key, fields[1].String)
+		}
+	})
+
+	t.Run("should mark injected revisions as unmodified", func(t *testing.T) {
+		Revision, BuildTime = "4f2a9c1", ""
+
+		if build := readBuildInfo(); build.Revision != "4f2a9c1" || build.Modified {
+			t.Errorf("Expected injected revision, got %+v", build)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// setupCallerLogger returns an observed logger reporting callers as
+// configured by config
+func setupCallerLogger(config Config) (*Logger, *observer.ObservedLogs) {
+	logger, _ := setupObservedLogger(config)
+	observedCore, observedLogs := observer.New(zapcore.DebugLevel)
+	logger.zap = zap.New(observedCore, logger.zapOptions()...)
+	return logger, observedLogs
+}
+
+// line returns the line number of its caller
+func line() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func TestEnableCaller(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should report the logging call site", func(t *testing.T) {
+		logger, observedLogs := setupCallerLogger(Config{ServiceName: "caller-test", Level: LevelDEBUG, EnableCaller: true})
+
+		tests := []struct {
+			name string
+			log  func() int
+		}{
+			{"Info", func() int { logger.Info(ctx, "message", nil); return line() }},
+			{"Error", func() int { logger.Error(ctx, "message", nil); return line() }},
+			{"Log", func() int { logger.Log(ctx, LevelWARN, TypeRPC, "message", nil); return line() }},
+			{"child logger", func() int { logger.With(LogContext{"a": 1}).Debug(ctx, "message", nil); return line() }},
+			{"security helper", func() int { logger.SecurityLoginFailure(ctx, "alice", "10.0.0.1", nil); return line() }},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				expectedLine := tt.log()
+
+				logs := observedLogs.TakeAll()
+				if len(logs) != 1 {
+					t.Fatalf("Expected 1 entry, got %d", len(logs))
+				}
+				caller := logs[0].Caller
+				if !caller.Defined || filepath.Base(caller.File) != "caller_test.go" || caller.Line != expectedLine {
+					t.Errorf("Expected caller caller_test.go:%d, got %s", expectedLine, caller.TrimmedPath())
+				}
+			})
+		}
+	})
+
+	t.Run("should skip wrapper frames", func(t *testing.T) {
+		logger, observedLogs := setupCallerLogger(Config{ServiceName: "caller-test", EnableCaller: true, CallerSkip: 1})
+		wrapper := func(message string) {
+			logger.Info(ctx, message, nil)
+		}
+
+		wrapper("message")
+		expectedLine := line() - 1
+
+		logs := observedLogs.TakeAll()
+		if len(logs) != 1 || logs[0].Caller.Line != expectedLine {
+			t.Errorf("Expected caller line %d, got %v", expectedLine, logs)
+		}
+	})
+
+	t.Run("should not report callers by default", func(t *testing.T) {
+		logger, observedLogs := setupCallerLogger(Config{ServiceName: "caller-test"})
+		logger.Info(ctx, "message", nil)
+
+		if logs := observedLogs.TakeAll(); logs[0].Caller.Defined {
+			t.Errorf("Expected no caller, got %s", logs[0].Caller.TrimmedPath())
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package logger
+
+import "strings"
+
+// capturedHeader is a request header logged as its own field
+type capturedHeader struct {
+	name  string
+	field string
+}
+
+// newHeaderCapture names the fields of captured headers: header.<name>
+// lowercased with dashes as underscores (header.cf_ray), or
+// http.request.header.<name> with semantic conventions
+func newHeaderCapture(names []string, semconv bool) []capturedHeader {
+	captured := make([]capturedHeader, 0, len(names))
+	for _, name := range names {
+		field := "header." + strings.ReplaceAll(strings.ToLower(name), "-", "_")
+		if semconv {
+			field = "http.request.header." + strings.ToLower(name)
+		}
+		captured = append(captured, capturedHeader{name: name, field: field})
+	}
+	return captured
+}
+
+// captureHeaders adds the captured headers present on a request, redacted
+// like IncludeHeaders
+func captureHeaders(context LogContext, captured []capturedHeader, get func(name string) string, redactor *headerRedactor) {
+	for _, header := range captured {
+		if value := get(header.name); value != "" {
+			context[header.field] = redactor.redact(header.name, value)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCaptureHeaders(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "capture-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	headers := []string{"CF-Ray", "X-Amzn-Trace-Id", "Authorization", "X-Shopify-Shop-Domain"}
+	request := func() *http.Request {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("CF-Ray", "8c1f2a3b4c5d6e7f-AMS")
+		req.Header.Set("X-Amzn-Trace-Id", "Root=1-67891233-abcdef012345678912345678")
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("Accept", "application/json")
+		return req
+	}
+
+	t.Run("fiber", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		app := fiber.New()
+		app.Use(FiberMiddleware(&MiddlewareOptions{CaptureHeaders: headers}))
+		app.Get("/", func(c *fiber.Ctx) error {
+			return c.SendStatus(200)
+		})
+		_, _ = app.Test(request())
+
+		fields := observedLogs.TakeAll()[0].ContextMap()
+		expected := map[string]interface{}{
+			"header.cf_ray":          "8c1f2a3b4c5d6e7f-AMS",
+			"header.x_amzn_trace_id": "Root=1-67891233-abcdef012345678912345678",
+			"header.authorization":   RedactedValue,
+		}
+		for key, value := range expected {
+			if fields[key] != value {
+				t.Errorf("Expected %s=%v, got %v", key, value, fields[key])
+			}
+		}
+		for _, key := range []string{"header.x_shopify_shop_domain", "header.accept", "headers"} {
+			if _, ok := fields[key]; ok {
+				t.Errorf("Expected %s to be omitted", key)
+			}
+		}
+	})
+
+	t.Run("net/http with semantic conventions", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		handler := HTTPMiddleware(&HTTPMiddlewareOptions{CaptureHeaders: headers, SemanticConventions: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), request())
+
+		fields := observedLogs.TakeAll()[0].ContextMap()
+		if fields["http.request.header.cf-ray"] != "8c1f2a3b4c5d6e7f-AMS" {
+			t.Errorf("Expected http.request.header.cf-ray, got %v", fields)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// ErrorClass is the cause of an error, logged as error.class
+type ErrorClass string
+
+const (
+	// ErrorClassClient is a request rejected as invalid (4xx)
+	ErrorClassClient ErrorClass = "client_error"
+	// ErrorClassServer is a failure of the service itself (5xx)
+	ErrorClassServer ErrorClass = "server_error"
+	// ErrorClassDependency is a failure of a database, broker or upstream
+	// service
+	ErrorClassDependency ErrorClass = "dependency_error"
+	// ErrorClassTimeout is a deadline exceeded or a timed out call
+	ErrorClassTimeout ErrorClass = "timeout"
+	// ErrorClassCancelled is work abandoned by its caller
+	ErrorClassCancelled ErrorClass = "cancelled"
+)
+
+// Class returns the error.class field, e.g.
+// Pairs(Class(ErrorClassDependency), KV("error", err)); it takes
+// precedence over Config.ClassifyErrors
+func Class(class ErrorClass) Pair {
+	return Pair{Key: "error.class", Value: string(class)}
+}
+
+// ClassifyError returns the class of err from its cause: timeout for
+// context.DeadlineExceeded and network timeouts, cancelled for
+// context.Canceled, dependency_error for network errors, or "" when unknown
+func ClassifyError(err error) ErrorClass {
+	var netErr net.Error
+	var opErr *net.OpError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCancelled
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	case errors.As(err, &opErr):
+		return ErrorClassDependency
+	}
+	return ""
+}
+
+// classifyError returns the class of a WARN or ERROR entry from its error
+// and status code, or "" when it is already classified or unknown
+func classifyError(fields LogContext) ErrorClass {
+	if _, ok := fields["error.class"]; ok {
+		return ""
+	}
+
+	if err, ok := fields["error"].(error); ok {
+		if class := ClassifyError(err); class != "" {
+			return class
+		}
+	}
+
+	status := statusCode(fields["status_code"])
+	if status == 0 {
+		status = statusCode(fields["http.response.status_code"])
+	}
+	switch {
+	case status == 499:
+		// Client closed the request, as logged by nginx
+		return ErrorClassCancelled
+	case status == 504:
+		return ErrorClassTimeout
+	case status >= 500:
+		return ErrorClassServer
+	case status >= 400:
+		return ErrorClassClient
+	}
+	return ""
+}
+
+// statusCode returns an integer status code field, or 0
+func statusCode(value interface{}) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	}
+	return 0
+}
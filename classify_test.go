@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestClassifyErrors(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "classify-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+		ClassifyErrors: true,
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		level    LogLevel
+		fields   LogContext
+		expected interface{}
+	}{
+		{"deadline", LevelERROR, LogContext{"error": fmt.Errorf("query: %w", context.DeadlineExceeded)}, "timeout"},
+		{"cancelled", LevelWARN, LogContext{"error": context.Canceled}, "cancelled"},
+		{"network", LevelERROR, LogContext{"error": &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}, "dependency_error"},
+		{"client status", LevelWARN, LogContext{"status_code": 404}, "client_error"},
+		{"server status", LevelERROR, LogContext{"status_code": 500}, "server_error"},
+		{"gateway timeout", LevelERROR, LogContext{"http.response.status_code": int64(504)}, "timeout"},
+		{"manual class wins", LevelERROR, Pairs(Class(ErrorClassDependency), KV("status_code", 500)), "dependency_error"},
+		{"unknown", LevelERROR, LogContext{"error": errors.New("boom")}, nil},
+		{"not a failure", LevelINFO, LogContext{"status_code": 404}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observedLogs.TakeAll()
+			logger.Log(ctx, tt.level, TypeNormal, "Request failed", tt.fields)
+
+			if class := observedLogs.TakeAll()[0].ContextMap()["error.class"]; class != tt.expected {
+				t.Errorf("Expected error.class=%v, got %v", tt.expected, class)
+			}
+			if _, ok := tt.fields["error.class"]; ok && tt.name != "manual class wins" {
+				t.Error("Expected the caller's fields to be left unchanged")
+			}
+		})
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected ErrorClass
+	}{
+		"nil":       {nil, ""},
+		"deadline":  {context.DeadlineExceeded, ErrorClassTimeout},
+		"cancelled": {fmt.Errorf("call: %w", context.Canceled), ErrorClassCancelled},
+		"network":   {&net.OpError{Op: "dial", Err: errors.New("refused")}, ErrorClassDependency},
+		"unknown":   {errors.New("boom"), ""},
+	}
+
+	for name, tt := range tests {
+		if class := ClassifyError(tt.err); class != tt.expected {
+			t.Errorf("%s: expected %q, got %q", name, tt.expected, class)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package logger
+
+import "time"
+
+// Clock supplies the current time for @timestamp and measured durations,
+// so tests and simulations can control time, or a monotonic-corrected
+// clock can be used
+type Clock interface {
+	Now() time.Time
+}
+
+// zapClock adapts a Clock to zapcore.Clock
+type zapClock struct {
+	Clock
+}
+
+func (c zapClock) NewTicker(d time.Duration) *time.Ticker {
+	return time.NewTicker(d)
+}
+
+// now returns the current time from the configured Clock
+func (l *Logger) now() time.Time {
+	if l.config.Clock != nil {
+		return l.config.Clock.Now()
+	}
+	return time.Now()
+}
+
+// since returns the time elapsed since start on the configured Clock
+func (l *Logger) since(start time.Time) time.Duration {
+	return l.now().Sub(start)
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fixedClock returns a settable time
+type fixedClock struct {
+	t time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.t
+}
+
+func TestClock(t *testing.T) {
+	clock := &fixedClock{t: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	logger, observedLogs := setupCallerLogger(Config{ServiceName: "clock-test", Level: LevelDEBUG, Clock: clock})
+
+	logger.Info(context.Background(), "tick", nil)
+	if entry := observedLogs.All()[0]; !entry.Time.Equal(clock.t) {
+		t.Errorf("Expected timestamp from the clock, got %s", entry.Time)
+	}
+
+	start := clock.t
+	clock.t = clock.t.Add(1500 * time.Millisecond)
+	if duration := MeasureDuration(start); duration != 1500 {
+		t.Errorf("Expected 1500ms on the clock, got %v", duration)
+	}
+
+	err := InstrumentJob(context.Background(), "clocked", func(ctx context.Context) error {
+		clock.t = clock.t.Add(2 * time.Second)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	finished := observedLogs.FilterMessage("Job clocked finished").All()
+	if len(finished) != 1 || finished[0].ContextMap()["duration_ms"] != int64(2000) {
+		t.Errorf("Expected a 2000ms job duration, got %v", finished)
+	}
+}
+
+func TestZapClockTicker(t *testing.T) {
+	ticker := zapClock{&fixedClock{}}.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+
+	select {
+	case <-ticker.C:
+	case <-time.After(time.Second):
+		t.Error("Expected the ticker to tick")
+	}
+}
+
+func TestClockWindows(t *testing.T) {
+	clock := &fixedClock{t: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:      "clock-window-test",
+		Level:            LevelDEBUG,
+		Clock:            clock,
+		Sampling:         &SamplingOptions{Initial: 1, Thereafter: 100, Tick: time.Minute},
+		ErrorSuppression: &ErrorSuppressionOptions{Initial: 1, Window: time.Minute},
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		logger.Info(ctx, "polling", nil)
+		logger.Error(ctx, "Upstream unavailable", nil)
+	}
+	if sampled, suppressed := observedLogs.FilterMessage("polling").Len(), observedLogs.FilterMessage("Upstream unavailable").Len(); sampled != 1 || suppressed != 1 {
+		t.Fatalf("Expected 1 sampled and 1 suppressed entry within the windows, got %d and %d", sampled, suppressed)
+	}
+	observedLogs.TakeAll()
+
+	// Both windows end on the clock, not after a minute of wall time
+	clock.t = clock.t.Add(time.Minute)
+	logger.Info(ctx, "polling", nil)
+	logger.Error(ctx, "Upstream unavailable", nil)
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 3 {
+		t.Fatalf("Expected the summary and both entries in the next windows, got %d", len(logs))
+	}
+	if logs[1].Message != "Repeated error suppressed" || logs[1].ContextMap()["suppressed_count"] != uint64(2) {
+		t.Errorf("Expected a summary of 2 suppressed entries, got %q %v", logs[1].Message, logs[1].ContextMap())
+	}
+	if logs[2].Message != "Upstream unavailable" {
+		t.Errorf("Expected the error logged in the next window, got %q", logs[2].Message)
+	}
+}
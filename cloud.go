@@ -0,0 +1,231 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CloudProvider identifies a cloud whose instance metadata can be added to
+// entries
+type CloudProvider string
+
+const (
+	CloudAWS   CloudProvider = "aws"
+	CloudGCP   CloudProvider = "gcp"
+	CloudAzure CloudProvider = "azure"
+)
+
+// CloudMetadataOptions configures detection of the cloud instance the
+// service runs on. Metadata is read once at startup and added to every
+// entry as cloud.provider, cloud.region, cloud.availability_zone,
+// cloud.account.id and cloud.instance.id.
+type CloudMetadataOptions struct {
+	// Providers are queried concurrently; the first to answer wins
+	// (default all)
+	Providers []CloudProvider
+	// Timeout bounds detection, which delays Initialize when no provider
+	// answers, e.g. outside the cloud (default 500ms)
+	Timeout time.Duration
+}
+
+// cloudMetadataEndpoint is the link-local metadata address shared by AWS,
+// GCP and Azure
+var cloudMetadataEndpoint = "http://169.254.169.254"
+
+// cloudMetadataClient queries the metadata endpoint directly, ignoring
+// HTTP_PROXY since the address is link-local, and gives up quickly on
+// connecting outside the cloud
+var cloudMetadataClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy:             nil,
+		DialContext:       (&net.Dialer{Timeout: 100 * time.Millisecond}).DialContext,
+		DisableKeepAlives: true,
+	},
+}
+
+// cloudMetadata holds the instance metadata attached to entries
+type cloudMetadata struct {
+	Provider         CloudProvider
+	Region           string
+	AvailabilityZone string
+	AccountID        string
+	InstanceID       string
+}
+
+// fields returns the metadata as cloud.* fields, omitting empty values
+func (m cloudMetadata) fields() []zap.Field {
+	fields := []zap.Field{zap.String("cloud.provider", string(m.Provider))}
+	for _, field := range []struct{ key, value string }{
+		{"cloud.region", m.Region},
+		{"cloud.availability_zone", m.AvailabilityZone},
+		{"cloud.account.id", m.AccountID},
+		{"cloud.instance.id", m.InstanceID},
+	} {
+		if field.value != "" {
+			fields = append(fields, zap.String(field.key, field.value))
+		}
+	}
+	return fields
+}
+
+// detectCloudMetadata queries the configured providers and returns the
+// metadata of the first that answers
+func detectCloudMetadata(opts *CloudMetadataOptions) (cloudMetadata, bool) {
+	providers := opts.Providers
+	if len(providers) == 0 {
+		providers = []CloudProvider{CloudAWS, CloudGCP, CloudAzure}
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 500 * time.Millisecond
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	results := make(chan cloudMetadata, len(providers))
+	var wg sync.WaitGroup
+	for _, provider := range providers {
+		fetch, ok := cloudFetchers[provider]
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if metadata, err := fetch(ctx, cloudMetadataClient); err == nil {
+				results <- metadata
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	metadata, ok := <-results
+	return metadata, ok
+}
+
+// cloudFetchers read the instance metadata of each provider
+var cloudFetchers = map[CloudProvider]func(ctx context.Context, client *http.Client) (cloudMetadata, error){
+	CloudAWS:   fetchAWSMetadata,
+	CloudGCP:   fetchGCPMetadata,
+	CloudAzure: fetchAzureMetadata,
+}
+
+// fetchAWSMetadata reads the EC2 instance identity document using an
+// IMDSv2 session token
+func fetchAWSMetadata(ctx context.Context, client *http.Client) (cloudMetadata, error) {
+	token, err := metadataRequest(ctx, client, http.MethodPut, "/latest/api/token",
+		map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": "60"})
+	if err != nil {
+		return cloudMetadata{}, err
+	}
+
+	body, err := metadataRequest(ctx, client, http.MethodGet, "/latest/dynamic/instance-identity/document",
+		map[string]string{"X-aws-ec2-metadata-token": string(token)})
+	if err != nil {
+		return cloudMetadata{}, err
+	}
+
+	var document struct {
+		InstanceID       string `json:"instanceId"`
+		Region           string `json:"region"`
+		AvailabilityZone string `json:"availabilityZone"`
+		AccountID        string `json:"accountId"`
+	}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return cloudMetadata{}, err
+	}
+	return cloudMetadata{
+		Provider:         CloudAWS,
+		Region:           document.Region,
+		AvailabilityZone: document.AvailabilityZone,
+		AccountID:        document.AccountID,
+		InstanceID:       document.InstanceID,
+	}, nil
+}
+
+// fetchGCPMetadata reads the Compute Engine instance id, zone and project
+func fetchGCPMetadata(ctx context.Context, client *http.Client) (cloudMetadata, error) {
+	headers := map[string]string{"Metadata-Flavor": "Google"}
+	metadata := cloudMetadata{Provider: CloudGCP}
+	for path, value := range map[string]*string{
+		"/computeMetadata/v1/instance/id":        &metadata.InstanceID,
+		"/computeMetadata/v1/instance/zone":      &metadata.AvailabilityZone,
+		"/computeMetadata/v1/project/project-id": &metadata.AccountID,
+	} {
+		body, err := metadataRequest(ctx, client, http.MethodGet, path, headers)
+		if err != nil {
+			return cloudMetadata{}, err
+		}
+		*value = string(body)
+	}
+
+	// The zone is returned as projects/<number>/zones/<zone>
+	metadata.AvailabilityZone = metadata.AvailabilityZone[strings.LastIndex(metadata.AvailabilityZone, "/")+1:]
+	if i := strings.LastIndex(metadata.AvailabilityZone, "-"); i > 0 {
+		metadata.Region = metadata.AvailabilityZone[:i]
+	}
+	return metadata, nil
+}
+
+// fetchAzureMetadata reads the compute section of the Azure Instance
+// Metadata Service
+func fetchAzureMetadata(ctx context.Context, client *http.Client) (cloudMetadata, error) {
+	body, err := metadataRequest(ctx, client, http.MethodGet, "/metadata/instance/compute?api-version=2021-02-01",
+		map[string]string{"Metadata": "true"})
+	if err != nil {
+		return cloudMetadata{}, err
+	}
+
+	var compute struct {
+		VMID           string `json:"vmId"`
+		Location       string `json:"location"`
+		Zone           string `json:"zone"`
+		SubscriptionID string `json:"subscriptionId"`
+	}
+	if err := json.Unmarshal(body, &compute); err != nil {
+		return cloudMetadata{}, err
+	}
+	return cloudMetadata{
+		Provider:         CloudAzure,
+		Region:           compute.Location,
+		AvailabilityZone: compute.Zone,
+		AccountID:        compute.SubscriptionID,
+		InstanceID:       compute.VMID,
+	}, nil
+}
+
+// metadataRequest calls the metadata endpoint and returns the body of a
+// 200 response
+func metadataRequest(ctx context.Context, client *http.Client, method, path string, headers map[string]string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, method, cloudMetadataEndpoint+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("metadata %s: status %d", path, response.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(response.Body, 64<<10))
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDetectCloudMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/latest/api/token" && r.Method == http.MethodPut:
+			_, _ = w.Write([]byte("imds-token"))
+		case r.URL.Path == "/latest/dynamic/instance-identity/document" && r.Header.Get("X-aws-ec2-metadata-token") == "imds-token":
+			_, _ = w.Write([]byte(`{"instanceId":"i-0abc","region":"eu-west-1","availabilityZone":"eu-west-1b","accountId":"123456789012"}`))
+		case r.URL.Path == "/computeMetadata/v1/instance/id" && r.Header.Get("Metadata-Flavor") == "Google":
+			_, _ = w.Write([]byte("4520031799277581759"))
+		case r.URL.Path == "/computeMetadata/v1/instance/zone" && r.Header.Get("Metadata-Flavor") == "Google":
+			_, _ = w.Write([]byte("projects/1234/zones/us-central1-a"))
+		case r.URL.Path == "/computeMetadata/v1/project/project-id" && r.Header.Get("Metadata-Flavor") == "Google":
+			_, _ = w.Write([]byte("shop-prod"))
+		case r.URL.Path == "/metadata/instance/compute" && r.Header.Get("Metadata") == "true":
+			_, _ = w.Write([]byte(`{"vmId":"02aab8a4","location":"westeurope","zone":"2","subscriptionId":"8d10da13"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	endpoint := cloudMetadataEndpoint
+	cloudMetadataEndpoint = server.URL
+	defer func() { cloudMetadataEndpoint = endpoint }()
+
+	tests := []struct {
+		provider CloudProvider
+		expected cloudMetadata
+	}{
+		{CloudAWS, cloudMetadata{CloudAWS, "eu-west-1", "eu-west-1b", "123456789012", "i-0abc"}},
+		{CloudGCP, cloudMetadata{CloudGCP, "us-central1", "us-central1-a", "shop-prod", "4520031799277581759"}},
+		{CloudAzure, cloudMetadata{CloudAzure, "westeurope", "2", "8d10da13", "02aab8a4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.provider), func(t *testing.T) {
+			metadata, ok := detectCloudMetadata(&CloudMetadataOptions{Providers: []CloudProvider{tt.provider}})
+			if !ok {
+				t.Fatal("Expected metadata to be detected")
+			}
+			if metadata != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, metadata)
+			}
+		})
+	}
+
+	t.Run("should bypass proxies", func(t *testing.T) {
+		if transport, ok := cloudMetadataClient.Transport.(*http.Transport); !ok || transport.Proxy != nil {
+			t.Error("Expected the metadata client to ignore proxy settings")
+		}
+	})
+
+	t.Run("should add cloud fields", func(t *testing.T) {
+		fields := tests[0].expected.fields()
+		if len(fields) != 5 || fields[0].Key != "cloud.provider" || fields[0].String != "aws" {
+			t.Errorf("Expected 5 cloud fields, got %v", fields)
+		}
+	})
+
+	t.Run("should report no metadata outside the cloud", func(t *testing.T) {
+		cloudMetadataEndpoint = server.URL + "/none"
+		if metadata, ok := detectCloudMetadata(&CloudMetadataOptions{}); ok {
+			t.Errorf("Expected no metadata, got %+v", metadata)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Code returns the code field identifying an entry independently of its
+// message, e.g. Pairs(Code("ORD-1042"), KV("order_id", id)), so alerts and
+// runbooks keep matching when the message is reworded
+func Code(code string) Pair {
+	return Pair{Key: "code", Value: code}
+}
+
+// MessageCode is a registered entry code with its level and message
+type MessageCode struct {
+	Code    string
+	Level   LogLevel
+	Message string
+}
+
+// registeredCodes holds the codes defined with RegisterMessageCode
+var registeredCodes = struct {
+	sync.RWMutex
+	codes map[string]MessageCode
+}{codes: map[string]MessageCode{}}
+
+// RegisterMessageCode defines an entry code (e.g. "ORD-1042") with its
+// level and message for use with Coded. Registering a code again replaces
+// its level and message.
+func RegisterMessageCode(code string, level LogLevel, message string) MessageCode {
+	messageCode := MessageCode{Code: code, Level: level, Message: message}
+
+	registeredCodes.Lock()
+	defer registeredCodes.Unlock()
+	registeredCodes.codes[code] = messageCode
+	return messageCode
+}
+
+// MessageCodes returns the registered codes sorted by code, e.g. to
+// generate a runbook index
+func MessageCodes() []MessageCode {
+	registeredCodes.RLock()
+	defer registeredCodes.RUnlock()
+
+	codes := make([]MessageCode, 0, len(registeredCodes.codes))
+	for _, code := range registeredCodes.codes {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i].Code < codes[j].Code })
+	return codes
+}
+
+// Coded logs the message of a registered code at its level with the code
+// field; an empty level logs at INFO. The log type follows the level, as
+// with Debug, Info, Warn and Error.
+func (l *Logger) Coded(ctx context.Context, code MessageCode, context LogContext) {
+	level := code.Level
+	if level == "" {
+		level = LevelINFO
+	}
+
+	fields := make(LogContext, len(context)+1)
+	for key, value := range context {
+		fields[key] = value
+	}
+	fields["code"] = code.Code
+
+	l.log(ctx, 0, level, levelType(level), code.Message, fields)
+}
+
+// levelType returns the log type of the level's logging method
+func levelType(level LogLevel) LogType {
+	switch level {
+	case LevelERROR:
+		return TypeError
+	case LevelDEBUG:
+		return TypeDebug
+	default:
+		return TypeNormal
+	}
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestMessageCodes(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "code-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+	ctx := context.Background()
+
+	t.Run("should add the code field", func(t *testing.T) {
+		observedLogs.TakeAll()
+		logger.Info(ctx, "Order rejected", Pairs(Code("ORD-1042"), KV("order_id", "123")))
+
+		if code := observedLogs.TakeAll()[0].ContextMap()["code"]; code != "ORD-1042" {
+			t.Errorf("Expected code=ORD-1042, got %v", code)
+		}
+	})
+
+	t.Run("should log registered codes", func(t *testing.T) {
+		observedLogs.TakeAll()
+		rejected := RegisterMessageCode("ORD-1043", LevelWARN, "Order rejected by fraud check")
+		context := LogContext{"order_id": "123"}
+		logger.Coded(ctx, rejected, context)
+
+		logs := observedLogs.TakeAll()
+		if len(logs) != 1 {
+			t.Fatalf("Expected 1 log entry, got %d", len(logs))
+		}
+		if logs[0].Message != "Order rejected by fraud check" || logs[0].Level != zapcore.WarnLevel {
+			t.Errorf("Unexpected entry %q at %v", logs[0].Message, logs[0].Level)
+		}
+		if fields := logs[0].ContextMap(); fields["code"] != "ORD-1043" || fields["order_id"] != "123" {
+			t.Errorf("Unexpected fields %v", fields)
+		}
+		if _, ok := context["code"]; ok {
+			t.Error("Expected the caller's context to be left unchanged")
+		}
+	})
+
+	t.Run("should type registered codes by level", func(t *testing.T) {
+		tests := map[LogLevel]LogType{
+			LevelDEBUG: TypeDebug,
+			LevelINFO:  TypeNormal,
+			LevelWARN:  TypeNormal,
+			LevelERROR: TypeError,
+		}
+		for level, want := range tests {
+			observedLogs.TakeAll()
+			logger.Coded(ctx, RegisterMessageCode("ORD-2000", level, "Order check"), nil)
+
+			if logType := observedLogs.TakeAll()[0].ContextMap()["log_type"]; logType != string(want) {
+				t.Errorf("Expected log_type=%s at %s, got %v", want, level, logType)
+			}
+		}
+	})
+
+	t.Run("should list registered codes", func(t *testing.T) {
+		RegisterMessageCode("ORD-1041", "", "Order created")
+		RegisterMessageCode("ORD-1041", LevelINFO, "Order placed")
+
+		var found []MessageCode
+		for _, code := range MessageCodes() {
+			if code.Code == "ORD-1041" || code.Code == "ORD-1043" {
+				found = append(found, code)
+			}
+		}
+		if len(found) != 2 || found[0].Message != "Order placed" || found[1].Code != "ORD-1043" {
+			t.Errorf("Expected sorted codes with the latest registration, got %v", found)
+		}
+	})
+}
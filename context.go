@@ -0,0 +1,35 @@
+package logger
+
+import "context"
+
+// contextFieldsKey is the context key for request-scoped log fields
+type contextFieldsKey struct{}
+
+// WithContextFields returns a copy of ctx carrying fields that are added to
+// every entry logged with it. Fields already on ctx are kept unless overridden.
+func WithContextFields(ctx context.Context, fields LogContext) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	existing := ContextFields(ctx)
+	merged := make(LogContext, len(existing)+len(fields))
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+// ContextFields returns the request-scoped fields carried by ctx
+func ContextFields(ctx context.Context) LogContext {
+	if ctx == nil {
+		return nil
+	}
+
+	fields, _ := ctx.Value(contextFieldsKey{}).(LogContext)
+	return fields
+}
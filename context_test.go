@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithContextFields(t *testing.T) {
+	t.Run("should merge fields across calls", func(t *testing.T) {
+		ctx := WithContextFields(context.Background(), LogContext{"tenant_id": "acme"})
+		ctx = WithContextFields(ctx, LogContext{"request_id": "req-1"})
+
+		fields := ContextFields(ctx)
+		if fields["tenant_id"] != "acme" || fields["request_id"] != "req-1" {
+			t.Errorf("Expected merged fields, got %v", fields)
+		}
+	})
+
+	t.Run("should not mutate parent context fields", func(t *testing.T) {
+		parent := WithContextFields(context.Background(), LogContext{"tenant_id": "acme"})
+		_ = WithContextFields(parent, LogContext{"tenant_id": "globex"})
+
+		if ContextFields(parent)["tenant_id"] != "acme" {
+			t.Error("Expected parent context to keep its fields")
+		}
+	})
+
+	t.Run("should handle nil context", func(t *testing.T) {
+		if fields := ContextFields(nil); fields != nil {
+			t.Errorf("Expected nil fields, got %v", fields)
+		}
+
+		//nolint:staticcheck // nil context is handled explicitly
+		ctx := WithContextFields(nil, LogContext{"a": 1})
+		if ContextFields(ctx)["a"] != 1 {
+			t.Error("Expected fields on a fresh context")
+		}
+	})
+}
+
+func TestContextFieldsInEntries(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "context-fields-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	ctx := WithContextFields(context.Background(), LogContext{
+		"tenant_id": "acme",
+		"region":    "eu",
+	})
+	logger.Info(ctx, "Order placed", Fields("region", "us"))
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(logs))
+	}
+
+	if fields := logs[0].ContextMap(); fields["tenant_id"] != "acme" {
+		t.Errorf("Expected tenant_id from context, got %v", fields["tenant_id"])
+	}
+
+	regions := 0
+	for _, field := range logs[0].Context {
+		if field.Key == "region" {
+			regions++
+			if field.String != "us" {
+				t.Errorf("Expected explicit field to win, got %s", field.String)
+			}
+		}
+	}
+	if regions != 1 {
+		t.Errorf("Expected a single region field, got %d", regions)
+	}
+}
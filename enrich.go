@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"net"
+	"strings"
+)
+
+// Enrichment adds fields derived from the client IP of an entry, e.g. its
+// location (see the loggeoip package)
+type Enrichment interface {
+	// Enrich returns the fields to add for ip, or nil
+	Enrich(ip net.IP) LogContext
+}
+
+// EnrichmentFunc adapts a function to the Enrichment interface
+type EnrichmentFunc func(ip net.IP) LogContext
+
+// Enrich calls f(ip)
+func (f EnrichmentFunc) Enrich(ip net.IP) LogContext {
+	return f(ip)
+}
+
+// clientIPKeys are the fields holding the client IP, as logged by the HTTP
+// middlewares and security helpers
+var clientIPKeys = []string{"ip", "client.address"}
+
+// enrich adds the Enrichment fields for the client IP of an entry.
+// Private and loopback addresses are skipped, and fields already logged
+// are kept.
+func enrich(enrichment Enrichment, fields LogContext) {
+	ip := clientIP(fields)
+	if ip == nil || ip.IsPrivate() || ip.IsLoopback() || ip.IsUnspecified() {
+		return
+	}
+
+	for key, value := range enrichment.Enrich(ip) {
+		if _, exists := fields[key]; !exists {
+			fields[key] = value
+		}
+	}
+}
+
+// clientIP parses the client IP field of an entry, which may carry a port
+// (e.g. a net/http RemoteAddr)
+func clientIP(fields LogContext) net.IP {
+	for _, key := range clientIPKeys {
+		value, ok := fields[key].(string)
+		if !ok || value == "" {
+			continue
+		}
+		if host, _, err := net.SplitHostPort(value); err == nil {
+			value = host
+		}
+		return net.ParseIP(strings.TrimSpace(value))
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestEnrichment(t *testing.T) {
+	var looked []string
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "enrich-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+		Enrichment: EnrichmentFunc(func(ip net.IP) LogContext {
+			looked = append(looked, ip.String())
+			return LogContext{"geo.country": "NL", "ip": "overwritten"}
+		}),
+	})
+
+	ctx := context.Background()
+	logger.HTTP(ctx, "GET /", LogContext{"ip": "198.51.100.4:51234"})
+	logger.Info(ctx, "semconv", LogContext{"client.address": "2001:db8::1"})
+	logger.Info(ctx, "private", LogContext{"ip": "10.0.0.8"})
+	logger.Info(ctx, "no ip", nil)
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 4 {
+		t.Fatalf("Expected 4 log entries, got %d", len(logs))
+	}
+	fields := logs[0].ContextMap()
+	if fields["geo.country"] != "NL" || fields["ip"] != "198.51.100.4:51234" {
+		t.Errorf("Expected enrichment without overwriting logged fields, got %v", fields)
+	}
+	if logs[1].ContextMap()["geo.country"] != "NL" {
+		t.Errorf("Expected client.address to be enriched, got %v", logs[1].ContextMap())
+	}
+	if _, ok := logs[2].ContextMap()["geo.country"]; ok {
+		t.Error("Expected private addresses to be skipped")
+	}
+	if len(looked) != 2 || looked[0] != "198.51.100.4" || looked[1] != "2001:db8::1" {
+		t.Errorf("Unexpected lookups: %v", looked)
+	}
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// exit terminates the process after Fatal, replaced in tests
+var exit = os.Exit
+
+// FatalHook runs before the process exits on Fatal, e.g. to flush sinks,
+// fire alerts or dump a debug trail. ctx expires at Config.FatalTimeout.
+type FatalHook func(ctx context.Context, entry Entry)
+
+// fatalHooks holds the fatal hooks shared by a logger and its children
+type fatalHooks struct {
+	mu    sync.RWMutex
+	hooks []FatalHook
+}
+
+// OnFatal adds a hook that runs before Fatal exits the process, including
+// on Fatal calls from child loggers
+func (l *Logger) OnFatal(hook FatalHook) {
+	if l.fatalHooks == nil {
+		l.fatalHooks = &fatalHooks{}
+	}
+
+	l.fatalHooks.mu.Lock()
+	defer l.fatalHooks.mu.Unlock()
+	l.fatalHooks.hooks = append(l.fatalHooks.hooks, hook)
+}
+
+// list returns the registered hooks
+func (f *fatalHooks) list() []FatalHook {
+	if f == nil {
+		return nil
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.hooks
+}
+
+// Fatal logs an error entry marked fatal, runs the OnFatal hooks, flushes
+// the logger like Shutdown and exits the process with status 1. Hooks and
+// flushing together are bounded by Config.FatalTimeout (default 5s), so a
+// hanging hook cannot keep the process alive.
+func (l *Logger) Fatal(ctx context.Context, message string, context LogContext) {
+	fields := make(LogContext, len(context)+1)
+	for key, value := range context {
+		fields[key] = value
+	}
+	fields["fatal"] = true
+
+	// Fatal entries are written whatever the configured level
+	l.withDebug().log(ctx, 0, LevelERROR, TypeError, message, fields)
+	l.exitFatal(Entry{
+		Context: ctx,
+		Level:   LevelERROR,
+		Type:    TypeError,
+		Message: message,
+		Fields:  l.mergeFields(ctx, fields),
+	})
+}
+
+// exitFatal runs the fatal hooks and flushes the logger within the fatal
+// timeout, then exits
+func (l *Logger) exitFatal(entry Entry) {
+	timeout := l.config.FatalTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, hook := range l.fatalHooks.list() {
+			hook(ctx, entry)
+		}
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	_ = l.Shutdown(ctx, "fatal: "+entry.Message)
+	exit(1)
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFatal(t *testing.T) {
+	original := exit
+	defer func() { exit = original }()
+
+	t.Run("should log, run hooks and exit", func(t *testing.T) {
+		logger, observedLogs := setupObservedLogger(Config{
+			ServiceName:    "fatal-test",
+			ServiceVersion: "1.0.0",
+			Env:            "test",
+			Level:          LevelOFF,
+		})
+		exitCode := -1
+		exit = func(code int) { exitCode = code }
+
+		var hooked []Entry
+		logger.OnFatal(func(ctx context.Context, entry Entry) {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("Expected hook context to have a deadline")
+			}
+			hooked = append(hooked, entry)
+		})
+
+		logger.With(LogContext{"component": "db"}).Fatal(context.Background(), "Database unreachable", LogContext{"host": "db-1"})
+
+		if exitCode != 1 {
+			t.Errorf("Expected exit code 1, got %d", exitCode)
+		}
+		if len(hooked) != 1 || hooked[0].Message != "Database unreachable" || hooked[0].Fields["component"] != "db" {
+			t.Errorf("Expected hook to receive the fatal entry, got %v", hooked)
+		}
+
+		logs := observedLogs.FilterMessage("Database unreachable").All()
+		if len(logs) != 1 {
+			t.Fatalf("Expected fatal entry despite LevelOFF, got %d", len(logs))
+		}
+		if logs[0].ContextMap()["fatal"] != true || logs[0].ContextMap()["host"] != "db-1" {
+			t.Errorf("Expected fatal marker and fields, got %v", logs[0].ContextMap())
+		}
+		if observedLogs.FilterMessage("Logger shutting down").Len() != 1 {
+			t.Error("Expected logger to be shut down before exiting")
+		}
+	})
+
+	t.Run("should not wait for hanging hooks beyond the timeout", func(t *testing.T) {
+		logger, _ := setupObservedLogger(Config{
+			ServiceName:  "fatal-test",
+			FatalTimeout: 20 * time.Millisecond,
+		})
+		exited := false
+		exit = func(code int) { exited = true }
+
+		release := make(chan struct{})
+		defer close(release)
+		logger.OnFatal(func(ctx context.Context, entry Entry) {
+			<-release
+		})
+
+		startTime := time.Now()
+		logger.Fatal(context.Background(), "Out of disk", nil)
+
+		if !exited {
+			t.Error("Expected process to exit")
+		}
+		if elapsed := time.Since(startTime); elapsed > time.Second {
+			t.Errorf("Expected exit after the fatal timeout, took %s", elapsed)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"runtime"
+)
+
+// Variable parts of error messages, replaced so that occurrences of the
+// same error share a fingerprint
+var (
+	fingerprintUUID   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	fingerprintHex    = regexp.MustCompile(`0x[0-9a-fA-F]+|\b[0-9a-fA-F]{16,}\b`)
+	fingerprintQuoted = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+	fingerprintNumber = regexp.MustCompile(`\d+`)
+)
+
+// normalizeErrorMessage replaces IDs, quoted values and numbers in an
+// error message with placeholders
+func normalizeErrorMessage(message string) string {
+	message = fingerprintUUID.ReplaceAllString(message, "<uuid>")
+	message = fingerprintHex.ReplaceAllString(message, "<hex>")
+	message = fingerprintQuoted.ReplaceAllString(message, "<str>")
+	return fingerprintNumber.ReplaceAllString(message, "<n>")
+}
+
+// errorFingerprint hashes the error type, the normalized error message
+// (or the entry message without one) and the function logging the entry
+// into a short stable ID. Recovered panics, which are all logged from the
+// same place, keep the panic_fingerprint of their type and stack. It is
+// called with the context of an ERROR entry before normalizeError; skip
+// counts the frames between the caller of the public method and log.
+func errorFingerprint(skip int, message string, context LogContext) string {
+	if panicFingerprint, ok := context["panic_fingerprint"].(string); ok && panicFingerprint != "" {
+		return panicFingerprint
+	}
+
+	errorType, errorMessage := context["error_type"], context["error_message"]
+	if err, ok := context["error"].(error); ok {
+		errorType, errorMessage = fmt.Sprintf("%T", err), err.Error()
+	}
+	if errorMessage == nil {
+		errorMessage = message
+	}
+
+	// Frames: errorFingerprint, log, the public method and its caller
+	var function string
+	if pc, _, _, ok := runtime.Caller(3 + skip); ok {
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			function = fn.Name()
+		}
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprint(errorType, "\x00", normalizeErrorMessage(fmt.Sprint(errorMessage)), "\x00", function)))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+)
+
+func TestNormalizeErrorMessage(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected string
+	}{
+		{"dial tcp 10.0.0.1:5432: connection refused", "dial tcp <n>.<n>.<n>.<n>:<n>: connection refused"},
+		{`order "A-17" not found`, "order <str> not found"},
+		{"user 3fa85f64-5717-4562-b3fc-2c963f66afa6 locked", "user <uuid> locked"},
+		{"bad pointer 0xc000123abc", "bad pointer <hex>"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeErrorMessage(tt.message); got != tt.expected {
+			t.Errorf("normalizeErrorMessage(%q) = %q, want %q", tt.message, got, tt.expected)
+		}
+	}
+}
+
+func TestErrorFingerprint(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:      "fingerprint-test",
+		ServiceVersion:   "1.0.0",
+		Env:              "test",
+		Level:            LevelDEBUG,
+		ErrorFingerprint: true,
+	})
+	ctx := context.Background()
+
+	logOrderError := func(err error) string {
+		logger.Error(ctx, "Order lookup failed", LogContext{"error": err})
+		return observedLogs.TakeAll()[0].ContextMap()["error.fingerprint"].(string)
+	}
+	logOtherError := func(err error) string {
+		logger.Error(ctx, "Order lookup failed", LogContext{"error": err})
+		return observedLogs.TakeAll()[0].ContextMap()["error.fingerprint"].(string)
+	}
+
+	first := logOrderError(fmt.Errorf("order %d not found", 17))
+	if len(first) != 16 {
+		t.Fatalf("Expected a 16 character fingerprint, got %q", first)
+	}
+	if second := logOrderError(fmt.Errorf("order %d not found", 42)); second != first {
+		t.Errorf("Expected the same fingerprint for another order ID, got %q and %q", first, second)
+	}
+	if other := logOrderError(fs.ErrNotExist); other == first {
+		t.Error("Expected another fingerprint for another error")
+	}
+	if other := logOtherError(fmt.Errorf("order %d not found", 17)); other == first {
+		t.Error("Expected another fingerprint for another logging function")
+	}
+
+	t.Run("should not change the caller's fields", func(t *testing.T) {
+		context := LogContext{"error_message": "timeout"}
+		logger.Error(ctx, "Export failed", context)
+		logger.Error(ctx, "Export failed", nil)
+
+		if _, ok := context["error.fingerprint"]; ok {
+			t.Error("Expected error.fingerprint only on the entry")
+		}
+		for _, entry := range observedLogs.TakeAll() {
+			if _, ok := entry.ContextMap()["error.fingerprint"]; !ok {
+				t.Errorf("Expected error.fingerprint on %q", entry.Message)
+			}
+		}
+	})
+
+	t.Run("should not fingerprint other levels", func(t *testing.T) {
+		logger.Warn(ctx, "Retrying", LogContext{"error": errors.New("timeout")})
+		if _, ok := observedLogs.TakeAll()[0].ContextMap()["error.fingerprint"]; ok {
+			t.Error("Expected no error.fingerprint on WARN entries")
+		}
+	})
+}
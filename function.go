@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// functionCore adds the calling function and its package to every entry.
+// It wraps all other cores, so audit hashes cover the added fields.
+type functionCore struct {
+	zapcore.Core
+	// caller keeps the caller field, which is only computed for the
+	// function name when EnableCaller is unset
+	caller bool
+}
+
+func (c *functionCore) With(fields []zapcore.Field) zapcore.Core {
+	return &functionCore{Core: c.Core.With(fields), caller: c.caller}
+}
+
+func (c *functionCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *functionCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if entry.Caller.Defined && entry.Caller.Function != "" {
+		pkg, function := splitFunction(entry.Caller.Function)
+		fields = append(fields, zap.String("function", function), zap.String("package", pkg))
+	}
+	if !c.caller {
+		entry.Caller = zapcore.EntryCaller{}
+	}
+	return c.Core.Write(entry, fields)
+}
+
+// splitFunction splits a fully qualified function name such as
+// github.com/rcommerz/shop/orders.(*Service).Create into its package
+// path and the function name within the package. The runtime escapes
+// dots in the last path element (gopkg.in/yaml%2ev3), which is undone.
+func splitFunction(qualified string) (string, string) {
+	slash := strings.LastIndex(qualified, "/")
+	dot := strings.Index(qualified[slash+1:], ".")
+	if dot < 0 {
+		return "", qualified
+	}
+	dot += slash + 1
+	return strings.ReplaceAll(qualified[:dot], "%2e", "."), qualified[dot+1:]
+}
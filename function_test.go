@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestEnableFunction(t *testing.T) {
+	logger, _ := setupObservedLogger(Config{ServiceName: "function-test", EnableFunction: true})
+	observedCore, observedLogs := observer.New(zapcore.DebugLevel)
+	logger.zap = zap.New(&functionCore{Core: observedCore}, logger.zapOptions()...)
+
+	logger.Info(context.Background(), "Order created", nil)
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(logs))
+	}
+	fields := logs[0].ContextMap()
+	if fields["function"] != "TestEnableFunction" || fields["package"] != "github.com/rcommerz/logger-go" {
+		t.Errorf("Expected calling function and package, got %v %v", fields["function"], fields["package"])
+	}
+	if logs[0].Caller.Defined {
+		t.Errorf("Expected no caller unless EnableCaller is set, got %s", logs[0].Caller.TrimmedPath())
+	}
+}
+
+func TestSplitFunction(t *testing.T) {
+	tests := []struct {
+		qualified string
+		pkg       string
+		function  string
+	}{
+		{"github.com/rcommerz/shop/orders.(*Service).Create", "github.com/rcommerz/shop/orders", "(*Service).Create"},
+		{"github.com/rcommerz/shop/orders.Handler.func1", "github.com/rcommerz/shop/orders", "Handler.func1"},
+		{"main.main", "main", "main"},
+		{"gopkg.in/yaml%2ev3.Unmarshal", "gopkg.in/yaml.v3", "Unmarshal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.qualified, func(t *testing.T) {
+			pkg, function := splitFunction(tt.qualified)
+			if pkg != tt.pkg || function != tt.function {
+				t.Errorf("Expected %s %s, got %s %s", tt.pkg, tt.function, pkg, function)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// HeartbeatOptions configures a periodic "alive" entry, so a gap in the
+// log pipeline can be told apart from a crashed service
+type HeartbeatOptions struct {
+	// Interval between heartbeats (default 1 minute)
+	Interval time.Duration
+}
+
+// heartbeatMessage is the message of heartbeat entries, which are not
+// counted in the entries they report
+const heartbeatMessage = "alive"
+
+// heartbeat counts written entries and periodically logs them
+type heartbeat struct {
+	interval time.Duration
+	started  time.Time
+	total    atomic.Int64
+	errors   atomic.Int64
+	stopOnce sync.Once
+	stopped  chan struct{}
+	done     chan struct{}
+}
+
+func newHeartbeat(opts *HeartbeatOptions) *heartbeat {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &heartbeat{
+		interval: interval,
+		started:  time.Now(),
+		stopped:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// record counts an entry written at level
+func (h *heartbeat) record(level zapcore.Level) {
+	h.total.Add(1)
+	if level >= zapcore.ErrorLevel {
+		h.errors.Add(1)
+	}
+}
+
+// run logs a heartbeat every interval until stop is called. Heartbeats
+// are written regardless of the configured Level.
+func (h *heartbeat) run(l *Logger) {
+	defer close(h.done)
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	out := l.withDebug()
+	var lastTotal, lastErrors int64
+	for {
+		select {
+		case <-h.stopped:
+			return
+		case <-ticker.C:
+			total, errors := h.total.Load(), h.errors.Load()
+			out.Log(context.Background(), LevelINFO, TypeNormal, heartbeatMessage, LogContext{
+				"uptime_ms":             time.Since(h.started).Milliseconds(),
+				"entries.total":         total,
+				"entries.interval":      total - lastTotal,
+				"entries.errors":        errors - lastErrors,
+				"entries.per_second":    float64(total-lastTotal) / h.interval.Seconds(),
+				"heartbeat.interval_ms": h.interval.Milliseconds(),
+			})
+			lastTotal, lastErrors = total, errors
+		}
+	}
+}
+
+// stop ends the heartbeat goroutine and waits for it to exit
+func (h *heartbeat) stop() {
+	h.stopOnce.Do(func() { close(h.stopped) })
+	<-h.done
+}
+
+// heartbeatCore counts entries written by the wrapped core for heartbeats
+type heartbeatCore struct {
+	zapcore.Core
+	heartbeat *heartbeat
+}
+
+func (c *heartbeatCore) With(fields []zapcore.Field) zapcore.Core {
+	return &heartbeatCore{Core: c.Core.With(fields), heartbeat: c.heartbeat}
+}
+
+func (c *heartbeatCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *heartbeatCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if err := c.Core.Write(entry, fields); err != nil {
+		return err
+	}
+	if entry.Message != heartbeatMessage {
+		c.heartbeat.record(entry.Level)
+	}
+	return nil
+}
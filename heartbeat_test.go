@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestHeartbeat(t *testing.T) {
+	logger, _ := setupObservedLogger(Config{
+		ServiceName:    "heartbeat-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelERROR,
+	})
+	heartbeat := newHeartbeat(&HeartbeatOptions{Interval: 20 * time.Millisecond})
+	observedCore, observedLogs := observer.New(zapcore.ErrorLevel)
+	logger.zap = zap.New(&heartbeatCore{Core: observedCore, heartbeat: heartbeat})
+
+	ctx := context.Background()
+	logger.Error(ctx, "Payment failed", nil)
+	logger.Error(ctx, "Payment failed", nil)
+	logger.Info(ctx, "Below level", nil)
+
+	go heartbeat.run(logger)
+	deadline := time.Now().Add(time.Second)
+	for observedLogs.FilterMessage(heartbeatMessage).Len() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	heartbeat.stop()
+
+	beats := observedLogs.FilterMessage(heartbeatMessage).All()
+	if len(beats) < 2 {
+		t.Fatalf("Expected at least 2 heartbeats despite the ERROR level, got %d", len(beats))
+	}
+
+	first, second := beats[0].ContextMap(), beats[1].ContextMap()
+	if first["entries.total"] != int64(2) || first["entries.interval"] != int64(2) || first["entries.errors"] != int64(2) {
+		t.Errorf("Expected 2 written errors in the first heartbeat, got %v", first)
+	}
+	if second["entries.total"] != int64(2) || second["entries.interval"] != int64(0) {
+		t.Errorf("Expected heartbeats not to be counted, got %v", second)
+	}
+	if uptime, ok := first["uptime_ms"].(int64); !ok || uptime < 20 {
+		t.Errorf("Expected uptime_ms of at least one interval, got %v", first["uptime_ms"])
+	}
+}
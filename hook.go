@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"context"
+	"sync"
+)
+
+// Entry is a log entry as seen by hooks, before it is encoded
+type Entry struct {
+	// Context is the context passed to the logging call
+	Context context.Context
+	Level   LogLevel
+	Type    LogType
+	Message string
+	// Fields holds the entry's fields, including request-scoped and child
+	// logger fields. Trace IDs are added after hooks run.
+	Fields LogContext
+	// Drop discards the entry when set by a hook
+	Drop bool
+}
+
+// Hook processes every entry before it is encoded. Hooks can enrich,
+// mutate or drop (by setting Drop) entries, and run in registration order.
+type Hook func(entry Entry) Entry
+
+// hookChain holds the hooks shared by a logger and its children
+type hookChain struct {
+	mu    sync.RWMutex
+	hooks []Hook
+}
+
+// RegisterHook adds a hook that every entry passes through before it is
+// encoded, including entries from child loggers
+func (l *Logger) RegisterHook(hook Hook) {
+	if l.hooks == nil {
+		l.hooks = &hookChain{}
+	}
+
+	l.hooks.mu.Lock()
+	defer l.hooks.mu.Unlock()
+	l.hooks.hooks = append(l.hooks.hooks, hook)
+}
+
+// list returns the registered hooks
+func (c *hookChain) list() []Hook {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.hooks
+}
+
+// runHooks passes an entry through hooks, reporting false if one dropped it
+func runHooks(hooks []Hook, entry Entry) (Entry, bool) {
+	for _, hook := range hooks {
+		entry = hook(entry)
+		if entry.Drop {
+			return entry, false
+		}
+	}
+	return entry, true
+}
+
+// mergeFields combines request-scoped, child logger and explicit fields
+// with the same precedence as appendFields
+func (l *Logger) mergeFields(ctx context.Context, context LogContext) LogContext {
+	scoped := ContextFields(ctx)
+	merged := make(LogContext, len(l.fields)+len(scoped)+len(context))
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	for key, value := range scoped {
+		merged[key] = value
+	}
+	for key, value := range context {
+		merged[key] = value
+	}
+	return merged
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRegisterHook(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "hook-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	var seen []Entry
+	logger.RegisterHook(func(entry Entry) Entry {
+		seen = append(seen, entry)
+		entry.Fields["region"] = "eu-west-1"
+		return entry
+	})
+	logger.RegisterHook(func(entry Entry) Entry {
+		if entry.Fields["email"] != nil {
+			delete(entry.Fields, "email")
+		}
+		if entry.Message == "noisy" {
+			entry.Drop = true
+		}
+		if entry.Type == TypeSecurity {
+			entry.Level = LevelERROR
+		}
+		return entry
+	})
+
+	child := logger.With(LogContext{"component": "billing"})
+	ctx := WithContextFields(context.Background(), LogContext{"request_id": "req-1"})
+
+	child.Info(ctx, "charged", LogContext{"email": "a@example.com"})
+	logger.Info(ctx, "noisy", nil)
+	logger.Security(ctx, "blocked", nil)
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 2 {
+		t.Fatalf("Expected 2 log entries, got %d", len(logs))
+	}
+
+	fields := logs[0].ContextMap()
+	if fields["region"] != "eu-west-1" {
+		t.Errorf("Expected hook to add region, got %v", fields["region"])
+	}
+	if _, ok := fields["email"]; ok {
+		t.Error("Expected hook to remove email")
+	}
+	if fields["component"] != "billing" || fields["request_id"] != "req-1" || fields["log_type"] != "normal" {
+		t.Errorf("Expected child, scoped and log_type fields to be kept, got %v", fields)
+	}
+
+	if logs[1].Level != zapcore.ErrorLevel || logs[1].ContextMap()["log_type"] != "security" {
+		t.Errorf("Expected hook to raise security entry to ERROR, got %v %v", logs[1].Level, logs[1].ContextMap()["log_type"])
+	}
+
+	if len(seen) != 3 || seen[0].Fields["request_id"] != "req-1" || seen[0].Context != ctx {
+		t.Errorf("Expected hooks to see merged fields and context, got %v", seen)
+	}
+}
+
+func TestRegisterHookSkipsDisabledLevels(t *testing.T) {
+	logger, _ := setupObservedLogger(Config{
+		ServiceName:    "hook-level-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelINFO,
+	})
+	logger.zap = logger.zap.WithOptions(zap.IncreaseLevel(zapcore.InfoLevel))
+
+	calls := 0
+	logger.RegisterHook(func(entry Entry) Entry {
+		calls++
+		return entry
+	})
+
+	logger.Debug(context.Background(), "filtered", nil)
+	if calls != 0 {
+		t.Errorf("Expected hooks not to run for disabled levels, got %d calls", calls)
+	}
+}
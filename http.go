@@ -0,0 +1,275 @@
+package logger
+
+import (
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// HTTPMiddlewareOptions configures the net/http logging middleware
+type HTTPMiddlewareOptions struct {
+	// Logger, when set, logs requests instead of the singleton
+	Logger *Logger
+
+	// ExcludePaths lists paths or path globs that are not logged
+	ExcludePaths []string
+	// ExcludePatterns excludes paths matching any of these regular expressions
+	ExcludePatterns []*regexp.Regexp
+	// ExcludeMethods excludes requests by HTTP method
+	ExcludeMethods []string
+	// Skip, when set, excludes requests for which it returns true
+	Skip func(r *http.Request) bool
+
+	IncludeHeaders bool
+	// LogRequestStart also logs a "METHOD path started" entry with
+	// request_phase=started when a request arrives; the completion entry
+	// then carries request_phase=completed
+	LogRequestStart bool
+	// ProgressInterval, when set, logs a "METHOD path in progress" entry
+	// with elapsed_ms, response_bytes and request_phase=in_progress every
+	// interval while a request is served, e.g. for server-sent events and
+	// long polls
+	ProgressInterval time.Duration
+
+	// MessageTemplate formats the entry message with text/template over an
+	// HTTPExchange (default "METHOD path status")
+	MessageTemplate string
+	// MessageFormatter, when set, formats the entry message instead of
+	// MessageTemplate
+	MessageFormatter HTTPMessageFunc
+
+	// CaptureHeaders logs these request headers individually as
+	// header.<name> fields (e.g. CF-Ray → header.cf_ray)
+	CaptureHeaders []string
+
+	// RouteFunc resolves the matched route pattern (e.g. /users/{id}) once the
+	// request has been served. Defaults to the pattern set by http.ServeMux.
+	RouteFunc func(r *http.Request) string
+
+	// SemanticConventions emits OpenTelemetry HTTP field names instead of the defaults
+	SemanticConventions bool
+
+	// RedactHeaders adds header names to the default redaction list
+	RedactHeaders []string
+	// HashRedactedHeaders replaces redacted header values with a short SHA-256 digest
+	HashRedactedHeaders bool
+	// RedactQueryParams adds query parameter names to the default redaction list
+	RedactQueryParams []string
+
+	// AccessLog, when set, also writes every logged request as a combined
+	// log format line
+	AccessLog *AccessLogOptions
+
+	// ParseUserAgent adds the browser, OS and device class parsed from the
+	// User-Agent header as user_agent.* fields
+	ParseUserAgent bool
+
+	// TrustedProxies lists the IPs and CIDRs of proxies whose
+	// X-Forwarded-For and X-Real-IP headers are believed (default: none)
+	TrustedProxies []string
+	// LogForwardingChain adds the X-Forwarded-For addresses followed by the
+	// connection's peer as forwarded_for
+	LogForwardingChain bool
+
+	// IncludeTLS adds http.version and the TLS version, cipher and client
+	// certificate fields of TLS connections
+	IncludeTLS bool
+}
+
+// statusRecorder captures the status code and body size written by
+// downstream handlers
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+	// progress, when set, also counts the bytes written for progress entries
+	progress *progressTracker
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	if r.progress != nil {
+		r.progress.bytes.Add(int64(n))
+	}
+	return n, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// HTTPMiddleware returns a net/http middleware that logs HTTP requests.
+// It is compatible with chi, gorilla/mux and http.ServeMux.
+func HTTPMiddleware(opts *HTTPMiddlewareOptions) func(http.Handler) http.Handler {
+	if opts == nil {
+		opts = &HTTPMiddlewareOptions{}
+	}
+
+	routeFunc := opts.RouteFunc
+	if routeFunc == nil {
+		routeFunc = func(r *http.Request) string { return r.Pattern }
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = GetInstance()
+	}
+	redactor := newHeaderRedactor(opts.RedactHeaders, opts.HashRedactedHeaders)
+	redactedParams := nameSet(defaultRedactedQueryParams, opts.RedactQueryParams)
+	excluded := newRequestMatcher(opts.ExcludePaths, opts.ExcludePatterns, opts.ExcludeMethods)
+	accessLog := newAccessLog(opts.AccessLog, logger)
+	proxies := newProxyResolver(opts.TrustedProxies, opts.LogForwardingChain)
+	captured := newHeaderCapture(opts.CaptureHeaders, opts.SemanticConventions)
+	formatMessage := newHTTPMessageFunc(opts.MessageTemplate, opts.MessageFormatter)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Skip excluded paths and methods
+			path := r.URL.Path
+			if excluded.matches(r.Method, path) || (opts.Skip != nil && opts.Skip(r)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			startTime := logger.now()
+			recorder := &statusRecorder{ResponseWriter: w}
+
+			// Announce the request before it is handled
+			logStart := opts.LogRequestStart && (opts.AccessLog == nil || !opts.AccessLog.Only)
+			if logStart {
+				logger.HTTP(r.Context(), r.Method+" "+path+" started", LogContext{
+					"method":        r.Method,
+					"path":          path,
+					"request_phase": "started",
+				})
+			}
+
+			// Report on long-running requests while they are served
+			if opts.ProgressInterval > 0 {
+				recorder.progress = startProgress(logger, r.Context(), r.Method, path, startTime, opts.ProgressInterval, true)
+			}
+
+			// Process request
+			next.ServeHTTP(recorder, r)
+			if recorder.progress != nil {
+				recorder.progress.stop()
+			}
+
+			duration := logger.since(startTime)
+			statusCode := recorder.status
+			if statusCode == 0 {
+				statusCode = http.StatusOK
+			}
+
+			// Resolve the client behind trusted proxies
+			clientIP := r.RemoteAddr
+			if host, _, err := net.SplitHostPort(clientIP); err == nil {
+				clientIP = host
+			}
+			var forwardedFor []string
+			if proxies != nil {
+				forwarded := strings.Join(r.Header.Values(headerForwardedFor), ",")
+				clientIP, forwardedFor = proxies.resolve(clientIP, forwarded, r.Header.Get(headerRealIP))
+			}
+
+			if accessLog != nil {
+				accessLog.write(httpAccessRecord(r, recorder, clientIP, statusCode, startTime, redactedParams))
+				if opts.AccessLog.Only {
+					return
+				}
+			}
+
+			req := HTTPRequestInfo{
+				Method:    r.Method,
+				Path:      path,
+				Route:     routeFunc(r),
+				IP:        clientIP,
+				UserAgent: r.UserAgent(),
+				Bytes:     r.ContentLength,
+			}
+			resp := HTTPResponseInfo{
+				StatusCode: statusCode,
+				Duration:   duration,
+				Bytes:      int64(recorder.bytes),
+			}
+
+			// Add query params if present
+			if r.URL.RawQuery != "" {
+				req.Query = redactQuery(r.URL.RawQuery, redactedParams)
+			}
+
+			// Add headers if requested
+			if opts.IncludeHeaders {
+				req.Headers = make(map[string]string, len(r.Header))
+				for key := range r.Header {
+					req.Headers[key] = redactor.redact(key, r.Header.Get(key))
+				}
+			}
+
+			context := HTTPFields(req, resp)
+			if len(forwardedFor) > 0 {
+				context["forwarded_for"] = forwardedFor
+			}
+			if logStart {
+				context["request_phase"] = "completed"
+			}
+			captureHeaders(context, captured, r.Header.Get, redactor)
+			if opts.ParseUserAgent {
+				addUserAgentFields(context, req.UserAgent)
+			}
+			if opts.IncludeTLS {
+				addTLSFields(context, httpVersion(r.ProtoMajor, r.ProtoMinor), r.TLS)
+			}
+
+			if opts.SemanticConventions {
+				applySemanticConventions(context)
+			}
+
+			message := formatMessage(req, resp)
+
+			// Log based on status code
+			ctx := r.Context()
+			if statusCode >= 500 {
+				logger.Error(ctx, message, context)
+			} else if statusCode >= 400 {
+				logger.Warn(ctx, message, context)
+			} else {
+				logger.HTTP(ctx, message, context)
+			}
+		})
+	}
+}
+
+// httpAccessRecord collects the combined log format details of a request
+func httpAccessRecord(r *http.Request, recorder *statusRecorder, clientIP string, statusCode int, startTime time.Time, redactedParams map[string]struct{}) accessRecord {
+	uri := r.URL.Path
+	if r.URL.RawQuery != "" {
+		uri += "?" + redactQuery(r.URL.RawQuery, redactedParams)
+	}
+
+	return accessRecord{
+		ip:        clientIP,
+		time:      startTime,
+		method:    r.Method,
+		uri:       uri,
+		protocol:  r.Proto,
+		status:    statusCode,
+		bytes:     recorder.bytes,
+		referer:   r.Referer(),
+		userAgent: r.UserAgent(),
+	}
+}
@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "http-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("GET /broken", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	handler := HTTPMiddleware(&HTTPMiddlewareOptions{
+		ExcludePaths: []string{"/health"},
+	})(mux)
+
+	t.Run("should log route pattern alongside raw path", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		req := httptest.NewRequest("GET", "/users/42?expand=true", nil)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		logs := observedLogs.TakeAll()
+		if len(logs) != 1 {
+			t.Fatalf("Expected 1 log entry, got %d", len(logs))
+		}
+
+		fields := logs[0].ContextMap()
+		if fields["http.route"] != "GET /users/{id}" {
+			t.Errorf("Expected http.route='GET /users/{id}', got %v", fields["http.route"])
+		}
+		if fields["path"] != "/users/42" {
+			t.Errorf("Expected path=/users/42, got %v", fields["path"])
+		}
+		if fields["ip"] != "192.0.2.1" {
+			t.Errorf("Expected ip without the port, got %v", fields["ip"])
+		}
+		if fields["status_code"] != int64(201) {
+			t.Errorf("Expected status_code=201, got %v", fields["status_code"])
+		}
+		if fields["query"] != "expand=true" {
+			t.Errorf("Expected query=expand=true, got %v", fields["query"])
+		}
+		if fields["response_bytes"] != int64(0) {
+			t.Errorf("Expected response_bytes=0, got %v", fields["response_bytes"])
+		}
+	})
+
+	t.Run("should log 5xx as error", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/broken", nil))
+
+		logs := observedLogs.TakeAll()
+		if len(logs) != 1 || logs[0].Level != zapcore.ErrorLevel {
+			t.Fatalf("Expected a single ERROR entry, got %v", logs)
+		}
+		if size := logs[0].ContextMap()["response_bytes"]; size != int64(len("boom\n")) {
+			t.Errorf("Expected response_bytes=5, got %v", size)
+		}
+	})
+
+	t.Run("should skip excluded paths", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+
+		if n := observedLogs.Len(); n != 0 {
+			t.Errorf("Expected no log entries, got %d", n)
+		}
+	})
+
+	t.Run("should use custom route resolver", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		custom := HTTPMiddleware(&HTTPMiddlewareOptions{
+			RouteFunc: func(r *http.Request) string { return "/custom" },
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		custom.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/anything", nil))
+
+		logs := observedLogs.TakeAll()
+		if len(logs) != 1 {
+			t.Fatalf("Expected 1 log entry, got %d", len(logs))
+		}
+		if route := logs[0].ContextMap()["http.route"]; route != "/custom" {
+			t.Errorf("Expected http.route=/custom, got %v", route)
+		}
+	})
+}
+
+func TestHTTPMiddlewareResponseController(t *testing.T) {
+	setupObservedLogger(Config{ServiceName: "http-test", Level: LevelDEBUG})
+
+	handler := HTTPMiddleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Expected the recorder to unwrap to a flusher, got %v", err)
+		}
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/stream", nil))
+	if !recorder.Flushed {
+		t.Error("Expected the response to be flushed")
+	}
+}
@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// HTTPRequestInfo describes an inbound or outbound HTTP request with the
+// field names used by the middlewares
+type HTTPRequestInfo struct {
+	Method string
+	Path   string
+	// Route is the matched route pattern, logged as http.route
+	Route string
+	// Query is the raw query string; redact credentials before logging
+	Query     string
+	Host      string
+	IP        string
+	UserAgent string
+	// Bytes is the request body size, or -1 when unknown
+	Bytes   int64
+	Headers map[string]string
+}
+
+// HTTPResponseInfo describes the response to an HTTP request
+type HTTPResponseInfo struct {
+	StatusCode int
+	Duration   time.Duration
+	// Bytes is the response body size, or -1 when unknown
+	Bytes   int64
+	Headers map[string]string
+}
+
+// HTTPTyped logs an HTTP request and its response using the standard
+// field names, at INFO, WARN for 4xx or ERROR for 5xx responses. Extra
+// fields are added as is and override the standard ones.
+func (l *Logger) HTTPTyped(ctx context.Context, req HTTPRequestInfo, resp HTTPResponseInfo, extra LogContext) {
+	context := HTTPFields(req, resp)
+	for key, value := range extra {
+		context[key] = value
+	}
+
+	level := LevelINFO
+	if resp.StatusCode >= 500 {
+		level = LevelERROR
+	} else if resp.StatusCode >= 400 {
+		level = LevelWARN
+	}
+
+	l.log(ctx, 0, level, TypeHTTP, defaultHTTPMessage(req, resp), context)
+}
+
+// HTTPMessageFunc formats the message of an HTTP request entry
+type HTTPMessageFunc func(req HTTPRequestInfo, resp HTTPResponseInfo) string
+
+// HTTPExchange is the data of a middleware MessageTemplate, e.g.
+// "{{.Request.Method}} {{.Request.Path}} {{.Response.StatusCode}} in {{.Response.Duration}}"
+type HTTPExchange struct {
+	Request  HTTPRequestInfo
+	Response HTTPResponseInfo
+}
+
+// defaultHTTPMessage formats messages as "METHOD path status"
+func defaultHTTPMessage(req HTTPRequestInfo, resp HTTPResponseInfo) string {
+	return fmt.Sprintf("%s %s %d", req.Method, req.Path, resp.StatusCode)
+}
+
+// newHTTPMessageFunc returns the middleware message format: formatter when
+// set, else the parsed text, else the default. It panics on an invalid
+// template, like other configuration errors found at startup; templates
+// failing at execution fall back to the default message.
+func newHTTPMessageFunc(text string, formatter HTTPMessageFunc) HTTPMessageFunc {
+	if formatter != nil {
+		return formatter
+	}
+	if text == "" {
+		return defaultHTTPMessage
+	}
+
+	tmpl := template.Must(template.New("message").Parse(text))
+	return func(req HTTPRequestInfo, resp HTTPResponseInfo) string {
+		var message strings.Builder
+		if err := tmpl.Execute(&message, HTTPExchange{Request: req, Response: resp}); err != nil {
+			return defaultHTTPMessage(req, resp)
+		}
+		return message.String()
+	}
+}
+
+// HTTPFields returns the log fields of an HTTP exchange, as logged by the
+// middlewares and HTTPTyped, for integrations that log HTTP requests with
+// their own level and message; empty optional values and unknown sizes are
+// left out
+func HTTPFields(req HTTPRequestInfo, resp HTTPResponseInfo) LogContext {
+	context := LogContext{
+		"method":      req.Method,
+		"path":        req.Path,
+		"status_code": resp.StatusCode,
+		"duration_ms": resp.Duration.Milliseconds(),
+	}
+
+	if req.IP != "" {
+		context["ip"] = req.IP
+	}
+	if req.UserAgent != "" {
+		context["user_agent"] = req.UserAgent
+	}
+	if req.Route != "" {
+		context["http.route"] = req.Route
+	}
+	if req.Query != "" {
+		context["query"] = req.Query
+	}
+	if req.Host != "" {
+		context["host"] = req.Host
+	}
+	if req.Bytes >= 0 {
+		context["request_bytes"] = req.Bytes
+	}
+	if resp.Bytes >= 0 {
+		context["response_bytes"] = resp.Bytes
+	}
+	if req.Headers != nil {
+		context["headers"] = req.Headers
+	}
+	if resp.Headers != nil {
+		context["response_headers"] = resp.Headers
+	}
+	return context
+}
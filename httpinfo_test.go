@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestHTTPTyped(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "http-typed-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	t.Run("should log standard HTTP fields", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		logger.HTTPTyped(context.Background(), HTTPRequestInfo{
+			Method:    "GET",
+			Path:      "/orders/42",
+			Route:     "/orders/:id",
+			Host:      "api.internal",
+			IP:        "10.0.0.1",
+			UserAgent: "resty/2",
+			Bytes:     -1,
+		}, HTTPResponseInfo{
+			StatusCode: 200,
+			Duration:   1500 * time.Millisecond,
+			Bytes:      128,
+		}, LogContext{"http.attempt": 2})
+
+		logs := observedLogs.TakeAll()
+		if len(logs) != 1 {
+			t.Fatalf("Expected 1 log entry, got %d", len(logs))
+		}
+		if logs[0].Message != "GET /orders/42 200" {
+			t.Errorf("Unexpected message %q", logs[0].Message)
+		}
+		if logs[0].Level != zapcore.InfoLevel {
+			t.Errorf("Expected INFO, got %v", logs[0].Level)
+		}
+
+		fields := logs[0].ContextMap()
+		expected := map[string]interface{}{
+			"log_type":       "http",
+			"method":         "GET",
+			"path":           "/orders/42",
+			"http.route":     "/orders/:id",
+			"host":           "api.internal",
+			"status_code":    int64(200),
+			"duration_ms":    int64(1500),
+			"response_bytes": int64(128),
+			"http.attempt":   int64(2),
+		}
+		for key, value := range expected {
+			if fields[key] != value {
+				t.Errorf("Expected %s=%v, got %v", key, value, fields[key])
+			}
+		}
+		for _, key := range []string{"request_bytes", "query", "headers", "response_headers"} {
+			if _, ok := fields[key]; ok {
+				t.Errorf("Expected %s to be omitted", key)
+			}
+		}
+	})
+
+	t.Run("should raise the level of failed responses", func(t *testing.T) {
+		tests := []struct {
+			status   int
+			expected zapcore.Level
+		}{
+			{404, zapcore.WarnLevel},
+			{503, zapcore.ErrorLevel},
+		}
+
+		for _, tt := range tests {
+			observedLogs.TakeAll()
+			logger.HTTPTyped(context.Background(), HTTPRequestInfo{Method: "POST", Path: "/orders"}, HTTPResponseInfo{StatusCode: tt.status}, nil)
+
+			logs := observedLogs.TakeAll()
+			if len(logs) != 1 || logs[0].Level != tt.expected {
+				t.Errorf("Expected one %v entry for status %d, got %v", tt.expected, tt.status, logs)
+			}
+		}
+	})
+}
+
+func TestHTTPMessageFunc(t *testing.T) {
+	req := HTTPRequestInfo{Method: "GET", Path: "/orders"}
+	resp := HTTPResponseInfo{StatusCode: 200, Duration: 1500 * time.Microsecond}
+
+	tests := []struct {
+		name      string
+		template  string
+		formatter HTTPMessageFunc
+		expected  string
+	}{
+		{"default", "", nil, "GET /orders 200"},
+		{"template", "{{.Request.Method}} {{.Request.Path}} {{.Response.StatusCode}} in {{.Response.Duration}}", nil, "GET /orders 200 in 1.5ms"},
+		{"failing template falls back", "{{.Request.Missing}}", nil, "GET /orders 200"},
+		{"formatter wins", "ignored", func(req HTTPRequestInfo, resp HTTPResponseInfo) string {
+			return fmt.Sprintf("HTTP %d %s", resp.StatusCode, req.Path)
+		}, "HTTP 200 /orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newHTTPMessageFunc(tt.template, tt.formatter)(req, resp); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for an invalid template")
+		}
+	}()
+	newHTTPMessageFunc("{{.Request", nil)
+}
+
+func TestMiddlewareMessageTemplate(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "message-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+	const template = "request {{.Request.Method}} {{.Request.Path}} status={{.Response.StatusCode}}"
+
+	t.Run("fiber", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		app := fiber.New()
+		app.Use(FiberMiddleware(&MiddlewareOptions{MessageTemplate: template}))
+		app.Get("/orders", func(c *fiber.Ctx) error {
+			return c.SendStatus(404)
+		})
+		_, _ = app.Test(httptest.NewRequest("GET", "/orders", nil))
+
+		if message := observedLogs.TakeAll()[0].Message; message != "request GET /orders status=404" {
+			t.Errorf("Unexpected message %q", message)
+		}
+	})
+
+	t.Run("net/http", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		handler := HTTPMiddleware(&HTTPMiddlewareOptions{MessageTemplate: template})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/orders", nil))
+
+		if message := observedLogs.TakeAll()[0].Message; message != "request POST /orders status=200" {
+			t.Errorf("Unexpected message %q", message)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+// Package sqltext inspects SQL text for the database integrations.
+package sqltext
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Operation returns the first keyword of a query, e.g. SELECT
+func Operation(query string) string {
+	fields := strings.FieldsFunc(query, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '('
+	})
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// RedactLiterals replaces quoted strings and numbers with ?, keeping
+// placeholders ($1, :1, @p1) and identifiers (t1) intact
+func RedactLiterals(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'':
+			// Skip to the closing quote; '' is an escaped quote
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			b.WriteRune('?')
+		case unicode.IsDigit(r) && (i == 0 || !isIdentifierRune(runes[i-1])):
+			for i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.') {
+				i++
+			}
+			b.WriteRune('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isIdentifierRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$' || r == ':' || r == '@' || r == '.'
+}
@@ -0,0 +1,38 @@
+package sqltext
+
+import "testing"
+
+func TestOperation(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"select * from t", "SELECT"},
+		{"  (SELECT 1) UNION (SELECT 2)", "SELECT"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Operation(tt.query); got != tt.expected {
+			t.Errorf("Operation(%q) = %q, expected %q", tt.query, got, tt.expected)
+		}
+	}
+}
+
+func TestRedactLiterals(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"SELECT * FROM t1 WHERE id = 42", "SELECT * FROM t1 WHERE id = ?"},
+		{"SELECT 'it''s', 3.14", "SELECT ?, ?"},
+		{"INSERT INTO t VALUES ($1, :2, @p3)", "INSERT INTO t VALUES ($1, :2, @p3)"},
+		{`SELECT "col1" FROM t`, `SELECT "col1" FROM t`},
+	}
+
+	for _, tt := range tests {
+		if got := RedactLiterals(tt.query); got != tt.expected {
+			t.Errorf("RedactLiterals(%q) = %q, expected %q", tt.query, got, tt.expected)
+		}
+	}
+}
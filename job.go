@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrJobPanicked is wrapped by the error InstrumentJob returns when the
+// job panics
+var ErrJobPanicked = errors.New("job panicked")
+
+// InstrumentJob runs fn as a named background job (cron task, queue
+// consumer, goroutine worker), logging when it starts and finishes with
+// its duration and error. A panic in fn is recovered, logged with its
+// stack and returned as an error wrapping ErrJobPanicked.
+//
+// The context passed to fn carries job.name and job.run_id, so entries
+// logged inside the job can be correlated with its start and finish.
+func InstrumentJob(ctx context.Context, name string, fn func(ctx context.Context) error) (err error) {
+	logger := GetInstance()
+	ctx = WithContextFields(ctx, LogContext{
+		"job.name":   name,
+		"job.run_id": newRunID(),
+	})
+
+	logger.Log(ctx, LevelINFO, TypeJob, fmt.Sprintf("Job %s started", name), LogContext{
+		"job.status": "started",
+	})
+
+	startTime := logger.now()
+	defer func() {
+		context := LogContext{
+			"duration_ms": logger.since(startTime).Milliseconds(),
+		}
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %s", ErrJobPanicked, panicMessage(r))
+			context["job.status"] = "panicked"
+			context["panic"] = panicMessage(r)
+			context["panic_type"] = fmt.Sprintf("%T", r)
+			context["panic_stack"] = trimStack(debug.Stack())
+		} else if err != nil {
+			context["job.status"] = "failed"
+		} else {
+			context["job.status"] = "succeeded"
+		}
+
+		if err != nil {
+			context["error"] = err
+			logger.Log(ctx, LevelERROR, TypeJob, fmt.Sprintf("Job %s failed", name), context)
+			return
+		}
+		logger.Log(ctx, LevelINFO, TypeJob, fmt.Sprintf("Job %s finished", name), context)
+	}()
+
+	return fn(ctx)
+}
+
+// newRunID returns a random identifier for a job run
+func newRunID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestInstrumentJob(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "job-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+	ctx := context.Background()
+
+	t.Run("should log start and finish of a successful job", func(t *testing.T) {
+		var jobFields LogContext
+		err := InstrumentJob(ctx, "reindex", func(ctx context.Context) error {
+			jobFields = ContextFields(ctx)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		logs := observedLogs.TakeAll()
+		if len(logs) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(logs))
+		}
+		started, finished := logs[0].ContextMap(), logs[1].ContextMap()
+		if logs[0].Message != "Job reindex started" || started["job.status"] != "started" {
+			t.Errorf("Expected start entry, got %q %v", logs[0].Message, started)
+		}
+		if logs[1].Message != "Job reindex finished" || finished["job.status"] != "succeeded" {
+			t.Errorf("Expected finish entry, got %q %v", logs[1].Message, finished)
+		}
+		if finished["log_type"] != string(TypeJob) || finished["job.name"] != "reindex" {
+			t.Errorf("Expected job type and name, got %v", finished)
+		}
+		if _, ok := finished["duration_ms"]; !ok {
+			t.Error("Expected duration_ms on finish entry")
+		}
+		if jobFields["job.run_id"] == nil || jobFields["job.run_id"] != finished["job.run_id"] {
+			t.Errorf("Expected job context to carry the run id %v, got %v", finished["job.run_id"], jobFields["job.run_id"])
+		}
+	})
+
+	t.Run("should log and return job errors", func(t *testing.T) {
+		jobErr := errors.New("upstream unavailable")
+		err := InstrumentJob(ctx, "sync", func(ctx context.Context) error {
+			return jobErr
+		})
+		if !errors.Is(err, jobErr) {
+			t.Fatalf("Expected job error, got %v", err)
+		}
+
+		logs := observedLogs.TakeAll()
+		if len(logs) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(logs))
+		}
+		failed := logs[1].ContextMap()
+		if logs[1].Level.String() != "error" || failed["job.status"] != "failed" {
+			t.Errorf("Expected failed ERROR entry, got %s %v", logs[1].Level, failed)
+		}
+		if failed["error_message"] != "upstream unavailable" {
+			t.Errorf("Expected error message, got %v", failed["error_message"])
+		}
+	})
+
+	t.Run("should recover panics", func(t *testing.T) {
+		err := InstrumentJob(ctx, "worker", func(ctx context.Context) error {
+			panic("nil map")
+		})
+		if !errors.Is(err, ErrJobPanicked) {
+			t.Fatalf("Expected ErrJobPanicked, got %v", err)
+		}
+
+		logs := observedLogs.TakeAll()
+		if len(logs) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(logs))
+		}
+		panicked := logs[1].ContextMap()
+		if panicked["job.status"] != "panicked" || panicked["panic"] != "nil map" {
+			t.Errorf("Expected panicked entry, got %v", panicked)
+		}
+		if panicked["panic_stack"] == "" {
+			t.Error("Expected panic stack")
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package logger
+
+// Pair is a key-value pair created with KV
+type Pair struct {
+	Key   string
+	Value interface{}
+}
+
+// KV pairs a key with a typed value for Pairs, so a missing value or a
+// non-string key is a compile error rather than a panic in Fields
+func KV[T any](key string, value T) Pair {
+	return Pair{Key: key, Value: value}
+}
+
+// Pairs creates a LogContext from pairs; later pairs win on duplicate keys
+// Example: Pairs(KV("order_id", id), KV("items", 3))
+func Pairs(pairs ...Pair) LogContext {
+	context := make(LogContext, len(pairs))
+	for _, pair := range pairs {
+		context[pair.Key] = pair.Value
+	}
+	return context
+}
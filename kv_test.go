@@ -0,0 +1,25 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPairs(t *testing.T) {
+	context := Pairs(
+		KV("order_id", "123"),
+		KV("items", 3),
+		KV("timeout", 2*time.Second),
+		KV("items", 4),
+	)
+
+	if len(context) != 3 {
+		t.Fatalf("Expected 3 fields, got %v", context)
+	}
+	if context["order_id"] != "123" || context["items"] != 4 || context["timeout"] != 2*time.Second {
+		t.Errorf("Unexpected fields: %v", context)
+	}
+	if len(Pairs()) != 0 {
+		t.Error("Expected an empty context without pairs")
+	}
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ParseLevel parses a level name case-insensitively (debug, info, warn,
+// warning, error or off)
+func ParseLevel(name string) (LogLevel, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "DEBUG":
+		return LevelDEBUG, nil
+	case "INFO":
+		return LevelINFO, nil
+	case "WARN", "WARNING":
+		return LevelWARN, nil
+	case "ERROR":
+		return LevelERROR, nil
+	case "OFF":
+		return LevelOFF, nil
+	default:
+		return "", fmt.Errorf("invalid log level %q", name)
+	}
+}
+
+// Level returns the current minimum level
+func (l *Logger) Level() LogLevel {
+	if !l.level.Enabled(zapLevel(LevelERROR)) {
+		return LevelOFF
+	}
+	return logLevel(l.level.Level())
+}
+
+// SetLevel changes the minimum level of the logger and its children at
+// runtime, logging the change with source (e.g. "admin" or "signal")
+func (l *Logger) SetLevel(level LogLevel, source string) {
+	previous := l.Level()
+	l.level.SetLevel(zapLevel(level))
+
+	l.withDebug().log(context.Background(), 0, LevelINFO, TypeNormal, "Log level changed", LogContext{
+		"previous_level": string(previous),
+		"level":          string(level),
+		"source":         source,
+	})
+}
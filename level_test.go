@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]LogLevel{"debug": LevelDEBUG, "INFO": LevelINFO, "Warning": LevelWARN, "error": LevelERROR, "off": LevelOFF}
+	for name, expected := range tests {
+		if level, err := ParseLevel(name); err != nil || level != expected {
+			t.Errorf("Expected %s for %q, got %s %v", expected, name, level, err)
+		}
+	}
+	if _, err := ParseLevel("trace"); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	logger, _ := setupObservedLogger(Config{ServiceName: "level-test", Level: LevelWARN})
+	child := logger.With(LogContext{"component": "db"})
+
+	logger.SetLevel(LevelDEBUG, "test")
+	if child.Level() != LevelDEBUG || !logger.level.Enabled(zapcore.DebugLevel) {
+		t.Errorf("Expected children to share the new level, got %s", child.Level())
+	}
+
+	logger.SetLevel(LevelOFF, "test")
+	if logger.Level() != LevelOFF || logger.level.Enabled(zapcore.ErrorLevel) {
+		t.Errorf("Expected OFF, got %s", logger.Level())
+	}
+}
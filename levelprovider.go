@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// LevelProvider supplies the desired level from a central source, such as
+// a control plane or feature-flag system. An empty level leaves the
+// current level unchanged.
+type LevelProvider interface {
+	Level(ctx context.Context) (LogLevel, error)
+}
+
+// LevelProviderFunc adapts a function to the LevelProvider interface
+type LevelProviderFunc func(ctx context.Context) (LogLevel, error)
+
+// Level calls f(ctx)
+func (f LevelProviderFunc) Level(ctx context.Context) (LogLevel, error) {
+	return f(ctx)
+}
+
+// WatchLevel polls provider every interval (default 30s) until ctx is
+// done, applying the level whenever the provided value changes. Levels set
+// in between with SetLevel, the admin handler or signals are kept until
+// the provider's value changes again. Provider failures are logged once
+// until it recovers.
+func (l *Logger) WatchLevel(ctx context.Context, provider LevelProvider, interval time.Duration) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var applied LogLevel
+		failing := false
+		for {
+			level, err := provider.Level(ctx)
+			switch {
+			case err != nil:
+				if !failing && ctx.Err() == nil {
+					l.log(ctx, 0, LevelWARN, TypeNormal, "Level provider failed", LogContext{"error_message": err.Error()})
+				}
+				failing = true
+			case level != "" && level != applied:
+				failing = false
+				applied = level
+				if level != l.Level() {
+					l.SetLevel(level, "provider")
+				}
+			default:
+				failing = false
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// EnvLevelProvider returns a LevelProvider reading the level from the
+// environment variable name on every poll. An unset variable leaves the
+// level unchanged.
+func EnvLevelProvider(name string) LevelProvider {
+	return LevelProviderFunc(func(ctx context.Context) (LogLevel, error) {
+		value := os.Getenv(name)
+		if value == "" {
+			return "", nil
+		}
+		return ParseLevel(value)
+	})
+}
+
+// HTTPLevelProvider fetches the level from an HTTP endpoint responding
+// with {"level":"DEBUG"} or the plain level name
+type HTTPLevelProvider struct {
+	URL string
+	// Header is added to each request, e.g. for authorization
+	Header http.Header
+	// Client defaults to an http.Client with a 5 second timeout
+	Client *http.Client
+}
+
+// Level fetches and parses the level
+func (p *HTTPLevelProvider) Level(ctx context.Context) (LogLevel, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	for key, values := range p.Header {
+		request.Header[key] = values
+	}
+
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("level provider: status %d", response.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(response.Body, 4<<10))
+	if err != nil {
+		return "", err
+	}
+
+	value := strings.TrimSpace(string(body))
+	var payload levelRequest
+	if strings.HasPrefix(value, "{") {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return "", err
+		}
+		value = payload.Level
+	}
+	return ParseLevel(value)
+}
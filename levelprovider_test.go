@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPLevelProvider(t *testing.T) {
+	body := `{"level":"debug"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	provider := &HTTPLevelProvider{URL: server.URL, Header: http.Header{"Authorization": {"Bearer secret"}}}
+	if level, err := provider.Level(context.Background()); err != nil || level != LevelDEBUG {
+		t.Errorf("Expected DEBUG, got %s %v", level, err)
+	}
+
+	body = "WARN\n"
+	if level, err := provider.Level(context.Background()); err != nil || level != LevelWARN {
+		t.Errorf("Expected WARN from plain body, got %s %v", level, err)
+	}
+
+	provider.Header = nil
+	if _, err := provider.Level(context.Background()); err == nil {
+		t.Error("Expected error for non-200 response")
+	}
+}
+
+func TestWatchLevel(t *testing.T) {
+	logger, logs := setupObservedLogger(Config{ServiceName: "provider-test", Level: LevelINFO})
+	t.Setenv("PROVIDER_TEST_LEVEL", "error")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger.WatchLevel(ctx, EnvLevelProvider("PROVIDER_TEST_LEVEL"), 5*time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for logger.Level() != LevelERROR && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if logger.Level() != LevelERROR {
+		t.Fatalf("Expected ERROR from provider, got %s", logger.Level())
+	}
+
+	changes := logs.FilterMessage("Log level changed").All()
+	if len(changes) != 1 || changes[0].ContextMap()["source"] != "provider" {
+		t.Errorf("Expected one provider level change, got %v", changes)
+	}
+
+	// A manual override is kept while the provider value is unchanged
+	logger.SetLevel(LevelDEBUG, "test")
+	time.Sleep(20 * time.Millisecond)
+	if logger.Level() != LevelDEBUG {
+		t.Errorf("Expected manual override to persist, got %s", logger.Level())
+	}
+}
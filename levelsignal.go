@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// levelSignalWatcher switches the level to DEBUG on the debug signal
+// (SIGUSR1) and back on the restore signal (SIGUSR2)
+type levelSignalWatcher struct {
+	signals  chan os.Signal
+	stopOnce sync.Once
+	stopped  chan struct{}
+	done     chan struct{}
+}
+
+// watchLevelSignals starts handling the level signals. It returns nil on
+// platforms without them.
+func (l *Logger) watchLevelSignals() *levelSignalWatcher {
+	if debugSignal == nil {
+		return nil
+	}
+
+	w := &levelSignalWatcher{
+		signals: make(chan os.Signal, 1),
+		stopped: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	signal.Notify(w.signals, debugSignal, restoreSignal)
+
+	go func() {
+		defer close(w.done)
+
+		// restore is the level in effect before the debug signal
+		var restore LogLevel
+		for {
+			select {
+			case <-w.stopped:
+				return
+			case sig := <-w.signals:
+				switch {
+				case sig == debugSignal && restore == "":
+					restore = l.Level()
+					l.SetLevel(LevelDEBUG, "signal")
+				case sig == restoreSignal && restore != "":
+					l.SetLevel(restore, "signal")
+					restore = ""
+				}
+			}
+		}
+	}()
+	return w
+}
+
+// stop stops handling the signals and waits for the watcher to exit
+func (w *levelSignalWatcher) stop() {
+	w.stopOnce.Do(func() {
+		signal.Stop(w.signals)
+		close(w.stopped)
+	})
+	<-w.done
+}
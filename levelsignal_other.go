@@ -0,0 +1,11 @@
+//go:build !unix
+
+package logger
+
+import "os"
+
+// Level signals are not available on this platform
+var (
+	debugSignal   os.Signal
+	restoreSignal os.Signal
+)
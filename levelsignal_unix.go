@@ -0,0 +1,15 @@
+//go:build unix
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+// debugSignal and restoreSignal toggle the level when Config.LevelSignals
+// is set, e.g. kill -USR1 <pid>
+var (
+	debugSignal   os.Signal = syscall.SIGUSR1
+	restoreSignal os.Signal = syscall.SIGUSR2
+)
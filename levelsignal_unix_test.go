@@ -0,0 +1,49 @@
+//go:build unix
+
+package logger
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLevelSignals(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "signal-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelWARN,
+	})
+	watcher := logger.watchLevelSignals()
+	defer watcher.stop()
+
+	waitForLevel := func(level LogLevel) {
+		deadline := time.Now().Add(time.Second)
+		for logger.Level() != level && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if logger.Level() != level {
+			t.Fatalf("Expected level %s, got %s", level, logger.Level())
+		}
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("Failed to send signal: %v", err)
+	}
+	waitForLevel(LevelDEBUG)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatalf("Failed to send signal: %v", err)
+	}
+	waitForLevel(LevelWARN)
+
+	logs := observedLogs.FilterMessage("Log level changed").All()
+	if len(logs) != 2 {
+		t.Fatalf("Expected 2 level changes, got %d", len(logs))
+	}
+	if logs[0].ContextMap()["source"] != "signal" || logs[1].ContextMap()["level"] != "WARN" {
+		t.Errorf("Expected signal level changes, got %v %v", logs[0].ContextMap(), logs[1].ContextMap())
+	}
+}
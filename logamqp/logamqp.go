@@ -0,0 +1,157 @@
+// Package logamqp wraps RabbitMQ (amqp091-go) delivery handlers so every
+// delivery is logged through the rcommerz logger with exchange, routing
+// key, redelivery flag, consumer tag, processing time and ack decision.
+package logamqp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	logger "github.com/rcommerz/logger-go"
+	"go.opentelemetry.io/otel"
+)
+
+// Handler processes a delivery with the trace context of its publisher
+type Handler func(ctx context.Context, delivery amqp.Delivery) error
+
+// Options configures the delivery wrapper
+type Options struct {
+	// Requeue requeues failed deliveries once: a failed redelivery is
+	// rejected without requeue (dead-lettered if the queue has a DLX).
+	// By default failed deliveries are never requeued.
+	Requeue bool
+}
+
+// Decisions logged as messaging.rabbitmq.decision
+const (
+	DecisionAck     = "ack"
+	DecisionRequeue = "requeue"
+	DecisionReject  = "reject"
+)
+
+// Wrap returns a delivery handler that runs handler, acks the delivery
+// when it succeeds and nacks it when it fails or panics. Each delivery is
+// logged at INFO, or ERROR if it failed. Handlers get a context derived
+// from context.Background; use Consume to derive it from a caller's
+// context instead.
+func Wrap(handler Handler, opts *Options) func(amqp.Delivery) {
+	handle := wrap(handler, opts)
+	return func(delivery amqp.Delivery) {
+		handle(context.Background(), delivery)
+	}
+}
+
+// wrap returns a delivery handler whose context, carrying the publisher's
+// trace context, is derived from the given one
+func wrap(handler Handler, opts *Options) func(context.Context, amqp.Delivery) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	log := logger.GetInstance()
+
+	return func(ctx context.Context, delivery amqp.Delivery) {
+		if delivery.Headers != nil {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, tableCarrier(delivery.Headers))
+		}
+
+		startTime := time.Now()
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic: %v", r)
+					log.Error(ctx, "Panic recovered", logger.LogContext{
+						"messaging.system":               "rabbitmq",
+						"messaging.rabbitmq.routing_key": delivery.RoutingKey,
+						"panic":                          fmt.Sprint(r),
+					})
+				}
+			}()
+			err = handler(ctx, delivery)
+		}()
+
+		decision, ackErr := DecisionAck, error(nil)
+		switch {
+		case err == nil:
+			ackErr = delivery.Ack(false)
+		case opts.Requeue && !delivery.Redelivered:
+			decision = DecisionRequeue
+			ackErr = delivery.Nack(false, true)
+		default:
+			decision = DecisionReject
+			ackErr = delivery.Nack(false, false)
+		}
+
+		context := logger.LogContext{
+			"messaging.system":                "rabbitmq",
+			"messaging.operation":             "process",
+			"messaging.destination.name":      delivery.Exchange,
+			"messaging.rabbitmq.routing_key":  delivery.RoutingKey,
+			"messaging.rabbitmq.redelivered":  delivery.Redelivered,
+			"messaging.rabbitmq.consumer_tag": delivery.ConsumerTag,
+			"messaging.rabbitmq.decision":     decision,
+			"messaging.message.body.size":     len(delivery.Body),
+			"duration_ms":                     time.Since(startTime).Milliseconds(),
+		}
+		if delivery.MessageId != "" {
+			context["messaging.message.id"] = delivery.MessageId
+		}
+		if ackErr != nil {
+			context["messaging.rabbitmq.ack_error"] = ackErr.Error()
+		}
+
+		level := logger.LevelINFO
+		if err != nil {
+			context["error"] = err
+			level = logger.LevelERROR
+		}
+		log.Log(ctx, level, logger.TypeMessaging, fmt.Sprintf("rabbitmq process %s", delivery.RoutingKey), context)
+	}
+}
+
+// Consume runs the wrapped handler for each delivery until the channel is
+// closed or ctx is done. Handlers get a context derived from ctx, so they
+// see its cancellation and values.
+func Consume(ctx context.Context, deliveries <-chan amqp.Delivery, handler Handler, opts *Options) {
+	handle := wrap(handler, opts)
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+			return
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return
+			}
+			handle(ctx, delivery)
+		}
+	}
+}
+
+// tableCarrier adapts delivery headers to an OpenTelemetry TextMapCarrier
+type tableCarrier amqp.Table
+
+func (c tableCarrier) Get(key string) string {
+	switch value := c[key].(type) {
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	default:
+		return ""
+	}
+}
+
+func (c tableCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c tableCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,245 @@
+package logamqp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	logger "github.com/rcommerz/logger-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	entriesMu sync.Mutex
+	entries   []logger.Entry
+)
+
+func TestMain(m *testing.M) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	log := logger.Initialize(logger.Config{
+		ServiceName:    "logamqp-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          logger.LevelDEBUG,
+	})
+	log.RegisterHook(func(entry logger.Entry) logger.Entry {
+		entriesMu.Lock()
+		entries = append(entries, entry)
+		entriesMu.Unlock()
+		return entry
+	})
+	os.Exit(m.Run())
+}
+
+// takeEntries returns and clears the entries logged so far
+func takeEntries() []logger.Entry {
+	entriesMu.Lock()
+	defer entriesMu.Unlock()
+	taken := entries
+	entries = nil
+	return taken
+}
+
+// fakeAcknowledger records the ack decision made for a delivery
+type fakeAcknowledger struct {
+	acked    bool
+	nacked   bool
+	requeued bool
+	err      error
+}
+
+func (a *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return a.err
+}
+
+func (a *fakeAcknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	a.nacked = true
+	a.requeued = requeue
+	return a.err
+}
+
+func (a *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func newDelivery(ack *fakeAcknowledger) amqp.Delivery {
+	return amqp.Delivery{
+		Acknowledger: ack,
+		Headers:      amqp.Table{"traceparent": traceparent},
+		ConsumerTag:  "worker-1",
+		DeliveryTag:  1,
+		Exchange:     "orders",
+		RoutingKey:   "orders.created",
+		Body:         []byte("hello"),
+	}
+}
+
+func TestWrap(t *testing.T) {
+	t.Run("should ack and log processed deliveries with the publisher's trace", func(t *testing.T) {
+		var traceID string
+		ack := &fakeAcknowledger{}
+		Wrap(func(ctx context.Context, delivery amqp.Delivery) error {
+			traceID = trace.SpanContextFromContext(ctx).TraceID().String()
+			return nil
+		}, nil)(newDelivery(ack))
+
+		if traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("Expected handler context to carry the delivery trace, got %q", traceID)
+		}
+		if !ack.acked {
+			t.Error("Expected delivery to be acked")
+		}
+		logged := takeEntries()
+		if len(logged) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(logged))
+		}
+		expected := map[string]interface{}{
+			"messaging.system":                "rabbitmq",
+			"messaging.operation":             "process",
+			"messaging.destination.name":      "orders",
+			"messaging.rabbitmq.routing_key":  "orders.created",
+			"messaging.rabbitmq.redelivered":  false,
+			"messaging.rabbitmq.consumer_tag": "worker-1",
+			"messaging.rabbitmq.decision":     DecisionAck,
+			"messaging.message.body.size":     5,
+		}
+		for key, value := range expected {
+			if logged[0].Fields[key] != value {
+				t.Errorf("Expected %s=%v, got %v", key, value, logged[0].Fields[key])
+			}
+		}
+		if logged[0].Level != logger.LevelINFO {
+			t.Errorf("Expected INFO, got %s", logged[0].Level)
+		}
+		if logged[0].Type != logger.TypeMessaging {
+			t.Errorf("Expected messaging type, got %s", logged[0].Type)
+		}
+	})
+
+	t.Run("should requeue a failed delivery once", func(t *testing.T) {
+		handler := Wrap(func(ctx context.Context, delivery amqp.Delivery) error {
+			return errors.New("boom")
+		}, &Options{Requeue: true})
+
+		ack := &fakeAcknowledger{}
+		handler(newDelivery(ack))
+		if !ack.nacked || !ack.requeued {
+			t.Error("Expected first failure to be requeued")
+		}
+
+		ack = &fakeAcknowledger{}
+		redelivery := newDelivery(ack)
+		redelivery.Redelivered = true
+		handler(redelivery)
+		if !ack.nacked || ack.requeued {
+			t.Error("Expected failed redelivery to be rejected")
+		}
+
+		logged := takeEntries()
+		if len(logged) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(logged))
+		}
+		if logged[0].Fields["messaging.rabbitmq.decision"] != DecisionRequeue {
+			t.Errorf("Expected requeue decision, got %v", logged[0].Fields["messaging.rabbitmq.decision"])
+		}
+		if logged[1].Fields["messaging.rabbitmq.decision"] != DecisionReject {
+			t.Errorf("Expected reject decision, got %v", logged[1].Fields["messaging.rabbitmq.decision"])
+		}
+		if logged[1].Level != logger.LevelERROR {
+			t.Errorf("Expected ERROR, got %s", logged[1].Level)
+		}
+		if logged[1].Fields["error_message"] != "boom" {
+			t.Errorf("Expected error message, got %v", logged[1].Fields["error_message"])
+		}
+	})
+
+	t.Run("should log and nack a panicking handler", func(t *testing.T) {
+		ack := &fakeAcknowledger{}
+		Wrap(func(ctx context.Context, delivery amqp.Delivery) error {
+			panic("kaboom")
+		}, nil)(newDelivery(ack))
+
+		if !ack.nacked || ack.requeued {
+			t.Error("Expected panicking delivery to be rejected")
+		}
+		logged := takeEntries()
+		if len(logged) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(logged))
+		}
+		if logged[0].Message != "Panic recovered" || logged[0].Fields["panic"] != "kaboom" {
+			t.Errorf("Expected panic entry, got %q %v", logged[0].Message, logged[0].Fields)
+		}
+		if logged[1].Level != logger.LevelERROR {
+			t.Errorf("Expected ERROR, got %s", logged[1].Level)
+		}
+	})
+
+	t.Run("should log ack failures", func(t *testing.T) {
+		ack := &fakeAcknowledger{err: errors.New("channel closed")}
+		Wrap(func(ctx context.Context, delivery amqp.Delivery) error {
+			return nil
+		}, nil)(newDelivery(ack))
+
+		logged := takeEntries()
+		if len(logged) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(logged))
+		}
+		if logged[0].Fields["messaging.rabbitmq.ack_error"] != "channel closed" {
+			t.Errorf("Expected ack error, got %v", logged[0].Fields["messaging.rabbitmq.ack_error"])
+		}
+	})
+}
+
+func TestConsume(t *testing.T) {
+	t.Run("should process deliveries until the channel closes", func(t *testing.T) {
+		deliveries := make(chan amqp.Delivery, 2)
+		deliveries <- newDelivery(&fakeAcknowledger{})
+		deliveries <- newDelivery(&fakeAcknowledger{})
+		close(deliveries)
+
+		count := 0
+		Consume(context.Background(), deliveries, func(ctx context.Context, delivery amqp.Delivery) error {
+			count++
+			return nil
+		}, nil)
+
+		if count != 2 {
+			t.Errorf("Expected 2 deliveries processed, got %d", count)
+		}
+		takeEntries()
+	})
+
+	t.Run("should derive handler contexts from ctx and stop when it is done", func(t *testing.T) {
+		type key struct{}
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "consumer"))
+		deliveries := make(chan amqp.Delivery, 2)
+		deliveries <- newDelivery(&fakeAcknowledger{})
+		deliveries <- newDelivery(&fakeAcknowledger{})
+
+		count := 0
+		Consume(ctx, deliveries, func(ctx context.Context, delivery amqp.Delivery) error {
+			count++
+			if ctx.Value(key{}) != "consumer" {
+				t.Error("Expected the handler context to carry the caller's values")
+			}
+			cancel()
+			if ctx.Err() == nil {
+				t.Error("Expected the handler context to be cancelled with the caller's")
+			}
+			return nil
+		}, nil)
+
+		if count != 1 {
+			t.Errorf("Expected Consume to stop after ctx is done, got %d deliveries processed", count)
+		}
+		takeEntries()
+	})
+}
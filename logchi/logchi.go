@@ -0,0 +1,34 @@
+// Package logchi adapts the net/http logging middleware to the chi router,
+// recording the matched route pattern as http.route.
+package logchi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	logger "github.com/rcommerz/logger-go"
+)
+
+// Middleware returns a chi middleware that logs HTTP requests with the
+// matched chi route pattern (e.g. /users/{id})
+func Middleware(opts *logger.HTTPMiddlewareOptions) func(http.Handler) http.Handler {
+	options := logger.HTTPMiddlewareOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.RouteFunc == nil {
+		options.RouteFunc = RoutePattern
+	}
+
+	return logger.HTTPMiddleware(&options)
+}
+
+// RoutePattern returns the chi route pattern matched for the request, or an
+// empty string when the request was not routed by chi
+func RoutePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return ""
+	}
+	return rctx.RoutePattern()
+}
@@ -0,0 +1,74 @@
+package logchi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	logger "github.com/rcommerz/logger-go"
+)
+
+func TestRoutePattern(t *testing.T) {
+	t.Run("should resolve nested chi route pattern", func(t *testing.T) {
+		var pattern string
+
+		r := chi.NewRouter()
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				next.ServeHTTP(w, req)
+				pattern = RoutePattern(req)
+			})
+		})
+		r.Route("/api", func(r chi.Router) {
+			r.Get("/users/{id}", func(w http.ResponseWriter, req *http.Request) {})
+		})
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/users/7", nil))
+
+		if pattern != "/api/users/{id}" {
+			t.Errorf("Expected pattern '/api/users/{id}', got '%s'", pattern)
+		}
+	})
+
+	t.Run("should return empty string outside chi", func(t *testing.T) {
+		if p := RoutePattern(httptest.NewRequest("GET", "/", nil)); p != "" {
+			t.Errorf("Expected empty pattern, got '%s'", p)
+		}
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	var entries []logger.Entry
+	log := logger.New(logger.Config{ServiceName: "logchi-test", Level: logger.LevelINFO, Output: io.Discard})
+	log.RegisterHook(func(entry logger.Entry) logger.Entry {
+		entries = append(entries, entry)
+		return entry
+	})
+
+	r := chi.NewRouter()
+	r.Use(Middleware(&logger.HTTPMiddlewareOptions{Logger: log}))
+	r.Route("/users", func(r chi.Router) {
+		r.Get("/{id}", func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+		})
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/users/1", nil))
+
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("Expected status 202, got %d", rec.Code)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	fields := entries[0].Fields
+	if fields["http.route"] != "/users/{id}" {
+		t.Errorf("Expected http.route=/users/{id}, got %v", fields["http.route"])
+	}
+	if fields["path"] != "/users/1" || fields["ip"] != "192.0.2.1" {
+		t.Errorf("Expected the raw path and the bare client IP, got %v %v", fields["path"], fields["ip"])
+	}
+}
@@ -0,0 +1,91 @@
+// Package logcron logs robfig/cron job runs through the rcommerz logger.
+// Wrap logs each run's start, finish, duration and failure,
+// SkipIfStillRunning logs skipped overlapping runs, and Logger adapts the
+// scheduler's own logging:
+//
+//	c := cron.New(cron.WithLogger(logcron.Logger()))
+//	c.AddJob("@every 1m", cron.NewChain(
+//		logcron.SkipIfStillRunning("reindex"),
+//		logcron.Wrap("reindex"),
+//	).Then(job))
+package logcron
+
+import (
+	"context"
+	"fmt"
+
+	logger "github.com/rcommerz/logger-go"
+	"github.com/robfig/cron/v3"
+)
+
+// Wrap returns a job wrapper that runs each job with logger.InstrumentJob
+// under the given schedule name, so every run is logged with its start,
+// finish, duration and panic, if any. Panics are recovered.
+func Wrap(name string) cron.JobWrapper {
+	return func(job cron.Job) cron.Job {
+		return cron.FuncJob(func() {
+			_ = logger.InstrumentJob(context.Background(), name, func(ctx context.Context) error {
+				job.Run()
+				return nil
+			})
+		})
+	}
+}
+
+// SkipIfStillRunning returns a job wrapper that skips a run while the
+// previous run of the job is still in progress, logging each skipped run
+// at WARN
+func SkipIfStillRunning(name string) cron.JobWrapper {
+	return func(job cron.Job) cron.Job {
+		running := make(chan struct{}, 1)
+		running <- struct{}{}
+		return cron.FuncJob(func() {
+			select {
+			case token := <-running:
+				defer func() { running <- token }()
+				job.Run()
+			default:
+				logger.GetInstance().Log(context.Background(), logger.LevelWARN, logger.TypeJob,
+					fmt.Sprintf("Job %s skipped", name), logger.LogContext{
+						"job.name":   name,
+						"job.status": "skipped",
+						"reason":     "previous run still running",
+					})
+			}
+		})
+	}
+}
+
+// Logger returns a cron.Logger for cron.WithLogger. Scheduler messages
+// are logged at DEBUG, skipped runs at WARN and errors (including panics
+// recovered by cron.Recover) at ERROR.
+func Logger() cron.Logger {
+	return cronLogger{}
+}
+
+// cronLogger implements cron.Logger
+type cronLogger struct{}
+
+func (cronLogger) Info(msg string, keysAndValues ...interface{}) {
+	level := logger.LevelDEBUG
+	if msg == "skip" {
+		level = logger.LevelWARN
+	}
+	logger.GetInstance().Log(context.Background(), level, logger.TypeJob, "cron "+msg, fields(keysAndValues))
+}
+
+func (cronLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	entryFields := fields(keysAndValues)
+	entryFields["error"] = err
+	logger.GetInstance().Log(context.Background(), logger.LevelERROR, logger.TypeJob, "cron "+msg, entryFields)
+}
+
+// fields converts cron's alternating keys and values to a LogContext,
+// ignoring a trailing key without a value
+func fields(keysAndValues []interface{}) logger.LogContext {
+	context := logger.LogContext{"component": "cron"}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		context[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	return context
+}
@@ -0,0 +1,138 @@
+package logcron
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	logger "github.com/rcommerz/logger-go"
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	entriesMu sync.Mutex
+	entries   []logger.Entry
+)
+
+func TestMain(m *testing.M) {
+	log := logger.Initialize(logger.Config{
+		ServiceName:    "logcron-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          logger.LevelDEBUG,
+	})
+	log.RegisterHook(func(entry logger.Entry) logger.Entry {
+		entriesMu.Lock()
+		entries = append(entries, entry)
+		entriesMu.Unlock()
+		return entry
+	})
+	os.Exit(m.Run())
+}
+
+// takeEntries returns and clears the entries logged so far
+func takeEntries() []logger.Entry {
+	entriesMu.Lock()
+	defer entriesMu.Unlock()
+	taken := entries
+	entries = nil
+	return taken
+}
+
+func TestWrap(t *testing.T) {
+	t.Run("should log job runs under the schedule name", func(t *testing.T) {
+		ran := false
+		Wrap("reindex")(cron.FuncJob(func() { ran = true })).Run()
+
+		if !ran {
+			t.Error("Expected job to run")
+		}
+		logged := takeEntries()
+		if len(logged) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(logged))
+		}
+		if logged[1].Fields["job.name"] != "reindex" || logged[1].Fields["job.status"] != "succeeded" {
+			t.Errorf("Expected succeeded reindex run, got %v", logged[1].Fields)
+		}
+		if logged[1].Type != logger.TypeJob {
+			t.Errorf("Expected job type, got %s", logged[1].Type)
+		}
+	})
+
+	t.Run("should log and recover panicking runs", func(t *testing.T) {
+		Wrap("reindex")(cron.FuncJob(func() { panic("boom") })).Run()
+
+		logged := takeEntries()
+		if len(logged) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(logged))
+		}
+		if logged[1].Level != logger.LevelERROR || logged[1].Fields["job.status"] != "panicked" {
+			t.Errorf("Expected panicked ERROR entry, got %s %v", logged[1].Level, logged[1].Fields)
+		}
+	})
+}
+
+func TestSkipIfStillRunning(t *testing.T) {
+	t.Run("should log skipped overlapping runs", func(t *testing.T) {
+		var job cron.Job
+		runs := 0
+		job = SkipIfStillRunning("reindex")(cron.FuncJob(func() {
+			runs++
+			if runs == 1 {
+				job.Run()
+			}
+		}))
+		job.Run()
+		job.Run()
+
+		if runs != 2 {
+			t.Errorf("Expected 2 runs, got %d", runs)
+		}
+		logged := takeEntries()
+		if len(logged) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(logged))
+		}
+		if logged[0].Level != logger.LevelWARN || logged[0].Fields["job.status"] != "skipped" {
+			t.Errorf("Expected skipped WARN entry, got %s %v", logged[0].Level, logged[0].Fields)
+		}
+	})
+}
+
+func TestLogger(t *testing.T) {
+	tests := []struct {
+		name  string
+		log   func(cron.Logger)
+		level logger.LogLevel
+	}{
+		{"info", func(l cron.Logger) { l.Info("start") }, logger.LevelDEBUG},
+		{"skip", func(l cron.Logger) { l.Info("skip") }, logger.LevelWARN},
+		{"error", func(l cron.Logger) { l.Error(errors.New("boom"), "panic", "stack", "...") }, logger.LevelERROR},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.log(Logger())
+
+			logged := takeEntries()
+			if len(logged) != 1 {
+				t.Fatalf("Expected 1 entry, got %d", len(logged))
+			}
+			if logged[0].Level != tt.level {
+				t.Errorf("Expected %s, got %s", tt.level, logged[0].Level)
+			}
+			if logged[0].Fields["component"] != "cron" {
+				t.Errorf("Expected component=cron, got %v", logged[0].Fields["component"])
+			}
+		})
+	}
+
+	t.Run("should keep key-value pairs", func(t *testing.T) {
+		Logger().Error(errors.New("boom"), "panic", "stack", "trace")
+
+		logged := takeEntries()
+		if logged[0].Fields["stack"] != "trace" || logged[0].Fields["error_message"] != "boom" {
+			t.Errorf("Expected stack and error fields, got %v", logged[0].Fields)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+// Package logfranz logs franz-go produce and consume events through the
+// rcommerz logger with topic, partition, offset, key hash, lag and
+// processing duration, propagating trace context in record headers.
+package logfranz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	logger "github.com/rcommerz/logger-go"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel"
+)
+
+// RecordHandler processes a consumed record
+type RecordHandler func(ctx context.Context, record *kgo.Record) error
+
+// Hooks injects trace context into produced records and logs their
+// delivery. Register it with kgo.WithHooks.
+type Hooks struct{}
+
+var (
+	_ kgo.HookProduceRecordBuffered   = (*Hooks)(nil)
+	_ kgo.HookProduceRecordUnbuffered = (*Hooks)(nil)
+)
+
+// NewHooks returns the client hooks
+func NewHooks() *Hooks {
+	return &Hooks{}
+}
+
+// OnProduceRecordBuffered injects the trace context of the Produce call
+// into the record's headers
+func (h *Hooks) OnProduceRecordBuffered(record *kgo.Record) {
+	if record.Context == nil {
+		return
+	}
+	otel.GetTextMapPropagator().Inject(record.Context, headerCarrier{&record.Headers})
+}
+
+// OnProduceRecordUnbuffered logs the delivered record at DEBUG, or ERROR
+// if it failed
+func (h *Hooks) OnProduceRecordUnbuffered(record *kgo.Record, err error) {
+	ctx := record.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	context := recordContext("publish", record)
+	if err == nil {
+		context["messaging.kafka.partition"] = record.Partition
+		context["messaging.kafka.offset"] = record.Offset
+	}
+	if !record.Timestamp.IsZero() {
+		context["duration_ms"] = time.Since(record.Timestamp).Milliseconds()
+	}
+	logRecord(ctx, "publish", record.Topic, context, err, logger.LevelDEBUG)
+}
+
+// ProcessFetches runs handler for every fetched record, logging each with
+// its consumer lag, and returns the handler errors joined
+func ProcessFetches(ctx context.Context, fetches kgo.Fetches, handler RecordHandler) error {
+	var errs []error
+	fetches.EachPartition(func(partition kgo.FetchTopicPartition) {
+		for _, record := range partition.Records {
+			lag := partition.HighWatermark - record.Offset - 1
+			if err := handleRecord(ctx, record, handler, &lag); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// HandleRecord runs handler with the trace context extracted from the
+// record's headers and logs the processed record at INFO, or ERROR if
+// handler failed
+func HandleRecord(ctx context.Context, record *kgo.Record, handler RecordHandler) error {
+	return handleRecord(ctx, record, handler, nil)
+}
+
+func handleRecord(ctx context.Context, record *kgo.Record, handler RecordHandler, lag *int64) error {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier{&record.Headers})
+
+	startTime := time.Now()
+	err := handler(ctx, record)
+
+	context := recordContext("process", record)
+	context["messaging.kafka.partition"] = record.Partition
+	context["messaging.kafka.offset"] = record.Offset
+	if lag != nil {
+		context["messaging.kafka.lag"] = *lag
+	}
+	context["duration_ms"] = time.Since(startTime).Milliseconds()
+
+	logRecord(ctx, "process", record.Topic, context, err, logger.LevelINFO)
+	return err
+}
+
+// recordContext returns the fields describing a record. The key is logged
+// as a salted hash so records can be correlated without exposing it.
+func recordContext(operation string, record *kgo.Record) logger.LogContext {
+	context := logger.LogContext{
+		"messaging.system":            "kafka",
+		"messaging.operation":         operation,
+		"messaging.destination.name":  record.Topic,
+		"messaging.message.body.size": len(record.Value),
+	}
+	if len(record.Key) > 0 {
+		for key, value := range logger.Sensitive("messaging.kafka.key_hash", string(record.Key)) {
+			context[key] = value
+		}
+	}
+	return context
+}
+
+// logRecord emits a record event at level, or ERROR when it failed
+func logRecord(ctx context.Context, operation, topic string, context logger.LogContext, err error, level logger.LogLevel) {
+	if err != nil {
+		context["error"] = err
+		level = logger.LevelERROR
+	}
+	logger.GetInstance().Log(ctx, level, logger.TypeMessaging, fmt.Sprintf("kafka %s %s", operation, topic), context)
+}
+
+// headerCarrier adapts record headers to an OpenTelemetry TextMapCarrier
+type headerCarrier struct {
+	headers *[]kgo.RecordHeader
+}
+
+func (c headerCarrier) Get(key string) string {
+	for _, header := range *c.headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func (c headerCarrier) Set(key, value string) {
+	for i, header := range *c.headers {
+		if header.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.headers = append(*c.headers, kgo.RecordHeader{Key: key, Value: []byte(value)})
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, len(*c.headers))
+	for i, header := range *c.headers {
+		keys[i] = header.Key
+	}
+	return keys
+}
@@ -0,0 +1,126 @@
+package logfranz
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	logger "github.com/rcommerz/logger-go"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	entriesMu sync.Mutex
+	entries   []logger.Entry
+)
+
+func TestMain(m *testing.M) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	log := logger.Initialize(logger.Config{
+		ServiceName:    "logfranz-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          logger.LevelDEBUG,
+	})
+	log.RegisterHook(func(entry logger.Entry) logger.Entry {
+		entriesMu.Lock()
+		entries = append(entries, entry)
+		entriesMu.Unlock()
+		return entry
+	})
+	os.Exit(m.Run())
+}
+
+// takeEntries returns and clears the entries logged so far
+func takeEntries() []logger.Entry {
+	entriesMu.Lock()
+	defer entriesMu.Unlock()
+	taken := entries
+	entries = nil
+	return taken
+}
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestHooks(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	hooks := NewHooks()
+	record := &kgo.Record{Topic: "orders", Key: []byte("order-1"), Context: ctx, Timestamp: time.Now()}
+	hooks.OnProduceRecordBuffered(record)
+
+	carrier := headerCarrier{&record.Headers}
+	if got := carrier.Get("traceparent"); got != traceparent {
+		t.Errorf("Expected traceparent header %q, got %q", traceparent, got)
+	}
+
+	record.Partition, record.Offset = 1, 99
+	hooks.OnProduceRecordUnbuffered(record, nil)
+	hooks.OnProduceRecordUnbuffered(&kgo.Record{Topic: "orders"}, errors.New("record too large"))
+
+	logged := takeEntries()
+	if len(logged) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(logged))
+	}
+	if logged[0].Level != logger.LevelDEBUG || logged[0].Fields["messaging.kafka.offset"] != int64(99) {
+		t.Errorf("Expected delivered record at DEBUG with offset, got %s %v", logged[0].Level, logged[0].Fields)
+	}
+	if logged[0].Fields["messaging.kafka.key_hash"] == nil {
+		t.Error("Expected hashed key")
+	}
+	if logged[1].Level != logger.LevelERROR {
+		t.Errorf("Expected failed record at ERROR, got %s", logged[1].Level)
+	}
+}
+
+func TestProcessFetches(t *testing.T) {
+	fetches := kgo.Fetches{{Topics: []kgo.FetchTopic{{
+		Topic: "orders",
+		Partitions: []kgo.FetchPartition{{
+			Partition:     3,
+			HighWatermark: 10,
+			Records: []*kgo.Record{
+				{Topic: "orders", Partition: 3, Offset: 7, Headers: []kgo.RecordHeader{{Key: "traceparent", Value: []byte(traceparent)}}},
+				{Topic: "orders", Partition: 3, Offset: 8},
+			},
+		}},
+	}}}}
+
+	var traceIDs []string
+	err := ProcessFetches(context.Background(), fetches, func(ctx context.Context, record *kgo.Record) error {
+		traceIDs = append(traceIDs, trace.SpanContextFromContext(ctx).TraceID().String())
+		if record.Offset == 8 {
+			return errors.New("invalid payload")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Error("Expected handler error to be returned")
+	}
+	if traceIDs[0] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected handler context to carry the record trace, got %q", traceIDs[0])
+	}
+
+	logged := takeEntries()
+	if len(logged) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(logged))
+	}
+	if logged[0].Level != logger.LevelINFO || logged[0].Fields["messaging.kafka.lag"] != int64(2) {
+		t.Errorf("Expected processed record at INFO with lag 2, got %s %v", logged[0].Level, logged[0].Fields)
+	}
+	if logged[1].Level != logger.LevelERROR {
+		t.Errorf("Expected failed record at ERROR, got %s", logged[1].Level)
+	}
+}
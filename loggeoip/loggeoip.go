@@ -0,0 +1,81 @@
+// Package loggeoip adds the location of an entry's client IP, looked up in
+// a MaxMind GeoIP2 or GeoLite2 database, as geo.* fields:
+//
+//	geo, err := loggeoip.Open("/var/lib/GeoIP/GeoLite2-City.mmdb")
+//	if err != nil { ... }
+//	defer geo.Close()
+//	logger.Initialize(logger.Config{ServiceName: "checkout", Enrichment: geo})
+package loggeoip
+
+import (
+	"net"
+	"strings"
+
+	"github.com/oschwald/geoip2-golang"
+	logger "github.com/rcommerz/logger-go"
+)
+
+// Enrichment looks up client IPs in a City or Country database
+type Enrichment struct {
+	reader *geoip2.Reader
+	// city is set for City databases, which also resolve the city
+	city bool
+}
+
+// Open opens the database at path
+func Open(path string) (*Enrichment, error) {
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(reader), nil
+}
+
+// New uses an already opened database
+func New(reader *geoip2.Reader) *Enrichment {
+	return &Enrichment{
+		reader: reader,
+		city:   strings.Contains(reader.Metadata().DatabaseType, "City"),
+	}
+}
+
+// Enrich returns geo.country (ISO code), geo.country_name and, from City
+// databases, geo.city for ip; unknown addresses return nil
+func (e *Enrichment) Enrich(ip net.IP) logger.LogContext {
+	if !e.city {
+		country, err := e.reader.Country(ip)
+		if err != nil {
+			return nil
+		}
+		return countryFields(country.Country.IsoCode, country.Country.Names)
+	}
+
+	city, err := e.reader.City(ip)
+	if err != nil {
+		return nil
+	}
+	fields := countryFields(city.Country.IsoCode, city.Country.Names)
+	if name := city.City.Names["en"]; name != "" {
+		if fields == nil {
+			fields = logger.LogContext{}
+		}
+		fields["geo.city"] = name
+	}
+	return fields
+}
+
+// Close closes the database
+func (e *Enrichment) Close() error {
+	return e.reader.Close()
+}
+
+func countryFields(isoCode string, names map[string]string) logger.LogContext {
+	if isoCode == "" {
+		return nil
+	}
+	fields := logger.LogContext{"geo.country": isoCode}
+	if name := names["en"]; name != "" {
+		fields["geo.country_name"] = name
+	}
+	return fields
+}
@@ -0,0 +1,68 @@
+package loggeoip
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	logger "github.com/rcommerz/logger-go"
+	"github.com/rcommerz/logger-go/logtest"
+)
+
+// testdata/GeoIP2-City-Test.mmdb maps 81.2.69.0/24 to London, GB and
+// 2.125.160.0/24 to GB without a city
+const testDatabase = "testdata/GeoIP2-City-Test.mmdb"
+
+func TestEnrich(t *testing.T) {
+	geo, err := Open(testDatabase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer geo.Close()
+
+	tests := []struct {
+		ip       string
+		expected logger.LogContext
+	}{
+		{"81.2.69.160", logger.LogContext{"geo.country": "GB", "geo.country_name": "United Kingdom", "geo.city": "London"}},
+		{"2.125.160.216", logger.LogContext{"geo.country": "GB", "geo.country_name": "United Kingdom"}},
+		{"203.0.113.7", nil},
+	}
+
+	for _, tt := range tests {
+		fields := geo.Enrich(net.ParseIP(tt.ip))
+		if len(fields) != len(tt.expected) {
+			t.Errorf("Expected %v for %s, got %v", tt.expected, tt.ip, fields)
+		}
+		for key, value := range tt.expected {
+			if fields[key] != value {
+				t.Errorf("Expected %s=%v for %s, got %v", key, value, tt.ip, fields[key])
+			}
+		}
+	}
+}
+
+func TestOpenMissingDatabase(t *testing.T) {
+	if _, err := Open("testdata/missing.mmdb"); err == nil {
+		t.Error("Expected an error for a missing database")
+	}
+}
+
+func TestLoggerEnrichment(t *testing.T) {
+	geo, err := Open(testDatabase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer geo.Close()
+
+	rec := logtest.NewRecorderWithConfig(logger.Config{ServiceName: "geo-test", Enrichment: geo})
+	rec.SecurityLoginFailure(context.Background(), "alice", "81.2.69.160", nil)
+
+	entries := rec.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Fields["geo.country"] != "GB" || entries[0].Fields["geo.city"] != "London" {
+		t.Errorf("Expected geo fields on the security entry, got %v", entries[0].Fields)
+	}
+}
@@ -2,7 +2,10 @@ package logger
 
 import (
 	"context"
+	"encoding/hex"
+	"io"
 	"os"
+	"runtime"
 	"sync"
 
 	"go.opentelemetry.io/otel/trace"
@@ -17,21 +20,82 @@ var (
 
 // Logger is a structured logger wrapper around zap
 type Logger struct {
-	zap    *zap.Logger
-	config Config
+	zap          *zap.Logger
+	config       Config
+	fields       LogContext
+	hooks        *hookChain
+	transformers *transformerSet
+	privacy      *privacyFilter
+	heartbeat    *heartbeat
+	fatalHooks   *fatalHooks
+	levelSignals *levelSignalWatcher
+	// level is the minimum level, shared with children and changed by
+	// SetLevel
+	level zap.AtomicLevel
+	// auditDelivery is the audit sink queue, flushed on Shutdown
+	auditDelivery *auditDelivery
+	// sampler limits repeated entries when Config.Sampling is set
+	sampler *entrySampler
+	// suppressor limits repeated errors when Config.ErrorSuppression is set
+	suppressor *errorSuppressor
+	// quarantine writes entries failing Config.Schema, when it has a
+	// Quarantine writer
+	quarantine *zap.Logger
+	// debug is set on loggers that emit every level (see withDebug)
+	debug bool
+	// typeLevels are the minimum levels of log types, shared with children
+	typeLevels *typeLevels
+	// salt keys the pseudonyms of Sensitive values and hashed IPs and
+	// personal data
+	salt []byte
 }
 
 // Initialize creates and returns a singleton logger instance
 func Initialize(config Config) *Logger {
 	once.Do(func() {
-		instance = &Logger{
-			config: config,
-		}
-		instance.zap = instance.buildZapLogger()
+		instance = New(config)
 	})
 	return instance
 }
 
+// New creates a Logger independent of the singleton, for code that is
+// handed its logger explicitly (e.g. a recorder in tests). Initialize uses
+// it to create the singleton.
+func New(config Config) *Logger {
+	if config.MaskPII {
+		config.Redactor = config.Redactor.WithMaskers(DefaultMaskers()...)
+	}
+
+	l := &Logger{
+		config:       config,
+		hooks:        &hookChain{},
+		transformers: &transformerSet{},
+		fatalHooks:   &fatalHooks{},
+		typeLevels:   newTypeLevels(config.TypeLevels),
+		salt:         sensitiveSalt(config),
+	}
+	if config.Privacy != nil {
+		l.privacy = newPrivacyFilter(config.Privacy, l.salt)
+	}
+	if config.Sampling != nil {
+		l.sampler = newEntrySampler(config.Sampling, config.Clock)
+	}
+	if config.ErrorSuppression != nil {
+		l.suppressor = newErrorSuppressor(l, config.ErrorSuppression)
+	}
+	if config.Heartbeat != nil {
+		l.heartbeat = newHeartbeat(config.Heartbeat)
+	}
+	l.zap = l.buildZapLogger()
+	if l.heartbeat != nil {
+		go l.heartbeat.run(l)
+	}
+	if config.LevelSignals {
+		l.levelSignals = l.watchLevelSignals()
+	}
+	return l
+}
+
 // GetInstance returns the singleton logger instance
 func GetInstance() *Logger {
 	if instance == nil {
@@ -40,9 +104,83 @@ func GetInstance() *Logger {
 	return instance
 }
 
-// buildZapLogger creates a configured zap logger
-func (l *Logger) buildZapLogger() *zap.Logger {
-	encoderConfig := zapcore.EncoderConfig{
+// With returns a child logger that adds fields to every entry it logs.
+// Fields passed to individual calls take precedence.
+func (l *Logger) With(fields LogContext) *Logger {
+	merged := make(LogContext, len(l.fields)+len(fields))
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	child := *l
+	child.fields = merged
+	return &child
+}
+
+// withDebug returns a child logger that emits entries at every level,
+// regardless of the configured minimum level
+func (l *Logger) withDebug() *Logger {
+	child := *l
+	child.zap = l.zap.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return debugCore{core}
+	}))
+	child.debug = true
+	return &child
+}
+
+// debugCore enables all levels on the wrapped core
+type debugCore struct {
+	zapcore.Core
+}
+
+func (c debugCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c debugCore) With(fields []zapcore.Field) zapcore.Core {
+	return debugCore{c.Core.With(fields)}
+}
+
+func (c debugCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checked.AddCore(entry, c)
+}
+
+// newEncoder creates the JSON encoder used for all output
+func newEncoder() zapcore.Encoder {
+	return zapcore.NewJSONEncoder(encoderConfig())
+}
+
+// outputEncoderConfig returns encoderConfig with Config.TimeFormat applied
+func (l *Logger) outputEncoderConfig() zapcore.EncoderConfig {
+	cfg := encoderConfig()
+	if l.config.TimeFormat == TimeFormatEpochMillis {
+		cfg.EncodeTime = zapcore.EpochMillisTimeEncoder
+	}
+	return cfg
+}
+
+// newOutputEncoder creates the encoder selected by Config.Encoding
+func (l *Logger) newOutputEncoder() zapcore.Encoder {
+	cfg := l.outputEncoderConfig()
+
+	switch l.config.Encoding {
+	case EncodingConsole:
+		return zapcore.NewConsoleEncoder(cfg)
+	case EncodingMsgpack:
+		return newMsgpackEncoder(cfg)
+	case EncodingOTLP:
+		return newOTLPEncoder(cfg)
+	default:
+		return zapcore.NewJSONEncoder(cfg)
+	}
+}
+
+// encoderConfig returns the field names and formats shared by all encoders
+func encoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
 		TimeKey:        "@timestamp",
 		LevelKey:       "log.level",
 		NameKey:        "logger",
@@ -56,71 +194,196 @@ func (l *Logger) buildZapLogger() *zap.Logger {
 		EncodeDuration: zapcore.SecondsDurationEncoder,
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
+}
 
+// buildZapLogger creates a configured zap logger
+func (l *Logger) buildZapLogger() *zap.Logger {
 	hostname, _ := os.Hostname()
 
+	encoder := l.newOutputEncoder()
+	if l.config.SecurityFormat != nil && l.config.Encoding != EncodingMsgpack && l.config.Encoding != EncodingOTLP {
+		encoder = newSecurityEncoder(encoder, l.config.SecurityFormat)
+	}
+
+	l.level = zap.NewAtomicLevelAt(l.getZapLevel())
 	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderConfig),
-		zapcore.AddSync(os.Stdout),
-		l.getZapLevel(),
+		encoder,
+		zapcore.AddSync(l.output()),
+		l.level,
 	)
 
-	logger := zap.New(core)
+	if l.config.ErrorRateAlert != nil {
+		core = &errorRateCore{Core: core, tracker: newErrorRateTracker(*l.config.ErrorRateAlert)}
+	}
+	if l.config.Metrics != nil {
+		core = &metricsCore{Core: core, metrics: l.config.Metrics}
+	}
+	if l.heartbeat != nil {
+		core = &heartbeatCore{Core: core, heartbeat: l.heartbeat}
+	}
+
+	if l.config.AuditChain != nil || l.config.AuditDelivery != nil {
+		// Audit entries are hashed and delivered as JSON, formatted like the
+		// output so chain hashes match the written lines
+		audit := &auditCore{Core: core, enc: zapcore.NewJSONEncoder(l.outputEncoderConfig())}
+		if l.config.AuditChain != nil {
+			audit.chain = newAuditChain(l.config.AuditChain)
+		}
+		if l.config.AuditDelivery != nil {
+			audit.delivery = newAuditDelivery(l.config.AuditDelivery, l.config.Metrics)
+			l.auditDelivery = audit.delivery
+		}
+		core = audit
+	}
+	if l.config.EnableFunction {
+		core = &functionCore{Core: core, caller: l.config.EnableCaller}
+	}
 
 	// Add constant fields
-	logger = logger.With(
+	constants := []zap.Field{
 		zap.String("service.name", l.config.ServiceName),
 		zap.String("service.version", l.config.ServiceVersion),
 		zap.String("env", l.config.Env),
 		zap.String("host.name", hostname),
-	)
+		zap.String("log_schema_version", LogSchemaVersion),
+	}
+	if l.config.ProcessFields {
+		constants = append(constants, processFields()...)
+	}
+	if l.config.BuildMetadata {
+		constants = append(constants, buildMetadataFields()...)
+	}
+	if l.config.CloudMetadata != nil {
+		if metadata, ok := detectCloudMetadata(l.config.CloudMetadata); ok {
+			constants = append(constants, metadata.fields()...)
+		}
+	}
 
-	return logger
+	if l.config.Schema != nil && l.config.Schema.Quarantine != nil {
+		l.quarantine = l.newQuarantineLogger(constants)
+	}
+	return zap.New(core, l.zapOptions()...).With(constants...)
+}
+
+// output returns the writer entries are encoded to
+func (l *Logger) output() io.Writer {
+	if l.config.Output != nil {
+		return l.config.Output
+	}
+	return os.Stdout
 }
 
-// getZapLevel converts LogLevel to zapcore.Level
+// zapOptions returns the options of the zap logger
+func (l *Logger) zapOptions() []zap.Option {
+	// Skip Logger.log and the public method that called it, for both the
+	// caller and stack traces
+	options := []zap.Option{zap.AddCallerSkip(2 + l.config.CallerSkip)}
+	if l.config.EnableCaller || l.config.EnableFunction {
+		options = append(options, zap.AddCaller())
+	}
+	if l.config.StackTraces {
+		options = append(options, zap.AddStacktrace(zapcore.ErrorLevel))
+	}
+	if l.config.Clock != nil {
+		options = append(options, zap.WithClock(zapClock{l.config.Clock}))
+	}
+	return options
+}
+
+// getZapLevel converts the configured LogLevel to zapcore.Level
 func (l *Logger) getZapLevel() zapcore.Level {
-	switch l.config.Level {
+	return zapLevel(l.config.Level)
+}
+
+// zapLevel converts LogLevel to zapcore.Level
+func zapLevel(level LogLevel) zapcore.Level {
+	switch level {
 	case LevelDEBUG:
 		return zapcore.DebugLevel
 	case LevelWARN:
 		return zapcore.WarnLevel
 	case LevelERROR:
 		return zapcore.ErrorLevel
+	case LevelOFF:
+		return zapcore.InvalidLevel
 	default:
 		return zapcore.InfoLevel
 	}
 }
 
-// getTraceContext extracts trace_id and span_id from OpenTelemetry context
-func (l *Logger) getTraceContext(ctx context.Context) []zap.Field {
+// fieldPool reuses the field slices entries are built in. Cores encode or
+// copy the fields they are given, so a slice is free again once the entry
+// is written.
+var fieldPool = sync.Pool{
+	New: func() interface{} {
+		fields := make([]zap.Field, 0, 16)
+		return &fields
+	},
+}
+
+// maxPooledFields bounds the slices kept in fieldPool, so one entry with
+// many fields does not pin a large slice
+const maxPooledFields = 256
+
+// appendTraceContext appends trace_id and span_id from the OpenTelemetry
+// span in ctx. Both are hex-encoded into a single string to allocate once.
+func appendTraceContext(fields []zap.Field, ctx context.Context) []zap.Field {
 	if ctx == nil {
-		return []zap.Field{}
+		return fields
 	}
 
-	spanContext := trace.SpanFromContext(ctx).SpanContext()
+	spanContext := trace.SpanContextFromContext(ctx)
 	if !spanContext.IsValid() {
-		return []zap.Field{}
+		return fields
 	}
 
-	return []zap.Field{
-		zap.String("trace_id", spanContext.TraceID().String()),
-		zap.String("span_id", spanContext.SpanID().String()),
-	}
+	traceID, spanID := spanContext.TraceID(), spanContext.SpanID()
+	var buf [48]byte
+	hex.Encode(buf[:32], traceID[:])
+	hex.Encode(buf[32:], spanID[:])
+	ids := string(buf[:])
+	return append(fields,
+		zap.String("trace_id", ids[:32]),
+		zap.String("span_id", ids[32:]),
+	)
 }
 
-// buildFields converts LogContext to zap.Field array
-func (l *Logger) buildFields(ctx context.Context, logType LogType, context LogContext) []zap.Field {
-	fields := []zap.Field{
-		zap.String("log_type", string(logType)),
+// appendBaseFields appends the log type and trace context fields
+func (l *Logger) appendBaseFields(fields []zap.Field, ctx context.Context, logType LogType) []zap.Field {
+	fields = append(fields, zap.String("log_type", string(logType)))
+	if l.config.GoroutineCount {
+		fields = append(fields, zap.Int("runtime.goroutines", runtime.NumGoroutine()))
 	}
 
 	// Add trace context
-	fields = append(fields, l.getTraceContext(ctx)...)
+	return appendTraceContext(fields, ctx)
+}
+
+// appendFields converts LogContext to zap fields, appended to fields
+func (l *Logger) appendFields(fields []zap.Field, ctx context.Context, logType LogType, context LogContext) []zap.Field {
+	fields = l.appendBaseFields(fields, ctx, logType)
+	transformers := l.transformers.list()
+
+	// Add request-scoped fields carried by the context; explicit fields win
+	scoped := ContextFields(ctx)
+	for key, value := range scoped {
+		if _, ok := context[key]; !ok {
+			fields = append(fields, l.field(transformers, key, value))
+		}
+	}
+
+	// Add child logger fields unless overridden by the call or the context
+	for key, value := range l.fields {
+		_, explicit := context[key]
+		_, inScope := scoped[key]
+		if !explicit && !inScope {
+			fields = append(fields, l.field(transformers, key, value))
+		}
+	}
 
 	// Add custom context fields
 	for key, value := range context {
-		fields = append(fields, zap.Any(key, value))
+		fields = append(fields, l.field(transformers, key, value))
 	}
 
 	return fields
@@ -128,51 +391,213 @@ func (l *Logger) buildFields(ctx context.Context, logType LogType, context LogCo
 
 // Info logs an informational message
 func (l *Logger) Info(ctx context.Context, message string, context LogContext) {
-	fields := l.buildFields(ctx, TypeNormal, context)
-	l.zap.Info(message, fields...)
+	l.log(ctx, 0, LevelINFO, TypeNormal, message, context)
 }
 
-// Error logs an error message
-func (l *Logger) Error(ctx context.Context, message string, context LogContext) {
-	// Handle error objects
+// normalizeError flattens an error object stored under "error" into
+// error_message and error_type fields
+func normalizeError(context LogContext) {
 	if err, ok := context["error"].(error); ok {
 		context["error_message"] = err.Error()
 		context["error_type"] = "error"
 		delete(context, "error")
 	}
+}
 
-	fields := l.buildFields(ctx, TypeError, context)
-	l.zap.Error(message, fields...)
+// Error logs an error message
+func (l *Logger) Error(ctx context.Context, message string, context LogContext) {
+	l.log(ctx, 0, LevelERROR, TypeError, message, context)
 }
 
 // Warn logs a warning message
 func (l *Logger) Warn(ctx context.Context, message string, context LogContext) {
-	fields := l.buildFields(ctx, TypeNormal, context)
-	l.zap.Warn(message, fields...)
+	l.log(ctx, 0, LevelWARN, TypeNormal, message, context)
 }
 
 // Debug logs a debug message
 func (l *Logger) Debug(ctx context.Context, message string, context LogContext) {
-	fields := l.buildFields(ctx, TypeDebug, context)
-	l.zap.Debug(message, fields...)
+	l.log(ctx, 0, LevelDEBUG, TypeDebug, message, context)
 }
 
 // HTTP logs an HTTP request/response
 func (l *Logger) HTTP(ctx context.Context, message string, context LogContext) {
-	fields := l.buildFields(ctx, TypeHTTP, context)
-	l.zap.Info(message, fields...)
+	l.log(ctx, 0, LevelINFO, TypeHTTP, message, context)
 }
 
 // Security logs a security-related event
 func (l *Logger) Security(ctx context.Context, message string, context LogContext) {
-	fields := l.buildFields(ctx, TypeSecurity, context)
-	l.zap.Warn(message, fields...)
+	l.log(ctx, 0, LevelWARN, TypeSecurity, message, context)
 }
 
 // Audit logs an audit trail event
 func (l *Logger) Audit(ctx context.Context, message string, context LogContext) {
-	fields := l.buildFields(ctx, TypeAudit, context)
-	l.zap.Info(message, fields...)
+	l.log(ctx, 0, LevelINFO, TypeAudit, message, context)
+}
+
+// Log logs a message at an explicit level and log type. It is intended for
+// integrations that emit their own log types (e.g. TypeRPC) and for types
+// defined with RegisterLogType.
+func (l *Logger) Log(ctx context.Context, level LogLevel, logType LogType, message string, context LogContext) {
+	l.log(ctx, 0, level, logType, message, context)
+}
+
+// log writes an entry for Log and the level methods. Both call it
+// directly so the reported caller is the caller of the public method;
+// skip adds frames for internal helpers in between.
+func (l *Logger) log(ctx context.Context, skip int, level LogLevel, logType LogType, message string, context LogContext) {
+	out := l.zap
+	if minLevel, ok := l.typeLevel(logType); ok && !l.debug {
+		// The type's own minimum level replaces the configured Level
+		if zapLevel(level) < zapLevel(minLevel) {
+			return
+		}
+		if !out.Core().Enabled(zapLevel(level)) {
+			out = l.withDebug().zap
+		}
+	} else if !out.Core().Enabled(zapLevel(level)) {
+		return
+	}
+
+	if l.sampler != nil && !l.debug && !l.sampler.allow(level, logType, message) {
+		if l.config.Metrics != nil {
+			l.config.Metrics.EntryDropped("sampled")
+		}
+		return
+	}
+
+	if skip > 0 && (l.config.EnableCaller || l.config.StackTraces) {
+		out = out.WithOptions(zap.AddCallerSkip(skip))
+	}
+
+	// Classify failures before their error objects are flattened
+	var added LogContext
+	if l.config.ClassifyErrors && (level == LevelERROR || level == LevelWARN) {
+		if class := classifyError(context); class != "" {
+			added = LogContext{"error.class": string(class)}
+		}
+	}
+
+	// Handle error objects
+	var fingerprint string
+	if level == LevelERROR {
+		if l.config.ErrorFingerprint || l.suppressor != nil {
+			fingerprint = errorFingerprint(skip, message, context)
+		}
+		normalizeError(context)
+
+		if l.config.ErrorFingerprint {
+			if added == nil {
+				added = LogContext{}
+			}
+			added["error.fingerprint"] = fingerprint
+		}
+	}
+
+	// Copy rather than add the fields to the caller's map
+	if added != nil {
+		for key, value := range context {
+			if _, ok := added[key]; !ok {
+				added[key] = value
+			}
+		}
+		context = added
+	}
+
+	if level == LevelERROR && l.suppressor != nil && !l.debug && logType != TypeAudit && logType != TypeSecurity &&
+		!l.suppressor.allow(fingerprint, message, context) {
+		if l.config.Metrics != nil {
+			l.config.Metrics.EntryDropped("suppressed")
+		}
+		return
+	}
+
+	pooled := fieldPool.Get().(*[]zap.Field)
+	defer func() {
+		if cap(*pooled) <= maxPooledFields {
+			fieldPool.Put(pooled)
+		}
+	}()
+
+	fields := (*pooled)[:0]
+	hooks, redactor := l.hooks.list(), l.config.Redactor
+	if len(hooks) > 0 || redactor != nil || l.privacy != nil || l.config.Schema != nil || l.config.Enrichment != nil ||
+		l.config.AnonymizeIP != "" || l.config.FieldShape != "" || l.config.FieldNamespace != "" {
+		entry, ok := runHooks(hooks, Entry{
+			Context: ctx,
+			Level:   level,
+			Type:    logType,
+			Message: message,
+			Fields:  l.mergeFields(ctx, context),
+		})
+		if !ok && (logType != TypeAudit || l.config.AuditDelivery == nil) {
+			return
+		}
+
+		// Enrich from the client IP before it can be redacted
+		if l.config.Enrichment != nil {
+			enrich(l.config.Enrichment, entry.Fields)
+		}
+		if l.config.AnonymizeIP != "" {
+			anonymizeIPFields(l.config.AnonymizeIP, l.salt, entry.Fields)
+		}
+
+		// Redact after hooks so fields they add are covered too
+		if redactor != nil {
+			entry.Message = redactor.RedactString(entry.Message)
+			entry.Fields = redactor.Redact(entry.Fields)
+		}
+		if l.privacy != nil {
+			entry.Fields = l.privacy.apply(entry.Fields)
+		}
+
+		// Validate the fields as logged, before they are reshaped
+		var violations []string
+		if l.config.Schema != nil {
+			if violations = l.config.Schema.validate(entry.Type, entry.Fields); len(violations) > 0 {
+				if out, ok = l.schemaViolation(out, skip, entry.Type); !ok {
+					return
+				}
+			}
+		}
+		entry.Fields = shapeFields(entry.Fields, l.config.FieldShape)
+
+		level, message = entry.Level, entry.Message
+		fields = l.appendBaseFields(fields, ctx, entry.Type)
+		if len(violations) > 0 {
+			fields = append(fields, zap.Strings("schema_violations", violations))
+		}
+		transformers := l.transformers.list()
+		if namespace := l.config.FieldNamespace; namespace != "" {
+			if len(entry.Fields) > 0 {
+				fields = append(fields, zap.Object(namespace, namespacedFields{l, transformers, entry.Fields}))
+			}
+		} else {
+			for key, value := range entry.Fields {
+				fields = append(fields, l.field(transformers, key, value))
+			}
+		}
+	} else {
+		fields = l.appendFields(fields, ctx, logType, context)
+	}
+
+	if limit := l.config.MaxMessageBytes; limit > 0 {
+		message = truncateString(message, limit)
+	}
+
+	switch level {
+	case LevelDEBUG:
+		out.Debug(message, fields...)
+	case LevelWARN:
+		out.Warn(message, fields...)
+	case LevelERROR:
+		out.Error(message, fields...)
+	default:
+		out.Info(message, fields...)
+	}
+
+	// Release the field values and keep the grown slice for reuse
+	clear(fields)
+	*pooled = fields[:0]
 }
 
 // Sync flushes any buffered log entries (call before app shutdown)
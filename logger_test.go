@@ -1,7 +1,9 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -602,3 +604,180 @@ func TestMeasureDuration(t *testing.T) {
 // - Fast and simple
 // - Good for CI/CD
 // - Current approach in main test files
+
+// setupObservedLogger resets the singleton and returns it wired to an observer core
+func setupObservedLogger(config Config) (*Logger, *observer.ObservedLogs) {
+	instance = nil
+	once = sync.Once{}
+
+	observedCore, observedLogs := observer.New(zapcore.DebugLevel)
+	logger := Initialize(config)
+	logger.zap = zap.New(observedCore)
+
+	return logger, observedLogs
+}
+
+func TestLogWithExplicitType(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "log-type-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	tests := []struct {
+		level    LogLevel
+		expected zapcore.Level
+	}{
+		{LevelDEBUG, zapcore.DebugLevel},
+		{LevelINFO, zapcore.InfoLevel},
+		{LevelWARN, zapcore.WarnLevel},
+		{LevelERROR, zapcore.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.level), func(t *testing.T) {
+			observedLogs.TakeAll()
+			logger.Log(context.Background(), tt.level, TypeRPC, "rpc call", Fields(
+				"error", &testError{msg: "rpc failed"},
+			))
+
+			logs := observedLogs.TakeAll()
+			if len(logs) != 1 {
+				t.Fatalf("Expected 1 log entry, got %d", len(logs))
+			}
+			if logs[0].Level != tt.expected {
+				t.Errorf("Expected level %v, got %v", tt.expected, logs[0].Level)
+			}
+
+			fields := logs[0].ContextMap()
+			if fields["log_type"] != "rpc" {
+				t.Errorf("Expected log_type=rpc, got %v", fields["log_type"])
+			}
+
+			_, normalized := fields["error_message"]
+			if normalized != (tt.level == LevelERROR) {
+				t.Errorf("Expected error normalization only at ERROR level, got error_message=%v", fields["error_message"])
+			}
+		})
+	}
+}
+
+func TestTypeLevels(t *testing.T) {
+	logger, _ := setupObservedLogger(Config{
+		ServiceName:    "type-levels-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelERROR,
+		TypeLevels: map[LogType]LogLevel{
+			TypeHTTP:  LevelWARN,
+			TypeDebug: LevelOFF,
+			TypeAudit: LevelDEBUG,
+		},
+	})
+	observedCore, observedLogs := observer.New(zapcore.ErrorLevel)
+	logger.zap = zap.New(observedCore)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		log    func()
+		logged bool
+	}{
+		{"http below type level", func() { logger.HTTP(ctx, "GET /", nil) }, false},
+		{"http at type level", func() { logger.Log(ctx, LevelWARN, TypeHTTP, "GET / 500", nil) }, true},
+		{"audit below global level", func() { logger.Audit(ctx, "role granted", nil) }, true},
+		{"debug type off", func() { logger.Log(ctx, LevelERROR, TypeDebug, "dump", nil) }, false},
+		{"other types use global level", func() { logger.Warn(ctx, "slow", nil) }, false},
+		{"debug logger ignores type levels", func() { logger.withDebug().Debug(ctx, "dump", nil) }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observedLogs.TakeAll()
+			tt.log()
+			if logged := observedLogs.Len() == 1; logged != tt.logged {
+				t.Errorf("Expected logged=%v, got %d entries", tt.logged, observedLogs.Len())
+			}
+		})
+	}
+}
+
+func TestWith(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "with-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	child := logger.With(LogContext{"component": "checkout", "region": "eu"})
+	grandchild := child.With(LogContext{"step": "payment"})
+
+	grandchild.Info(context.Background(), "Charging card", Fields("region", "us"))
+	logger.Info(context.Background(), "Parent entry", nil)
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 2 {
+		t.Fatalf("Expected 2 log entries, got %d", len(logs))
+	}
+
+	fields := logs[0].ContextMap()
+	if fields["component"] != "checkout" || fields["step"] != "payment" {
+		t.Errorf("Expected inherited child fields, got %v", fields)
+	}
+	if fields["region"] != "us" {
+		t.Errorf("Expected call fields to override child fields, got %v", fields["region"])
+	}
+
+	if _, leaked := logs[1].ContextMap()["component"]; leaked {
+		t.Error("Expected parent logger to be unaffected by With")
+	}
+}
+
+func TestNew(t *testing.T) {
+	instance = nil
+	once = sync.Once{}
+
+	var output bytes.Buffer
+	logger := New(Config{ServiceName: "new-test", Level: LevelINFO, Output: &output})
+	logger.Info(context.Background(), "hello", nil)
+
+	if !strings.Contains(output.String(), `"message":"hello"`) {
+		t.Errorf("Expected entry written to the output, got %q", output.String())
+	}
+	if instance != nil {
+		t.Error("Expected New not to initialize the singleton")
+	}
+}
+
+func TestWrappedCores(t *testing.T) {
+	wrap := map[string]func(zapcore.Core) zapcore.Core{
+		"buffer":     func(core zapcore.Core) zapcore.Core { return &bufferCore{next: core} },
+		"error rate": func(core zapcore.Core) zapcore.Core { return &errorRateCore{Core: core} },
+		"function":   func(core zapcore.Core) zapcore.Core { return &functionCore{Core: core, caller: true} },
+		"heartbeat":  func(core zapcore.Core) zapcore.Core { return &heartbeatCore{Core: core} },
+		"metrics":    func(core zapcore.Core) zapcore.Core { return &metricsCore{Core: core} },
+	}
+	for name, wrapCore := range wrap {
+		t.Run(name, func(t *testing.T) {
+			observed, _ := observer.New(zapcore.InfoLevel)
+			core := wrapCore(observed).With([]zapcore.Field{zap.String("service", "orders")})
+
+			if core.Check(zapcore.Entry{Level: zapcore.DebugLevel}, nil) != nil {
+				t.Error("Expected a disabled level not to be checked")
+			}
+			if core.Check(zapcore.Entry{Level: zapcore.InfoLevel}, nil) == nil {
+				t.Fatal("Expected an enabled level to be checked")
+			}
+			if err := core.Sync(); err != nil {
+				t.Errorf("Sync: %v", err)
+			}
+		})
+	}
+
+	debug := debugCore{zapcore.NewNopCore()}.With(nil)
+	if debug.Check(zapcore.Entry{Level: zapcore.DebugLevel}, nil) == nil {
+		t.Error("Expected the debug core to check every level")
+	}
+}
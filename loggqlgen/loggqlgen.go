@@ -0,0 +1,142 @@
+// Package loggqlgen provides a gqlgen handler extension that logs every
+// GraphQL operation through the rcommerz logger.
+package loggqlgen
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	logger "github.com/rcommerz/logger-go"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Options configures the GraphQL logging extension
+type Options struct {
+	// Logger, when set, logs operations instead of the singleton
+	Logger *logger.Logger
+	// IncludeIntrospection logs __schema/__type queries, which are skipped by default
+	IncludeIntrospection bool
+	// IncludeQuery adds the raw query document to each entry
+	IncludeQuery bool
+}
+
+// Extension logs operation name, type, complexity, errors and duration per
+// GraphQL request. Register it with handler.Server.Use.
+type Extension struct {
+	opts   Options
+	logger *logger.Logger
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = (*Extension)(nil)
+
+// New returns a GraphQL logging extension
+func New(opts *Options) *Extension {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	log := opts.Logger
+	if log == nil {
+		log = logger.GetInstance()
+	}
+
+	return &Extension{
+		opts:   *opts,
+		logger: log,
+	}
+}
+
+// ExtensionName implements graphql.HandlerExtension
+func (e *Extension) ExtensionName() string {
+	return "RcommerzLogger"
+}
+
+// Validate implements graphql.HandlerExtension
+func (e *Extension) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse implements graphql.ResponseInterceptor
+func (e *Extension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	oc := graphql.GetOperationContext(ctx)
+	if !e.opts.IncludeIntrospection && isIntrospection(oc) {
+		return next(ctx)
+	}
+
+	resp := next(ctx)
+
+	start := oc.Stats.OperationStart
+	if start.IsZero() {
+		start = time.Now()
+	}
+
+	operationName := oc.OperationName
+	if operationName == "" {
+		operationName = "anonymous"
+	}
+
+	operationType := "unknown"
+	if oc.Operation != nil {
+		operationType = string(oc.Operation.Operation)
+	}
+
+	context := logger.LogContext{
+		"graphql.operation.name": operationName,
+		"graphql.operation.type": operationType,
+		"duration_ms":            time.Since(start).Milliseconds(),
+	}
+
+	if stats := extension.GetComplexityStats(ctx); stats != nil {
+		context["graphql.complexity"] = stats.Complexity
+		context["graphql.complexity_limit"] = stats.ComplexityLimit
+	}
+
+	if e.opts.IncludeQuery {
+		context["graphql.query"] = oc.RawQuery
+	}
+
+	message := fmt.Sprintf("GraphQL %s %s", operationType, operationName)
+
+	if resp != nil && len(resp.Errors) > 0 {
+		messages := make([]string, 0, len(resp.Errors))
+		for _, err := range resp.Errors {
+			messages = append(messages, err.Message)
+		}
+		context["graphql.errors"] = messages
+		context["graphql.error_count"] = len(resp.Errors)
+		e.logger.Log(ctx, logger.LevelERROR, logger.TypeHTTP, message, context)
+		return resp
+	}
+
+	e.logger.HTTP(ctx, message, context)
+	return resp
+}
+
+// isIntrospection reports whether every top-level field of the operation is
+// an introspection field
+func isIntrospection(oc *graphql.OperationContext) bool {
+	if oc.Operation == nil || len(oc.Operation.SelectionSet) == 0 {
+		return false
+	}
+
+	for _, selection := range oc.Operation.SelectionSet {
+		field, ok := selection.(*ast.Field)
+		if !ok {
+			return false
+		}
+		if field.Name != "__schema" && field.Name != "__type" && field.Name != "__typename" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,198 @@
+package loggqlgen
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/testserver"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	logger "github.com/rcommerz/logger-go"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestMain(m *testing.M) {
+	logger.Initialize(logger.Config{
+		ServiceName:    "loggqlgen-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          logger.LevelDEBUG,
+	})
+	os.Exit(m.Run())
+}
+
+// newRecordingLogger returns a logger discarding its output and a function
+// returning and clearing the entries logged so far
+func newRecordingLogger() (*logger.Logger, func() []logger.Entry) {
+	var mu sync.Mutex
+	var entries []logger.Entry
+
+	log := logger.New(logger.Config{ServiceName: "loggqlgen-test", Level: logger.LevelDEBUG, Output: io.Discard})
+	log.RegisterHook(func(entry logger.Entry) logger.Entry {
+		mu.Lock()
+		entries = append(entries, entry)
+		mu.Unlock()
+		return entry
+	})
+
+	return log, func() []logger.Entry {
+		mu.Lock()
+		defer mu.Unlock()
+		taken := entries
+		entries = nil
+		return taken
+	}
+}
+
+func post(t *testing.T, h http.Handler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest("POST", "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestExtension(t *testing.T) {
+	t.Run("should pass through successful operations", func(t *testing.T) {
+		srv := testserver.New()
+		srv.AddTransport(transport.POST{})
+		srv.Use(New(nil))
+
+		rec := post(t, srv, `{"query":"query GetName { name }"}`)
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), `"name":"test"`) {
+			t.Errorf("Expected data in response, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("should log the operation name, type, complexity and duration", func(t *testing.T) {
+		log, takeEntries := newRecordingLogger()
+		srv := testserver.New()
+		srv.AddTransport(transport.POST{})
+		srv.Use(extension.FixedComplexityLimit(100))
+		srv.Use(New(&Options{Logger: log}))
+		srv.SetCalculatedComplexity(12)
+
+		post(t, srv, `{"query":"query GetName { name }","operationName":"GetName"}`)
+
+		entries := takeEntries()
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(entries))
+		}
+		entry := entries[0]
+		if entry.Message != "GraphQL query GetName" || entry.Level != logger.LevelINFO || entry.Type != logger.TypeHTTP {
+			t.Errorf("Unexpected entry: %s %s %q", entry.Level, entry.Type, entry.Message)
+		}
+		want := logger.LogContext{
+			"graphql.operation.name":   "GetName",
+			"graphql.operation.type":   "query",
+			"graphql.complexity":       12,
+			"graphql.complexity_limit": 100,
+		}
+		for key, value := range want {
+			if entry.Fields[key] != value {
+				t.Errorf("Expected %s=%v, got %v", key, value, entry.Fields[key])
+			}
+		}
+		if _, ok := entry.Fields["duration_ms"].(int64); !ok {
+			t.Errorf("Expected duration_ms, got %v", entry.Fields["duration_ms"])
+		}
+		if _, ok := entry.Fields["graphql.query"]; ok {
+			t.Error("Expected no query without IncludeQuery")
+		}
+	})
+
+	t.Run("should skip introspection unless included", func(t *testing.T) {
+		log, takeEntries := newRecordingLogger()
+		srv := testserver.New()
+		srv.AddTransport(transport.POST{})
+		srv.Use(New(&Options{Logger: log}))
+
+		post(t, srv, `{"query":"{ __typename }"}`)
+		if entries := takeEntries(); len(entries) != 0 {
+			t.Errorf("Expected introspection not logged, got %d entries", len(entries))
+		}
+
+		srv = testserver.New()
+		srv.AddTransport(transport.POST{})
+		srv.Use(New(&Options{Logger: log, IncludeIntrospection: true}))
+
+		post(t, srv, `{"query":"{ __typename }"}`)
+		if entries := takeEntries(); len(entries) != 1 {
+			t.Errorf("Expected included introspection logged, got %d entries", len(entries))
+		}
+	})
+
+	t.Run("should log resolver errors at ERROR", func(t *testing.T) {
+		log, takeEntries := newRecordingLogger()
+		srv := testserver.NewError()
+		srv.AddTransport(transport.POST{})
+		srv.Use(New(&Options{Logger: log, IncludeQuery: true}))
+
+		post(t, srv, `{"query":"{ name }"}`)
+
+		entries := takeEntries()
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(entries))
+		}
+		entry := entries[0]
+		if entry.Message != "GraphQL query anonymous" || entry.Level != logger.LevelERROR {
+			t.Errorf("Unexpected entry: %s %q", entry.Level, entry.Message)
+		}
+		if errs, _ := entry.Fields["graphql.errors"].([]string); len(errs) != 1 || errs[0] != "resolver error" {
+			t.Errorf("Expected the resolver error, got %v", entry.Fields["graphql.errors"])
+		}
+		if entry.Fields["graphql.error_count"] != 1 || entry.Fields["graphql.query"] != "{ name }" {
+			t.Errorf("Expected the error count and query, got %v", entry.Fields)
+		}
+	})
+
+	t.Run("should pass through resolver errors", func(t *testing.T) {
+		srv := testserver.NewError()
+		srv.AddTransport(transport.POST{})
+		srv.Use(New(&Options{IncludeQuery: true}))
+
+		rec := post(t, srv, `{"query":"{ name }"}`)
+		if !strings.Contains(rec.Body.String(), `"errors"`) {
+			t.Errorf("Expected errors in response, got %s", rec.Body.String())
+		}
+	})
+}
+
+func TestIsIntrospection(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   []string
+		expected bool
+	}{
+		{"schema query", []string{"__schema"}, true},
+		{"type query", []string{"__type", "__typename"}, true},
+		{"mixed query", []string{"__schema", "name"}, false},
+		{"regular query", []string{"name"}, false},
+		{"empty selection", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := &ast.OperationDefinition{Operation: ast.Query}
+			for _, name := range tt.fields {
+				op.SelectionSet = append(op.SelectionSet, &ast.Field{Name: name})
+			}
+
+			got := isIntrospection(&graphql.OperationContext{Operation: op})
+			if got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
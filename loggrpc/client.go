@@ -0,0 +1,148 @@
+package loggrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	logger "github.com/rcommerz/logger-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor returns a gRPC unary client interceptor that logs
+// every outbound call with its target, status code and latency
+func UnaryClientInterceptor(opts *Options) grpc.UnaryClientInterceptor {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	log := opts.logger()
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		if opts.excluded(method) {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+
+		startTime := time.Now()
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+
+		context := clientContext(ctx, method, cc, err)
+		context["grpc.type"] = "unary"
+		context["duration_ms"] = time.Since(startTime).Milliseconds()
+		if size, ok := messageSize(req); ok {
+			context["request_bytes"] = size
+		}
+		if size, ok := messageSize(reply); ok && err == nil {
+			context["response_bytes"] = size
+		}
+
+		logCall(ctx, log, method, context, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a gRPC stream client interceptor that logs
+// every outbound stream once it finishes
+func StreamClientInterceptor(opts *Options) grpc.StreamClientInterceptor {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	log := opts.logger()
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if opts.excluded(method) {
+			return streamer(ctx, desc, cc, method, callOpts...)
+		}
+
+		startTime := time.Now()
+		finish := func(err error, sent, received int) {
+			context := clientContext(ctx, method, cc, err)
+			context["grpc.type"] = streamType(desc.ClientStreams, desc.ServerStreams)
+			context["duration_ms"] = time.Since(startTime).Milliseconds()
+			context["messages_sent"] = sent
+			context["messages_received"] = received
+
+			logCall(ctx, log, method, context, err)
+		}
+
+		stream, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			finish(err, 0, 0)
+			return nil, err
+		}
+
+		return &loggingClientStream{
+			ClientStream:  stream,
+			finish:        finish,
+			serverStreams: desc.ServerStreams,
+		}, nil
+	}
+}
+
+// clientContext builds the fields for an outbound call, classifying any
+// failure from its status code
+func clientContext(ctx context.Context, method string, cc *grpc.ClientConn, err error) logger.LogContext {
+	context := callContext(ctx, method, err)
+	if cc != nil {
+		context["grpc.target"] = cc.Target()
+	}
+	if err != nil {
+		context["error.class"] = string(classForCode(status.Code(err)))
+	}
+	return context
+}
+
+// loggingClientStream logs the stream outcome the first time it terminates
+type loggingClientStream struct {
+	grpc.ClientStream
+	finish        func(err error, sent, received int)
+	serverStreams bool
+	once          sync.Once
+	mu            sync.Mutex
+	sent          int
+	received      int
+}
+
+func (s *loggingClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.mu.Lock()
+		s.sent++
+		s.mu.Unlock()
+	} else if !errors.Is(err, io.EOF) {
+		s.done(err)
+	}
+	return err
+}
+
+func (s *loggingClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		s.mu.Lock()
+		s.received++
+		s.mu.Unlock()
+		// Client-streaming calls end after their single response
+		if !s.serverStreams {
+			s.done(nil)
+		}
+	case errors.Is(err, io.EOF):
+		s.done(nil)
+	default:
+		s.done(err)
+	}
+	return err
+}
+
+func (s *loggingClientStream) done(err error) {
+	s.once.Do(func() {
+		s.mu.Lock()
+		sent, received := s.sent, s.received
+		s.mu.Unlock()
+		s.finish(err, sent, received)
+	})
+}
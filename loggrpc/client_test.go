@@ -0,0 +1,170 @@
+package loggrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	logger "github.com/rcommerz/logger-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestClientInterceptors(t *testing.T) {
+	log, takeEntries := newRecordingLogger()
+	opts := &Options{Logger: log}
+	conn := newTestConn(t, nil,
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(opts)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(opts)),
+	)
+	ctx := context.Background()
+
+	t.Run("should log successful unary calls", func(t *testing.T) {
+		takeEntries()
+		err := conn.Invoke(ctx, "/test.Echo/Ok", wrapperspb.String("hello"), &wrapperspb.StringValue{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		entries := takeEntries()
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(entries))
+		}
+		entry := entries[0]
+		if entry.Message != "/test.Echo/Ok OK" || entry.Level != logger.LevelINFO || entry.Type != logger.TypeRPC {
+			t.Errorf("Unexpected entry: %s %s %q", entry.Level, entry.Type, entry.Message)
+		}
+		assertFields(t, entry, logger.LogContext{
+			"grpc.target":    "passthrough:///bufnet",
+			"rpc.service":    "test.Echo",
+			"rpc.method":     "Ok",
+			"grpc.code":      "OK",
+			"grpc.type":      "unary",
+			"request_bytes":  7,
+			"response_bytes": 7,
+		})
+		if _, ok := entry.Fields["duration_ms"].(int64); !ok {
+			t.Errorf("Expected duration_ms, got %v", entry.Fields["duration_ms"])
+		}
+	})
+
+	t.Run("should log failed calls with their class", func(t *testing.T) {
+		takeEntries()
+		err := conn.Invoke(ctx, "/test.Echo/NotFound", &wrapperspb.StringValue{}, &wrapperspb.StringValue{})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
+		}
+
+		entry := takeEntries()[0]
+		if entry.Level != logger.LevelWARN {
+			t.Errorf("Expected WARN, got %s", entry.Level)
+		}
+		assertFields(t, entry, logger.LogContext{
+			"grpc.target": "passthrough:///bufnet",
+			"rpc.method":  "NotFound",
+			"grpc.code":   "NotFound",
+			"error.class": "client_error",
+		})
+	})
+
+	t.Run("should log client streams once they finish", func(t *testing.T) {
+		takeEntries()
+		stream, err := conn.NewStream(ctx, &testServiceDesc.Streams[0], "/test.Echo/Stream")
+		if err != nil {
+			t.Fatalf("Failed to open stream: %v", err)
+		}
+
+		if err := stream.SendMsg(wrapperspb.String("hello")); err != nil {
+			t.Fatalf("Failed to send: %v", err)
+		}
+		if err := stream.CloseSend(); err != nil {
+			t.Fatalf("Failed to close send: %v", err)
+		}
+		if err := stream.RecvMsg(&wrapperspb.StringValue{}); err != nil {
+			t.Fatalf("Expected echoed message, got %v", err)
+		}
+		if entries := takeEntries(); len(entries) != 0 {
+			t.Errorf("Expected no entry before the stream ends, got %d", len(entries))
+		}
+		if err := stream.RecvMsg(&wrapperspb.StringValue{}); !errors.Is(err, io.EOF) {
+			t.Errorf("Expected EOF, got %v", err)
+		}
+
+		entries := takeEntries()
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(entries))
+		}
+		assertFields(t, entries[0], logger.LogContext{
+			"grpc.target":       "passthrough:///bufnet",
+			"rpc.method":        "Stream",
+			"grpc.code":         "OK",
+			"grpc.type":         "bidi_stream",
+			"messages_sent":     1,
+			"messages_received": 1,
+		})
+		if _, ok := entries[0].Fields["duration_ms"].(int64); !ok {
+			t.Errorf("Expected duration_ms, got %v", entries[0].Fields["duration_ms"])
+		}
+	})
+
+	t.Run("should log streamer failures", func(t *testing.T) {
+		takeEntries()
+		failing := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return nil, status.Error(codes.Unavailable, "down")
+		}
+
+		streaming := StreamClientInterceptor(opts)
+		_, err := streaming(ctx, &grpc.StreamDesc{}, conn, "/test.Echo/Stream", failing)
+		if status.Code(err) != codes.Unavailable {
+			t.Errorf("Expected Unavailable, got %v", err)
+		}
+
+		entry := takeEntries()[0]
+		if entry.Level != logger.LevelERROR {
+			t.Errorf("Expected ERROR, got %s", entry.Level)
+		}
+		assertFields(t, entry, logger.LogContext{
+			"grpc.code":     "Unavailable",
+			"error.class":   "dependency_error",
+			"messages_sent": 0,
+		})
+	})
+}
+
+func TestClientContext(t *testing.T) {
+	fields := clientContext(context.Background(), "/svc.A/B", nil, status.Error(codes.Unavailable, "down"))
+
+	if fields["error.class"] != "dependency_error" {
+		t.Errorf("Expected error.class=dependency_error, got %v", fields["error.class"])
+	}
+	if fields["grpc.code"] != "Unavailable" {
+		t.Errorf("Expected grpc.code=Unavailable, got %v", fields["grpc.code"])
+	}
+
+	fields = clientContext(context.Background(), "/svc.A/B", nil, nil)
+	if _, ok := fields["error.class"]; ok {
+		t.Error("Expected no error.class for successful calls")
+	}
+}
+
+func TestClassForCode(t *testing.T) {
+	tests := map[codes.Code]logger.ErrorClass{
+		codes.DeadlineExceeded:  logger.ErrorClassTimeout,
+		codes.Canceled:          logger.ErrorClassCancelled,
+		codes.InvalidArgument:   logger.ErrorClassClient,
+		codes.NotFound:          logger.ErrorClassClient,
+		codes.Unauthenticated:   logger.ErrorClassClient,
+		codes.Unavailable:       logger.ErrorClassDependency,
+		codes.ResourceExhausted: logger.ErrorClassDependency,
+		codes.Internal:          logger.ErrorClassDependency,
+	}
+
+	for code, want := range tests {
+		if got := classForCode(code); got != want {
+			t.Errorf("classForCode(%s) = %s, want %s", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Package loggrpc provides gRPC interceptors that log calls through the
+// rcommerz logger with method, peer, status code, duration and sizes.
+package loggrpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	logger "github.com/rcommerz/logger-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Options configures the gRPC logging interceptors
+type Options struct {
+	// Logger, when set, logs calls instead of the singleton
+	Logger *logger.Logger
+	// ExcludeMethods lists full method names (e.g. /grpc.health.v1.Health/Check)
+	// that are not logged
+	ExcludeMethods []string
+}
+
+// logger returns the configured logger, or the singleton
+func (o *Options) logger() *logger.Logger {
+	if o.Logger != nil {
+		return o.Logger
+	}
+	return logger.GetInstance()
+}
+
+func (o *Options) excluded(fullMethod string) bool {
+	for _, method := range o.ExcludeMethods {
+		if method == fullMethod {
+			return true
+		}
+	}
+	return false
+}
+
+// splitMethod splits /package.Service/Method into service and method names
+func splitMethod(fullMethod string) (string, string) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "unknown", name
+}
+
+// levelForCode maps a gRPC status code to a log level: caller mistakes are
+// warnings, server-side failures are errors
+func levelForCode(code codes.Code) logger.LogLevel {
+	switch code {
+	case codes.OK:
+		return logger.LevelINFO
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.FailedPrecondition,
+		codes.OutOfRange, codes.ResourceExhausted:
+		return logger.LevelWARN
+	default:
+		return logger.LevelERROR
+	}
+}
+
+// classForCode maps the status code of a failed outbound call to its
+// error.class: rejected requests are client errors, deadlines and
+// cancellations keep their own classes and the rest are dependency errors
+func classForCode(code codes.Code) logger.ErrorClass {
+	switch code {
+	case codes.DeadlineExceeded:
+		return logger.ErrorClassTimeout
+	case codes.Canceled:
+		return logger.ErrorClassCancelled
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.FailedPrecondition,
+		codes.OutOfRange:
+		return logger.ErrorClassClient
+	default:
+		return logger.ErrorClassDependency
+	}
+}
+
+// messageSize returns the encoded size of a protobuf message
+func messageSize(msg interface{}) (int, bool) {
+	if m, ok := msg.(proto.Message); ok {
+		return proto.Size(m), true
+	}
+	return 0, false
+}
+
+// callContext builds the fields shared by all interceptors
+func callContext(ctx context.Context, fullMethod string, err error) logger.LogContext {
+	service, method := splitMethod(fullMethod)
+	code := status.Code(err)
+
+	context := logger.LogContext{
+		"rpc.system":  "grpc",
+		"rpc.service": service,
+		"rpc.method":  method,
+		"grpc.code":   code.String(),
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		context["peer"] = p.Addr.String()
+	}
+
+	if err != nil {
+		context["error"] = err
+	}
+
+	return context
+}
+
+// logCall emits the completed call at a level derived from its status code
+func logCall(ctx context.Context, log *logger.Logger, fullMethod string, context logger.LogContext, err error) {
+	code := status.Code(err)
+	message := fmt.Sprintf("%s %s", fullMethod, code.String())
+	log.Log(ctx, levelForCode(code), logger.TypeRPC, message, context)
+}
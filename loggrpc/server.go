@@ -0,0 +1,157 @@
+package loggrpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	logger "github.com/rcommerz/logger-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns a gRPC unary server interceptor that logs
+// every call and recovers from panics in the handler
+func UnaryServerInterceptor(opts *Options) grpc.UnaryServerInterceptor {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	log := opts.logger()
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		if opts.excluded(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		startTime := time.Now()
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoverPanic(ctx, log, info.FullMethod, r)
+			}
+
+			context := serverContext(ctx, info.FullMethod, err)
+			context["grpc.type"] = "unary"
+			context["duration_ms"] = time.Since(startTime).Milliseconds()
+			if size, ok := messageSize(req); ok {
+				context["request_bytes"] = size
+			}
+			if size, ok := messageSize(resp); ok && err == nil {
+				context["response_bytes"] = size
+			}
+
+			logCall(ctx, log, info.FullMethod, context, err)
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC stream server interceptor that logs
+// every stream once it completes and recovers from panics in the handler
+func StreamServerInterceptor(opts *Options) grpc.StreamServerInterceptor {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	log := opts.logger()
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		if opts.excluded(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		ctx := ss.Context()
+		startTime := time.Now()
+		stream := &countingServerStream{ServerStream: ss}
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoverPanic(ctx, log, info.FullMethod, r)
+			}
+
+			context := serverContext(ctx, info.FullMethod, err)
+			context["grpc.type"] = streamType(info.IsClientStream, info.IsServerStream)
+			context["duration_ms"] = time.Since(startTime).Milliseconds()
+			context["messages_received"] = stream.received
+			context["messages_sent"] = stream.sent
+			context["request_bytes"] = stream.receivedBytes
+			context["response_bytes"] = stream.sentBytes
+
+			logCall(ctx, log, info.FullMethod, context, err)
+		}()
+
+		return handler(srv, stream)
+	}
+}
+
+// serverContext builds the fields for a handled call, classifying any
+// failure from its status code. Failures the server does not attribute to
+// the caller are server errors.
+func serverContext(ctx context.Context, fullMethod string, err error) logger.LogContext {
+	context := callContext(ctx, fullMethod, err)
+	if err != nil {
+		class := classForCode(status.Code(err))
+		if class == logger.ErrorClassDependency {
+			class = logger.ErrorClassServer
+		}
+		context["error.class"] = string(class)
+	}
+	return context
+}
+
+// recoverPanic logs a recovered handler panic and converts it to an Internal status
+func recoverPanic(ctx context.Context, log *logger.Logger, fullMethod string, r interface{}) error {
+	service, method := splitMethod(fullMethod)
+	log.Error(ctx, "Panic recovered", logger.LogContext{
+		"rpc.system":  "grpc",
+		"rpc.service": service,
+		"rpc.method":  method,
+		"panic":       fmt.Sprint(r),
+	})
+	return status.Error(codes.Internal, "internal server error")
+}
+
+func streamType(clientStream, serverStream bool) string {
+	switch {
+	case clientStream && serverStream:
+		return "bidi_stream"
+	case clientStream:
+		return "client_stream"
+	default:
+		return "server_stream"
+	}
+}
+
+// countingServerStream tracks message counts and sizes flowing through a stream
+type countingServerStream struct {
+	grpc.ServerStream
+	received      int
+	sent          int
+	receivedBytes int
+	sentBytes     int
+}
+
+func (s *countingServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received++
+		if size, ok := messageSize(m); ok {
+			s.receivedBytes += size
+		}
+	}
+	return err
+}
+
+func (s *countingServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+		if size, ok := messageSize(m); ok {
+			s.sentBytes += size
+		}
+	}
+	return err
+}
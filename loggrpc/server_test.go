@@ -0,0 +1,287 @@
+package loggrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"testing"
+
+	logger "github.com/rcommerz/logger-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// testServiceDesc describes a hand-written service so tests don't need generated code
+var testServiceDesc = grpc.ServiceDesc{
+	ServiceName: "test.Echo",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Ok",
+			Handler:    unaryHandler("Ok", func() error { return nil }),
+		},
+		{
+			MethodName: "NotFound",
+			Handler:    unaryHandler("NotFound", func() error { return status.Error(codes.NotFound, "missing") }),
+		},
+		{
+			MethodName: "Panic",
+			Handler:    unaryHandler("Panic", func() error { panic("handler exploded") }),
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			ServerStreams: true,
+			ClientStreams: true,
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				msg := &wrapperspb.StringValue{}
+				if err := stream.RecvMsg(msg); err != nil {
+					return err
+				}
+				return stream.SendMsg(msg)
+			},
+		},
+	},
+}
+
+func unaryHandler(name string, fn func() error) grpc.MethodHandler {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := &wrapperspb.StringValue{}
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			if err := fn(); err != nil {
+				return nil, err
+			}
+			return req, nil
+		}
+		if interceptor == nil {
+			return handler(ctx, in)
+		}
+		return interceptor(ctx, in, &grpc.UnaryServerInfo{FullMethod: "/test.Echo/" + name}, handler)
+	}
+}
+
+func TestMain(m *testing.M) {
+	logger.Initialize(logger.Config{
+		ServiceName:    "loggrpc-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          logger.LevelDEBUG,
+	})
+	os.Exit(m.Run())
+}
+
+// newRecordingLogger returns a logger discarding its output and a function
+// returning and clearing the entries logged so far
+func newRecordingLogger() (*logger.Logger, func() []logger.Entry) {
+	var mu sync.Mutex
+	var entries []logger.Entry
+
+	log := logger.New(logger.Config{ServiceName: "loggrpc-test", Level: logger.LevelDEBUG, Output: io.Discard})
+	log.RegisterHook(func(entry logger.Entry) logger.Entry {
+		mu.Lock()
+		entries = append(entries, entry)
+		mu.Unlock()
+		return entry
+	})
+
+	return log, func() []logger.Entry {
+		mu.Lock()
+		defer mu.Unlock()
+		taken := entries
+		entries = nil
+		return taken
+	}
+}
+
+// assertFields fails t unless entry has every field in want
+func assertFields(t *testing.T, entry logger.Entry, want logger.LogContext) {
+	t.Helper()
+	for key, value := range want {
+		if entry.Fields[key] != value {
+			t.Errorf("Expected %s=%v (%T) on %q, got %v (%T)", key, value, value, entry.Message, entry.Fields[key], entry.Fields[key])
+		}
+	}
+}
+
+func newTestConn(t *testing.T, serverOpts []grpc.ServerOption, dialOpts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(serverOpts...)
+	server.RegisterService(&testServiceDesc, struct{}{})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	dialOpts = append(dialOpts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.NewClient("passthrough:///bufnet", dialOpts...)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestServerInterceptors(t *testing.T) {
+	log, takeEntries := newRecordingLogger()
+	opts := &Options{Logger: log}
+	conn := newTestConn(t, []grpc.ServerOption{
+		grpc.UnaryInterceptor(UnaryServerInterceptor(opts)),
+		grpc.StreamInterceptor(StreamServerInterceptor(opts)),
+	})
+	ctx := context.Background()
+
+	t.Run("should log successful calls", func(t *testing.T) {
+		takeEntries()
+		err := conn.Invoke(ctx, "/test.Echo/Ok", wrapperspb.String("hello"), &wrapperspb.StringValue{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		entries := takeEntries()
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(entries))
+		}
+		entry := entries[0]
+		if entry.Message != "/test.Echo/Ok OK" || entry.Level != logger.LevelINFO || entry.Type != logger.TypeRPC {
+			t.Errorf("Unexpected entry: %s %s %q", entry.Level, entry.Type, entry.Message)
+		}
+		assertFields(t, entry, logger.LogContext{
+			"rpc.system":     "grpc",
+			"rpc.service":    "test.Echo",
+			"rpc.method":     "Ok",
+			"grpc.code":      "OK",
+			"grpc.type":      "unary",
+			"peer":           "bufconn",
+			"request_bytes":  7,
+			"response_bytes": 7,
+		})
+		if _, ok := entry.Fields["duration_ms"].(int64); !ok {
+			t.Errorf("Expected duration_ms, got %v", entry.Fields["duration_ms"])
+		}
+		if _, ok := entry.Fields["error.class"]; ok {
+			t.Error("Expected no error.class for a successful call")
+		}
+	})
+
+	t.Run("should log handler status codes as client errors", func(t *testing.T) {
+		takeEntries()
+		err := conn.Invoke(ctx, "/test.Echo/NotFound", wrapperspb.String("hello"), &wrapperspb.StringValue{})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
+		}
+
+		entry := takeEntries()[0]
+		if entry.Level != logger.LevelWARN {
+			t.Errorf("Expected WARN, got %s", entry.Level)
+		}
+		assertFields(t, entry, logger.LogContext{
+			"rpc.method":    "NotFound",
+			"grpc.code":     "NotFound",
+			"error.class":   "client_error",
+			"request_bytes": 7,
+		})
+		if _, ok := entry.Fields["response_bytes"]; ok {
+			t.Error("Expected no response_bytes for a failed call")
+		}
+	})
+
+	t.Run("should recover panics as Internal server errors", func(t *testing.T) {
+		takeEntries()
+		err := conn.Invoke(ctx, "/test.Echo/Panic", &wrapperspb.StringValue{}, &wrapperspb.StringValue{})
+		if status.Code(err) != codes.Internal {
+			t.Errorf("Expected Internal after panic, got %v", err)
+		}
+
+		entries := takeEntries()
+		if len(entries) != 2 {
+			t.Fatalf("Expected panic and call entries, got %d", len(entries))
+		}
+		assertFields(t, entries[0], logger.LogContext{"rpc.method": "Panic", "panic": "handler exploded"})
+		if entries[1].Level != logger.LevelERROR {
+			t.Errorf("Expected ERROR, got %s", entries[1].Level)
+		}
+		assertFields(t, entries[1], logger.LogContext{"grpc.code": "Internal", "error.class": "server_error"})
+	})
+
+	t.Run("should log streams with message counts and sizes", func(t *testing.T) {
+		takeEntries()
+		stream, err := conn.NewStream(ctx, &testServiceDesc.Streams[0], "/test.Echo/Stream")
+		if err != nil {
+			t.Fatalf("Failed to open stream: %v", err)
+		}
+		if err := stream.SendMsg(wrapperspb.String("hello")); err != nil {
+			t.Fatalf("Failed to send: %v", err)
+		}
+		if err := stream.RecvMsg(&wrapperspb.StringValue{}); err != nil {
+			t.Errorf("Expected echoed message, got %v", err)
+		}
+		// The status arrives after the interceptor has logged the stream
+		if err := stream.RecvMsg(&wrapperspb.StringValue{}); !errors.Is(err, io.EOF) {
+			t.Fatalf("Expected EOF, got %v", err)
+		}
+
+		entry := takeEntries()[0]
+		assertFields(t, entry, logger.LogContext{
+			"rpc.method":        "Stream",
+			"grpc.code":         "OK",
+			"grpc.type":         "bidi_stream",
+			"peer":              "bufconn",
+			"messages_received": 1,
+			"messages_sent":     1,
+			"request_bytes":     7,
+			"response_bytes":    7,
+		})
+		if _, ok := entry.Fields["duration_ms"].(int64); !ok {
+			t.Errorf("Expected duration_ms, got %v", entry.Fields["duration_ms"])
+		}
+	})
+}
+
+func TestLevelForCode(t *testing.T) {
+	tests := []struct {
+		code     codes.Code
+		expected logger.LogLevel
+	}{
+		{codes.OK, logger.LevelINFO},
+		{codes.NotFound, logger.LevelWARN},
+		{codes.Unauthenticated, logger.LevelWARN},
+		{codes.Internal, logger.LevelERROR},
+		{codes.Unavailable, logger.LevelERROR},
+		{codes.DeadlineExceeded, logger.LevelERROR},
+	}
+
+	for _, tt := range tests {
+		if got := levelForCode(tt.code); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.code, tt.expected, got)
+		}
+	}
+}
+
+func TestSplitMethod(t *testing.T) {
+	service, method := splitMethod("/pkg.v1.Orders/Create")
+	if service != "pkg.v1.Orders" || method != "Create" {
+		t.Errorf("Expected pkg.v1.Orders/Create, got %s/%s", service, method)
+	}
+
+	service, method = splitMethod("bare")
+	if service != "unknown" || method != "bare" {
+		t.Errorf("Expected unknown/bare, got %s/%s", service, method)
+	}
+}
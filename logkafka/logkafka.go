@@ -0,0 +1,154 @@
+// Package logkafka logs segmentio/kafka-go produce and consume events
+// through the rcommerz logger with topic, partition, offset, key hash, lag
+// and processing duration, propagating trace context in message headers.
+//
+// Message keys are logged as messaging.kafka.key_hash with logger.Sensitive,
+// keyed by Config.SensitiveSalt. Without it the salt is random per process,
+// so the same key only hashes the same within one process; set it to
+// correlate keys across producers and consumers.
+package logkafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	logger "github.com/rcommerz/logger-go"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+)
+
+// MessageWriter is implemented by *kafka.Writer
+type MessageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// MessageHandler processes a consumed message
+type MessageHandler func(ctx context.Context, msg kafka.Message) error
+
+// Writer logs every message written through it and injects the caller's
+// trace context into message headers
+type Writer struct {
+	writer MessageWriter
+}
+
+// NewWriter wraps w, typically a *kafka.Writer
+func NewWriter(w MessageWriter) *Writer {
+	return &Writer{writer: w}
+}
+
+// WriteMessages writes msgs, logging one entry per message at DEBUG, or
+// ERROR for messages that failed. The trace headers are added to copies,
+// leaving the caller's messages unchanged.
+func (w *Writer) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	propagator := otel.GetTextMapPropagator()
+	msgs = append([]kafka.Message(nil), msgs...)
+	for i := range msgs {
+		msgs[i].Headers = append([]kafka.Header(nil), msgs[i].Headers...)
+		propagator.Inject(ctx, headerCarrier{&msgs[i].Headers})
+	}
+
+	startTime := time.Now()
+	err := w.writer.WriteMessages(ctx, msgs...)
+	duration := time.Since(startTime)
+
+	var writeErrors kafka.WriteErrors
+	errors.As(err, &writeErrors)
+
+	for i, msg := range msgs {
+		msgErr := err
+		if writeErrors != nil && i < len(writeErrors) {
+			msgErr = writeErrors[i]
+		}
+
+		if msg.Topic == "" {
+			if kw, ok := w.writer.(*kafka.Writer); ok {
+				msg.Topic = kw.Topic
+			}
+		}
+		context := messageContext("publish", msg)
+		context["duration_ms"] = duration.Milliseconds()
+		logMessage(ctx, "publish", msg.Topic, context, msgErr, logger.LevelDEBUG)
+	}
+	return err
+}
+
+// HandleMessage runs handler with the trace context extracted from msg's
+// headers and logs the processed message at INFO, or ERROR if handler
+// failed
+func HandleMessage(ctx context.Context, msg kafka.Message, handler MessageHandler) error {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier{&msg.Headers})
+
+	startTime := time.Now()
+	err := handler(ctx, msg)
+
+	context := messageContext("process", msg)
+	context["messaging.kafka.partition"] = msg.Partition
+	context["messaging.kafka.offset"] = msg.Offset
+	if msg.HighWaterMark > 0 {
+		context["messaging.kafka.lag"] = msg.HighWaterMark - msg.Offset - 1
+	}
+	context["duration_ms"] = time.Since(startTime).Milliseconds()
+
+	logMessage(ctx, "process", msg.Topic, context, err, logger.LevelINFO)
+	return err
+}
+
+// messageContext returns the fields describing a message. The key is
+// logged as a salted hash so messages can be correlated without exposing it.
+func messageContext(operation string, msg kafka.Message) logger.LogContext {
+	context := logger.LogContext{
+		"messaging.system":            "kafka",
+		"messaging.operation":         operation,
+		"messaging.destination.name":  msg.Topic,
+		"messaging.message.body.size": len(msg.Value),
+	}
+	if len(msg.Key) > 0 {
+		for key, value := range logger.Sensitive("messaging.kafka.key_hash", string(msg.Key)) {
+			context[key] = value
+		}
+	}
+	return context
+}
+
+// logMessage emits a message event at level, or ERROR when it failed
+func logMessage(ctx context.Context, operation, topic string, context logger.LogContext, err error, level logger.LogLevel) {
+	if err != nil {
+		context["error"] = err
+		level = logger.LevelERROR
+	}
+	logger.GetInstance().Log(ctx, level, logger.TypeMessaging, fmt.Sprintf("kafka %s %s", operation, topic), context)
+}
+
+// headerCarrier adapts message headers to an OpenTelemetry TextMapCarrier
+type headerCarrier struct {
+	headers *[]kafka.Header
+}
+
+func (c headerCarrier) Get(key string) string {
+	for _, header := range *c.headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func (c headerCarrier) Set(key, value string) {
+	for i, header := range *c.headers {
+		if header.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, len(*c.headers))
+	for i, header := range *c.headers {
+		keys[i] = header.Key
+	}
+	return keys
+}
@@ -0,0 +1,151 @@
+package logkafka
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	logger "github.com/rcommerz/logger-go"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	entriesMu sync.Mutex
+	entries   []logger.Entry
+)
+
+func TestMain(m *testing.M) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	log := logger.Initialize(logger.Config{
+		ServiceName:    "logkafka-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          logger.LevelDEBUG,
+	})
+	log.RegisterHook(func(entry logger.Entry) logger.Entry {
+		entriesMu.Lock()
+		entries = append(entries, entry)
+		entriesMu.Unlock()
+		return entry
+	})
+	os.Exit(m.Run())
+}
+
+// takeEntries returns and clears the entries logged so far
+func takeEntries() []logger.Entry {
+	entriesMu.Lock()
+	defer entriesMu.Unlock()
+	taken := entries
+	entries = nil
+	return taken
+}
+
+// fakeWriter records written messages and fails the second one
+type fakeWriter struct {
+	written []kafka.Message
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.written = append(w.written, msgs...)
+	if len(msgs) > 1 {
+		return kafka.WriteErrors{nil, errors.New("message too large")}
+	}
+	return nil
+}
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestWriter(t *testing.T) {
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	fake := &fakeWriter{}
+	headers := make([]kafka.Header, 1, 4)
+	headers[0] = kafka.Header{Key: "source", Value: []byte("checkout")}
+	msgs := []kafka.Message{
+		{Topic: "orders", Key: []byte("order-1"), Value: []byte("{}"), Headers: headers},
+		{Topic: "orders", Value: []byte("{}")},
+	}
+	err = NewWriter(fake).WriteMessages(ctx, msgs...)
+	if err == nil {
+		t.Fatal("Expected write error to pass through")
+	}
+	if len(msgs[0].Headers) != 1 || msgs[1].Headers != nil || headers[:2][1].Key != "" {
+		t.Errorf("Expected the caller's messages and headers unchanged, got %v", msgs)
+	}
+
+	carrier := headerCarrier{&fake.written[0].Headers}
+	if got := carrier.Get("traceparent"); got != traceparent {
+		t.Errorf("Expected traceparent header %q, got %q", traceparent, got)
+	}
+
+	logged := takeEntries()
+	if len(logged) != 2 {
+		t.Fatalf("Expected 1 entry per message, got %d", len(logged))
+	}
+	if logged[0].Level != logger.LevelDEBUG || logged[1].Level != logger.LevelERROR {
+		t.Errorf("Expected per-message levels DEBUG and ERROR, got %s and %s", logged[0].Level, logged[1].Level)
+	}
+	fields := logged[0].Fields
+	if fields["messaging.destination.name"] != "orders" || fields["messaging.operation"] != "publish" {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+	if hash := fields["messaging.kafka.key_hash"]; hash == nil || strings.Contains(logged[0].Message, "order-1") {
+		t.Errorf("Expected hashed key, got %v", hash)
+	}
+}
+
+func TestHandleMessage(t *testing.T) {
+	msg := kafka.Message{
+		Topic:         "orders",
+		Partition:     2,
+		Offset:        40,
+		HighWaterMark: 50,
+		Headers:       []kafka.Header{{Key: "traceparent", Value: []byte(traceparent)}},
+	}
+
+	var handlerTraceID string
+	err := HandleMessage(context.Background(), msg, func(ctx context.Context, msg kafka.Message) error {
+		handlerTraceID = trace.SpanContextFromContext(ctx).TraceID().String()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if handlerTraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected handler context to carry the message trace, got %q", handlerTraceID)
+	}
+
+	logged := takeEntries()
+	if len(logged) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(logged))
+	}
+	expected := map[string]interface{}{
+		"messaging.operation":       "process",
+		"messaging.kafka.partition": 2,
+		"messaging.kafka.offset":    int64(40),
+		"messaging.kafka.lag":       int64(9),
+	}
+	for key, value := range expected {
+		if logged[0].Fields[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, logged[0].Fields[key])
+		}
+	}
+	if logged[0].Type != logger.TypeMessaging || logged[0].Level != logger.LevelINFO {
+		t.Errorf("Expected INFO messaging entry, got %s %s", logged[0].Level, logged[0].Type)
+	}
+}
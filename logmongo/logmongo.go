@@ -0,0 +1,142 @@
+// Package logmongo provides a MongoDB driver command monitor that logs
+// commands through the rcommerz logger with their database, collection,
+// duration and failures, as log_type "db".
+package logmongo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	logger "github.com/rcommerz/logger-go"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// DefaultIgnoreCommands are handshake and health commands not logged by default
+var DefaultIgnoreCommands = []string{
+	"hello",
+	"isMaster",
+	"ismaster",
+	"ping",
+	"saslStart",
+	"saslContinue",
+	"buildInfo",
+	"endSessions",
+}
+
+// Options configures the command monitor
+type Options struct {
+	// SlowThreshold logs commands slower than this at WARN (0 = disabled)
+	SlowThreshold time.Duration
+	// IncludeCommand logs the command document as db.statement. Documents
+	// can hold personal data, so they are excluded by default.
+	IncludeCommand bool
+	// IgnoreCommands lists command names that are not logged (default
+	// DefaultIgnoreCommands)
+	IgnoreCommands []string
+}
+
+// monitor holds started commands until they finish, as finished events
+// don't carry the command document
+type monitor struct {
+	opts    Options
+	ignored map[string]bool
+	started sync.Map
+}
+
+// startedCommand is the part of a started event logged when it finishes
+type startedCommand struct {
+	collection string
+	command    string
+}
+
+// startedKey identifies a command across its started and finished events
+type startedKey struct {
+	connectionID string
+	requestID    int64
+}
+
+// NewMonitor returns a command monitor to set with
+// options.Client().SetMonitor
+func NewMonitor(opts *Options) *event.CommandMonitor {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	m := &monitor{opts: *opts, ignored: map[string]bool{}}
+	ignore := opts.IgnoreCommands
+	if ignore == nil {
+		ignore = DefaultIgnoreCommands
+	}
+	for _, name := range ignore {
+		m.ignored[name] = true
+	}
+
+	return &event.CommandMonitor{
+		Started:   m.commandStarted,
+		Succeeded: m.commandSucceeded,
+		Failed:    m.commandFailed,
+	}
+}
+
+func (m *monitor) commandStarted(_ context.Context, e *event.CommandStartedEvent) {
+	if m.ignored[e.CommandName] {
+		return
+	}
+
+	var started startedCommand
+	// The first element of a command is its name, with the collection as
+	// value for collection-level commands
+	if element, err := e.Command.IndexErr(0); err == nil {
+		started.collection, _ = element.Value().StringValueOK()
+	}
+	if m.opts.IncludeCommand {
+		started.command = e.Command.String()
+	}
+	m.started.Store(startedKey{e.ConnectionID, e.RequestID}, started)
+}
+
+func (m *monitor) commandSucceeded(ctx context.Context, e *event.CommandSucceededEvent) {
+	m.log(ctx, &e.CommandFinishedEvent, "")
+}
+
+func (m *monitor) commandFailed(ctx context.Context, e *event.CommandFailedEvent) {
+	m.log(ctx, &e.CommandFinishedEvent, e.Failure)
+}
+
+// log emits a finished command at DEBUG, WARN when slow, or ERROR when it
+// failed
+func (m *monitor) log(ctx context.Context, e *event.CommandFinishedEvent, failure string) {
+	if m.ignored[e.CommandName] {
+		return
+	}
+
+	context := logger.LogContext{
+		"db.system":    "mongodb",
+		"db.name":      e.DatabaseName,
+		"db.operation": e.CommandName,
+		"duration_ms":  e.Duration.Milliseconds(),
+	}
+	if value, ok := m.started.LoadAndDelete(startedKey{e.ConnectionID, e.RequestID}); ok {
+		started := value.(startedCommand)
+		if started.collection != "" {
+			context["db.mongodb.collection"] = started.collection
+		}
+		if started.command != "" {
+			context["db.statement"] = started.command
+		}
+	}
+
+	level := logger.LevelDEBUG
+	switch {
+	case failure != "":
+		context["error_message"] = failure
+		level = logger.LevelERROR
+	case m.opts.SlowThreshold > 0 && e.Duration >= m.opts.SlowThreshold:
+		context["slow"] = true
+		level = logger.LevelWARN
+	}
+
+	logger.GetInstance().Log(ctx, level, logger.TypeDB, fmt.Sprintf("mongo %s", e.CommandName), context)
+}
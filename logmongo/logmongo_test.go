@@ -0,0 +1,123 @@
+package logmongo
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logger "github.com/rcommerz/logger-go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+var (
+	entriesMu sync.Mutex
+	entries   []logger.Entry
+)
+
+func TestMain(m *testing.M) {
+	log := logger.Initialize(logger.Config{
+		ServiceName:    "logmongo-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          logger.LevelDEBUG,
+	})
+	log.RegisterHook(func(entry logger.Entry) logger.Entry {
+		entriesMu.Lock()
+		entries = append(entries, entry)
+		entriesMu.Unlock()
+		return entry
+	})
+	os.Exit(m.Run())
+}
+
+// takeEntries returns and clears the entries logged so far
+func takeEntries() []logger.Entry {
+	entriesMu.Lock()
+	defer entriesMu.Unlock()
+	taken := entries
+	entries = nil
+	return taken
+}
+
+// runCommand sends a started event followed by a succeeded event, or a
+// failed event when failure is set
+func runCommand(t *testing.T, monitor *event.CommandMonitor, command bson.D, failure string) {
+	t.Helper()
+	raw, err := bson.Marshal(command)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	name := command[0].Key
+	monitor.Started(ctx, &event.CommandStartedEvent{Command: raw, DatabaseName: "shop", CommandName: name, RequestID: 1, ConnectionID: "c1"})
+
+	finished := event.CommandFinishedEvent{CommandName: name, DatabaseName: "shop", RequestID: 1, ConnectionID: "c1", Duration: 3 * time.Millisecond}
+	if failure != "" {
+		monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: finished, Failure: failure})
+	} else {
+		monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished})
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	find := bson.D{{Key: "find", Value: "users"}, {Key: "filter", Value: bson.D{{Key: "email", Value: "a@example.com"}}}}
+
+	t.Run("should log commands without documents", func(t *testing.T) {
+		runCommand(t, NewMonitor(nil), find, "")
+
+		logged := takeEntries()
+		if len(logged) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(logged))
+		}
+		entry := logged[0]
+		expected := map[string]interface{}{
+			"db.system":             "mongodb",
+			"db.name":               "shop",
+			"db.operation":          "find",
+			"db.mongodb.collection": "users",
+			"duration_ms":           int64(3),
+		}
+		for key, value := range expected {
+			if entry.Fields[key] != value {
+				t.Errorf("Expected %s=%v, got %v", key, value, entry.Fields[key])
+			}
+		}
+		if entry.Type != logger.TypeDB || entry.Level != logger.LevelDEBUG {
+			t.Errorf("Expected DEBUG db entry, got %s %s", entry.Level, entry.Type)
+		}
+		if _, ok := entry.Fields["db.statement"]; ok {
+			t.Error("Expected command document excluded by default")
+		}
+	})
+
+	t.Run("should include documents when enabled", func(t *testing.T) {
+		runCommand(t, NewMonitor(&Options{IncludeCommand: true}), find, "")
+
+		logged := takeEntries()
+		if statement, _ := logged[0].Fields["db.statement"].(string); !strings.Contains(statement, "a@example.com") {
+			t.Errorf("Expected command document, got %v", logged[0].Fields["db.statement"])
+		}
+	})
+
+	t.Run("should log failures at ERROR", func(t *testing.T) {
+		runCommand(t, NewMonitor(nil), bson.D{{Key: "insert", Value: "orders"}}, "E11000 duplicate key")
+
+		logged := takeEntries()
+		if logged[0].Level != logger.LevelERROR || logged[0].Fields["error_message"] != "E11000 duplicate key" {
+			t.Errorf("Expected ERROR with failure, got %s %v", logged[0].Level, logged[0].Fields)
+		}
+	})
+
+	t.Run("should ignore handshake commands", func(t *testing.T) {
+		runCommand(t, NewMonitor(nil), bson.D{{Key: "hello", Value: 1}}, "")
+
+		if logged := takeEntries(); len(logged) != 0 {
+			t.Errorf("Expected hello to be ignored, got %v", logged)
+		}
+	})
+}
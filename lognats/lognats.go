@@ -0,0 +1,149 @@
+// Package lognats wraps NATS message handlers so every message is logged
+// through the rcommerz logger with subject, queue group, payload size,
+// processing time, ack outcome and panics, propagating trace context in
+// message headers.
+package lognats
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	logger "github.com/rcommerz/logger-go"
+	"go.opentelemetry.io/otel"
+)
+
+// Handler processes a message with the trace context of its publisher
+type Handler func(ctx context.Context, msg *nats.Msg) error
+
+// Publisher is implemented by *nats.Conn
+type Publisher interface {
+	PublishMsg(msg *nats.Msg) error
+}
+
+// Options configures the handler wrapper
+type Options struct {
+	// AutoAck acks JetStream messages when the handler succeeds and naks
+	// them when it fails or panics
+	AutoAck bool
+}
+
+// Wrap returns a nats.MsgHandler that runs handler, recovering and
+// logging panics, and logs each message at INFO, or ERROR if it failed
+func Wrap(handler Handler, opts *Options) nats.MsgHandler {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	log := logger.GetInstance()
+
+	return func(msg *nats.Msg) {
+		ctx := context.Background()
+		if msg.Header != nil {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier(msg.Header))
+		}
+
+		startTime := time.Now()
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic: %v", r)
+					log.Error(ctx, "Panic recovered", logger.LogContext{
+						"messaging.system":           "nats",
+						"messaging.destination.name": msg.Subject,
+						"panic":                      fmt.Sprint(r),
+					})
+				}
+			}()
+			err = handler(ctx, msg)
+		}()
+
+		context := logger.LogContext{
+			"messaging.system":            "nats",
+			"messaging.operation":         "process",
+			"messaging.destination.name":  msg.Subject,
+			"messaging.message.body.size": len(msg.Data),
+			"duration_ms":                 time.Since(startTime).Milliseconds(),
+		}
+		if msg.Sub != nil && msg.Sub.Queue != "" {
+			context["messaging.nats.queue_group"] = msg.Sub.Queue
+		}
+
+		if opts.AutoAck {
+			outcome, ackErr := "ack", error(nil)
+			if err != nil {
+				outcome, ackErr = "nak", msg.Nak()
+			} else {
+				ackErr = msg.Ack()
+			}
+			context["messaging.nats.ack"] = outcome
+			if ackErr != nil {
+				context["messaging.nats.ack_error"] = ackErr.Error()
+			}
+		}
+
+		level := logger.LevelINFO
+		if err != nil {
+			context["error"] = err
+			level = logger.LevelERROR
+		}
+		log.Log(ctx, level, logger.TypeMessaging, fmt.Sprintf("nats process %s", msg.Subject), context)
+	}
+}
+
+// PublishMsg injects the trace context of ctx into msg's headers, publishes
+// it and logs the publish at DEBUG, or ERROR if it failed
+func PublishMsg(ctx context.Context, publisher Publisher, msg *nats.Msg) error {
+	if msg.Header == nil {
+		msg.Header = nats.Header{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(msg.Header))
+
+	err := publisher.PublishMsg(msg)
+
+	context := logger.LogContext{
+		"messaging.system":            "nats",
+		"messaging.operation":         "publish",
+		"messaging.destination.name":  msg.Subject,
+		"messaging.message.body.size": len(msg.Data),
+	}
+	level := logger.LevelDEBUG
+	if err != nil {
+		context["error"] = err
+		level = logger.LevelERROR
+	}
+	logger.GetInstance().Log(ctx, level, logger.TypeMessaging, fmt.Sprintf("nats publish %s", msg.Subject), context)
+	return err
+}
+
+// headerCarrier adapts message headers to an OpenTelemetry TextMapCarrier.
+// NATS headers are case-sensitive, so Get falls back to a case-insensitive
+// match for publishers that use other casings.
+type headerCarrier nats.Header
+
+func (c headerCarrier) Get(key string) string {
+	if values := c[key]; len(values) > 0 {
+		return values[0]
+	}
+	for name, values := range c {
+		if strings.EqualFold(name, key) && len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c[key] = []string{value}
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
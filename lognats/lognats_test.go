@@ -0,0 +1,143 @@
+package lognats
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	logger "github.com/rcommerz/logger-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	entriesMu sync.Mutex
+	entries   []logger.Entry
+)
+
+func TestMain(m *testing.M) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	log := logger.Initialize(logger.Config{
+		ServiceName:    "lognats-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          logger.LevelDEBUG,
+	})
+	log.RegisterHook(func(entry logger.Entry) logger.Entry {
+		entriesMu.Lock()
+		entries = append(entries, entry)
+		entriesMu.Unlock()
+		return entry
+	})
+	os.Exit(m.Run())
+}
+
+// takeEntries returns and clears the entries logged so far
+func takeEntries() []logger.Entry {
+	entriesMu.Lock()
+	defer entriesMu.Unlock()
+	taken := entries
+	entries = nil
+	return taken
+}
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestWrap(t *testing.T) {
+	t.Run("should log processed messages with the publisher's trace", func(t *testing.T) {
+		var traceID string
+		handler := Wrap(func(ctx context.Context, msg *nats.Msg) error {
+			traceID = trace.SpanContextFromContext(ctx).TraceID().String()
+			return nil
+		}, nil)
+
+		handler(&nats.Msg{
+			Subject: "orders.created",
+			Data:    []byte("hello"),
+			Header:  nats.Header{"Traceparent": []string{traceparent}},
+			Sub:     &nats.Subscription{Queue: "workers"},
+		})
+
+		if traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("Expected handler context to carry the message trace, got %q", traceID)
+		}
+		logged := takeEntries()
+		if len(logged) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(logged))
+		}
+		expected := map[string]interface{}{
+			"messaging.system":            "nats",
+			"messaging.destination.name":  "orders.created",
+			"messaging.nats.queue_group":  "workers",
+			"messaging.message.body.size": 5,
+		}
+		for key, value := range expected {
+			if logged[0].Fields[key] != value {
+				t.Errorf("Expected %s=%v, got %v", key, value, logged[0].Fields[key])
+			}
+		}
+		if logged[0].Level != logger.LevelINFO || logged[0].Type != logger.TypeMessaging {
+			t.Errorf("Expected INFO messaging entry, got %s %s", logged[0].Level, logged[0].Type)
+		}
+	})
+
+	t.Run("should recover panics and nak", func(t *testing.T) {
+		handler := Wrap(func(ctx context.Context, msg *nats.Msg) error {
+			panic("handler exploded")
+		}, &Options{AutoAck: true})
+
+		handler(&nats.Msg{Subject: "orders.created"})
+
+		logged := takeEntries()
+		if len(logged) != 2 {
+			t.Fatalf("Expected panic and message entries, got %d", len(logged))
+		}
+		if logged[0].Fields["panic"] != "handler exploded" {
+			t.Errorf("Expected panic entry, got %v", logged[0].Fields)
+		}
+		fields := logged[1].Fields
+		if logged[1].Level != logger.LevelERROR || fields["messaging.nats.ack"] != "nak" {
+			t.Errorf("Expected failed message to be nak'd, got %s %v", logged[1].Level, fields)
+		}
+		if fields["messaging.nats.ack_error"] == nil {
+			t.Error("Expected nak of an unbound message to report an ack error")
+		}
+	})
+}
+
+type fakePublisher struct {
+	err error
+	msg *nats.Msg
+}
+
+func (p *fakePublisher) PublishMsg(msg *nats.Msg) error {
+	p.msg = msg
+	return p.err
+}
+
+func TestPublishMsg(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	publisher := &fakePublisher{err: errors.New("no responders")}
+	if err := PublishMsg(ctx, publisher, &nats.Msg{Subject: "orders.created"}); err == nil {
+		t.Error("Expected publish error to pass through")
+	}
+
+	if got := publisher.msg.Header.Get("traceparent"); got != traceparent {
+		t.Errorf("Expected traceparent header %q, got %q", traceparent, got)
+	}
+	logged := takeEntries()
+	if len(logged) != 1 || logged[0].Level != logger.LevelERROR {
+		t.Errorf("Expected failed publish at ERROR, got %v", logged)
+	}
+}
@@ -0,0 +1,222 @@
+// Package logpgx provides a pgx v5 tracer that logs queries, batches,
+// copies, connects and pool acquisitions through the rcommerz logger, as
+// log_type "db" correlated with the caller's trace context.
+package logpgx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	logger "github.com/rcommerz/logger-go"
+	"github.com/rcommerz/logger-go/internal/sqltext"
+)
+
+// Options configures the tracer
+type Options struct {
+	// SlowThreshold logs statements slower than this at WARN (0 = disabled)
+	SlowThreshold time.Duration
+	// SlowAcquireThreshold logs pool acquisitions waiting longer than this
+	// at WARN (0 = disabled)
+	SlowAcquireThreshold time.Duration
+	// KeepLiterals logs query text as-is. By default string and numeric
+	// literals are replaced with ? so inlined values are not logged.
+	KeepLiterals bool
+	// LogArgs logs statement arguments as db.args. Values still pass
+	// through the logger's Redactor.
+	LogArgs bool
+}
+
+// Tracer logs pgx activity. Set it as ConnConfig.Tracer; it also
+// implements pgxpool.AcquireTracer for pool wait times.
+type Tracer struct {
+	opts Options
+}
+
+var (
+	_ pgx.QueryTracer       = (*Tracer)(nil)
+	_ pgx.BatchTracer       = (*Tracer)(nil)
+	_ pgx.CopyFromTracer    = (*Tracer)(nil)
+	_ pgx.PrepareTracer     = (*Tracer)(nil)
+	_ pgx.ConnectTracer     = (*Tracer)(nil)
+	_ pgxpool.AcquireTracer = (*Tracer)(nil)
+)
+
+// NewTracer returns a tracer configured by opts
+func NewTracer(opts *Options) *Tracer {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &Tracer{opts: *opts}
+}
+
+// traceKey stores the start of a traced call in its context
+type traceKey struct{}
+
+// trace is the state of a call between its start and end hooks
+type trace struct {
+	start time.Time
+	sql   string
+	args  []any
+	// fields are logged with the end of the call
+	fields logger.LogContext
+}
+
+func startTrace(ctx context.Context, t *trace) context.Context {
+	t.start = time.Now()
+	return context.WithValue(ctx, traceKey{}, t)
+}
+
+func traceFrom(ctx context.Context) *trace {
+	if t, ok := ctx.Value(traceKey{}).(*trace); ok {
+		return t
+	}
+	return &trace{start: time.Now()}
+}
+
+func (t *Tracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return startTrace(ctx, &trace{sql: data.SQL, args: data.Args})
+}
+
+func (t *Tracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	tr := traceFrom(ctx)
+	context := t.statementContext(tr.sql, tr.args)
+	context["rows_affected"] = data.CommandTag.RowsAffected()
+	t.log(ctx, "query", tr.start, context, data.Err, t.opts.SlowThreshold)
+}
+
+func (t *Tracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	tr := &trace{fields: logger.LogContext{}}
+	if data.Batch != nil {
+		tr.fields["batch_size"] = data.Batch.Len()
+	}
+	return startTrace(ctx, tr)
+}
+
+func (t *Tracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	context := t.statementContext(data.SQL, data.Args)
+	context["rows_affected"] = data.CommandTag.RowsAffected()
+	t.log(ctx, "batch query", time.Time{}, context, data.Err, 0)
+}
+
+func (t *Tracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	tr := traceFrom(ctx)
+	tr.fields["db.operation"] = "BATCH"
+	t.log(ctx, "batch", tr.start, tr.fields, data.Err, t.opts.SlowThreshold)
+}
+
+func (t *Tracer) TraceCopyFromStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromStartData) context.Context {
+	return startTrace(ctx, &trace{fields: logger.LogContext{
+		"db.operation":   "COPY",
+		"db.sql.table":   data.TableName.Sanitize(),
+		"db.sql.columns": data.ColumnNames,
+	}})
+}
+
+func (t *Tracer) TraceCopyFromEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromEndData) {
+	tr := traceFrom(ctx)
+	tr.fields["rows_affected"] = data.CommandTag.RowsAffected()
+	t.log(ctx, "copy", tr.start, tr.fields, data.Err, t.opts.SlowThreshold)
+}
+
+func (t *Tracer) TracePrepareStart(ctx context.Context, _ *pgx.Conn, data pgx.TracePrepareStartData) context.Context {
+	tr := &trace{fields: t.statementContext(data.SQL, nil)}
+	tr.fields["db.operation"] = "PREPARE"
+	if data.Name != "" {
+		tr.fields["db.statement_name"] = data.Name
+	}
+	return startTrace(ctx, tr)
+}
+
+func (t *Tracer) TracePrepareEnd(ctx context.Context, _ *pgx.Conn, data pgx.TracePrepareEndData) {
+	if data.AlreadyPrepared {
+		return
+	}
+	tr := traceFrom(ctx)
+	t.log(ctx, "prepare", tr.start, tr.fields, data.Err, t.opts.SlowThreshold)
+}
+
+func (t *Tracer) TraceConnectStart(ctx context.Context, data pgx.TraceConnectStartData) context.Context {
+	tr := &trace{fields: logger.LogContext{"db.operation": "CONNECT"}}
+	if config := data.ConnConfig; config != nil {
+		tr.fields["server.address"] = config.Host
+		tr.fields["server.port"] = config.Port
+		tr.fields["db.name"] = config.Database
+		tr.fields["db.user"] = config.User
+	}
+	return startTrace(ctx, tr)
+}
+
+func (t *Tracer) TraceConnectEnd(ctx context.Context, data pgx.TraceConnectEndData) {
+	tr := traceFrom(ctx)
+	t.log(ctx, "connect", tr.start, tr.fields, data.Err, 0)
+}
+
+func (t *Tracer) TraceAcquireStart(ctx context.Context, _ *pgxpool.Pool, _ pgxpool.TraceAcquireStartData) context.Context {
+	return startTrace(ctx, &trace{})
+}
+
+// TraceAcquireEnd logs how long the caller waited for a pool connection,
+// with the pool's usage at that point
+func (t *Tracer) TraceAcquireEnd(ctx context.Context, pool *pgxpool.Pool, data pgxpool.TraceAcquireEndData) {
+	tr := traceFrom(ctx)
+	context := logger.LogContext{
+		"db.operation":         "ACQUIRE",
+		"pool.acquire_wait_ms": time.Since(tr.start).Milliseconds(),
+	}
+	if pool != nil {
+		stat := pool.Stat()
+		context["pool.acquired_conns"] = stat.AcquiredConns()
+		context["pool.idle_conns"] = stat.IdleConns()
+		context["pool.total_conns"] = stat.TotalConns()
+		context["pool.max_conns"] = stat.MaxConns()
+	}
+	t.log(ctx, "acquire", tr.start, context, data.Err, t.opts.SlowAcquireThreshold)
+}
+
+// statementContext returns the fields describing a SQL statement
+func (t *Tracer) statementContext(sql string, args []any) logger.LogContext {
+	statement := sql
+	if !t.opts.KeepLiterals {
+		statement = sqltext.RedactLiterals(sql)
+	}
+
+	context := logger.LogContext{
+		"db.statement": statement,
+		"db.operation": sqltext.Operation(sql),
+	}
+	if t.opts.LogArgs && len(args) > 0 {
+		context["db.args"] = args
+	}
+	return context
+}
+
+// log emits a completed call. A zero start omits the duration; slow is the
+// threshold above which a successful call is logged at WARN.
+func (t *Tracer) log(ctx context.Context, operation string, start time.Time, fields logger.LogContext, err error, slow time.Duration) {
+	fields["db.system"] = "postgresql"
+
+	var duration time.Duration
+	if !start.IsZero() {
+		duration = time.Since(start)
+		fields["duration_ms"] = duration.Milliseconds()
+	}
+
+	level := logger.LevelDEBUG
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		fields["error"] = err
+		level = logger.LevelWARN
+	case err != nil:
+		fields["error"] = err
+		level = logger.LevelERROR
+	case slow > 0 && duration >= slow:
+		fields["slow"] = true
+		level = logger.LevelWARN
+	}
+
+	logger.GetInstance().Log(ctx, level, logger.TypeDB, fmt.Sprintf("pgx %s", operation), fields)
+}
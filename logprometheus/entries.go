@@ -0,0 +1,53 @@
+package logprometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	logger "github.com/rcommerz/logger-go"
+)
+
+// EntryMetrics counts log entries in Prometheus. Pass it as
+// logger.Config.Metrics to export logger_entries_total{level,log_type},
+// logger_dropped_total{reason} and logger_sink_errors_total.
+type EntryMetrics struct {
+	entries    *prometheus.CounterVec
+	dropped    *prometheus.CounterVec
+	sinkErrors prometheus.Counter
+}
+
+// NewEntryMetrics creates and registers the entry counters with registerer
+// (default prometheus.DefaultRegisterer)
+func NewEntryMetrics(registerer prometheus.Registerer) *EntryMetrics {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	return &EntryMetrics{
+		entries: register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "logger_entries_total",
+			Help: "Total number of log entries written.",
+		}, []string{"level", "log_type"})),
+		dropped: register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "logger_dropped_total",
+			Help: "Total number of log entries dropped.",
+		}, []string{"reason"})),
+		sinkErrors: register(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "logger_sink_errors_total",
+			Help: "Total number of errors writing log entries to the output.",
+		})),
+	}
+}
+
+// EntryLogged implements logger.EntryMetrics
+func (m *EntryMetrics) EntryLogged(level logger.LogLevel, logType logger.LogType) {
+	m.entries.WithLabelValues(string(level), string(logType)).Inc()
+}
+
+// EntryDropped implements logger.EntryMetrics
+func (m *EntryMetrics) EntryDropped(reason string) {
+	m.dropped.WithLabelValues(reason).Inc()
+}
+
+// SinkError implements logger.EntryMetrics
+func (m *EntryMetrics) SinkError() {
+	m.sinkErrors.Inc()
+}
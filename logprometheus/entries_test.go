@@ -0,0 +1,42 @@
+package logprometheus
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	logger "github.com/rcommerz/logger-go"
+)
+
+func TestEntryMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	l := logger.Initialize(logger.Config{
+		ServiceName:    "entry-metrics-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          logger.LevelINFO,
+		Metrics:        NewEntryMetrics(registry),
+	})
+
+	ctx := context.Background()
+	l.Info(ctx, "started", nil)
+	l.Error(ctx, "failed", nil)
+	l.Security(ctx, "blocked", nil)
+	l.Debug(ctx, "filtered", nil)
+
+	expected := `
+		# HELP logger_entries_total Total number of log entries written.
+		# TYPE logger_entries_total counter
+		logger_entries_total{level="ERROR",log_type="error"} 1
+		logger_entries_total{level="INFO",log_type="normal"} 1
+		logger_entries_total{level="WARN",log_type="security"} 1
+	`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "logger_entries_total"); err != nil {
+		t.Error(err)
+	}
+	if value := testutil.ToFloat64(NewEntryMetrics(registry).sinkErrors); value != 0 {
+		t.Errorf("Expected no sink errors, got %v", value)
+	}
+}
@@ -0,0 +1,121 @@
+// Package logprometheus exports Prometheus metrics alongside the logger,
+// using the same route normalization as the logging middleware.
+package logprometheus
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	logger "github.com/rcommerz/logger-go"
+)
+
+// UnmatchedRoute is the route label for requests no handler route matched,
+// keeping label cardinality bounded
+const UnmatchedRoute = "unmatched"
+
+// Options configures the metrics middleware
+type Options struct {
+	// Namespace prefixes metric names (e.g. "shop" → shop_http_requests_total)
+	Namespace string
+	// Registerer registers the collectors (default prometheus.DefaultRegisterer)
+	Registerer prometheus.Registerer
+	// Buckets are the duration histogram buckets in seconds
+	// (default prometheus.DefBuckets)
+	Buckets []float64
+	// Skip excludes requests from the metrics when it returns true
+	Skip func(*fiber.Ctx) bool
+}
+
+// MetricsMiddleware returns a Fiber middleware that records
+// http_requests_total, http_request_duration_seconds (by method, route and
+// status) and http_requests_in_flight (by method)
+func MetricsMiddleware(opts *Options) fiber.Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+	registerer := opts.Registerer
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	buckets := opts.Buckets
+	if buckets == nil {
+		buckets = prometheus.DefBuckets
+	}
+
+	labels := []string{"method", "route", "status"}
+	requests := register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: opts.Namespace,
+		Name:      "http_requests_total",
+		Help:      "Total number of HTTP requests.",
+	}, labels))
+	duration := register(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: opts.Namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request duration in seconds.",
+		Buckets:   buckets,
+	}, labels))
+	inFlight := register(registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: opts.Namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "Number of HTTP requests currently being served.",
+	}, []string{"method"}))
+
+	return func(c *fiber.Ctx) error {
+		if opts.Skip != nil && opts.Skip(c) {
+			return c.Next()
+		}
+
+		startTime := time.Now()
+		middlewareRoute := c.Route()
+		method := c.Method()
+
+		gauge := inFlight.WithLabelValues(method)
+		gauge.Inc()
+		defer gauge.Dec()
+
+		err := c.Next()
+
+		route := logger.MatchedRoute(c, middlewareRoute)
+		if route == "" {
+			route = UnmatchedRoute
+		}
+		status := strconv.Itoa(statusCode(c, err))
+
+		requests.WithLabelValues(method, route, status).Inc()
+		duration.WithLabelValues(method, route, status).Observe(time.Since(startTime).Seconds())
+
+		return err
+	}
+}
+
+// statusCode returns the status that will be sent for the request,
+// accounting for errors not yet handled by the Fiber error handler
+func statusCode(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// register registers a collector, reusing an identical one that is already
+// registered so middleware can be created more than once
+func register[T prometheus.Collector](registerer prometheus.Registerer, collector T) T {
+	if err := registerer.Register(collector); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if errors.As(err, &registered) {
+			if existing, ok := registered.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return collector
+}
@@ -0,0 +1,83 @@
+package logprometheus
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	var inFlight float64
+	app := fiber.New()
+	app.Use(MetricsMiddleware(&Options{
+		Registerer: registry,
+		Skip: func(c *fiber.Ctx) bool {
+			return c.Path() == "/metrics"
+		},
+	}))
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		inFlight = gaugeValue(t, registry)
+		return c.SendStatus(200)
+	})
+	app.Get("/broken", func(c *fiber.Ctx) error {
+		return fiber.ErrBadGateway
+	})
+
+	for _, path := range []string{"/users/1", "/users/2", "/broken", "/missing", "/metrics"} {
+		_, _ = app.Test(httptest.NewRequest("GET", path, nil))
+	}
+
+	expected := `
+		# HELP http_requests_total Total number of HTTP requests.
+		# TYPE http_requests_total counter
+		http_requests_total{method="GET",route="/broken",status="502"} 1
+		http_requests_total{method="GET",route="/users/:id",status="200"} 2
+		http_requests_total{method="GET",route="unmatched",status="404"} 1
+	`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "http_requests_total"); err != nil {
+		t.Error(err)
+	}
+
+	if count := testutil.CollectAndCount(registry, "http_request_duration_seconds"); count != 3 {
+		t.Errorf("Expected 3 duration series, got %d", count)
+	}
+	if inFlight != 1 {
+		t.Errorf("Expected 1 in-flight request during handler, got %v", inFlight)
+	}
+	if after := gaugeValue(t, registry); after != 0 {
+		t.Errorf("Expected 0 in-flight requests after completion, got %v", after)
+	}
+}
+
+func TestMetricsMiddlewareReregister(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Expected second middleware to reuse collectors, got panic: %v", r)
+		}
+	}()
+	MetricsMiddleware(&Options{Registerer: registry})
+	MetricsMiddleware(&Options{Registerer: registry})
+}
+
+func gaugeValue(t *testing.T, registry *prometheus.Registry) float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() == "http_requests_in_flight" {
+			return family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	return 0
+}
@@ -0,0 +1,160 @@
+// Package logredis provides a go-redis v9 hook that logs commands and
+// pipelines through the rcommerz logger with latency, pipeline sizes and
+// errors, as log_type "db".
+package logredis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	logger "github.com/rcommerz/logger-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// ArgsMode controls how command arguments are logged
+type ArgsMode int
+
+const (
+	// ArgsNone logs only command names (default)
+	ArgsNone ArgsMode = iota
+	// ArgsRedacted logs the command and its first argument (usually the
+	// key), replacing the remaining arguments with ?
+	ArgsRedacted
+	// ArgsFull logs all arguments. Values still pass through the logger's
+	// Redactor.
+	ArgsFull
+)
+
+// Options configures the hook
+type Options struct {
+	// SlowThreshold logs commands slower than this at WARN (0 = disabled)
+	SlowThreshold time.Duration
+	// Args controls how command arguments are logged as db.redis.args
+	Args ArgsMode
+}
+
+// Hook logs redis commands. Register it with client.AddHook.
+type Hook struct {
+	opts Options
+}
+
+var _ redis.Hook = (*Hook)(nil)
+
+// NewHook returns a hook configured by opts
+func NewHook(opts *Options) *Hook {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &Hook{opts: *opts}
+}
+
+// DialHook logs new connections to the server
+func (h *Hook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		startTime := time.Now()
+		conn, err := next(ctx, network, addr)
+
+		h.log(ctx, "dial", startTime, logger.LogContext{
+			"db.operation":   "dial",
+			"server.address": addr,
+		}, err)
+		return conn, err
+	}
+}
+
+// ProcessHook logs single commands. redis.Nil is logged as a cache miss
+// rather than an error.
+func (h *Hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		startTime := time.Now()
+		err := next(ctx, cmd)
+
+		context := logger.LogContext{"db.operation": cmd.FullName()}
+		if args := h.args(cmd); args != nil {
+			context["db.redis.args"] = args
+		}
+		h.log(ctx, cmd.FullName(), startTime, context, err)
+		return err
+	}
+}
+
+// ProcessPipelineHook logs pipelines and transactions as one entry with
+// their size and command names
+func (h *Hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		startTime := time.Now()
+		err := next(ctx, cmds)
+
+		names := make([]string, len(cmds))
+		var args [][]interface{}
+		for i, cmd := range cmds {
+			names[i] = cmd.FullName()
+			if a := h.args(cmd); a != nil {
+				args = append(args, a)
+			}
+			if err == nil && cmd.Err() != nil && !errors.Is(cmd.Err(), redis.Nil) {
+				err = cmd.Err()
+			}
+		}
+
+		context := logger.LogContext{
+			"db.operation":      "pipeline",
+			"pipeline_size":     len(cmds),
+			"db.redis.commands": names,
+		}
+		if args != nil {
+			context["db.redis.args"] = args
+		}
+		h.log(ctx, "pipeline", startTime, context, err)
+		return err
+	}
+}
+
+// args returns the command arguments to log, or nil
+func (h *Hook) args(cmd redis.Cmder) []interface{} {
+	all := cmd.Args()
+	switch h.opts.Args {
+	case ArgsFull:
+		return all
+	case ArgsRedacted:
+		redacted := make([]interface{}, len(all))
+		for i, arg := range all {
+			if i < 2 {
+				redacted[i] = arg
+			} else {
+				redacted[i] = "?"
+			}
+		}
+		return redacted
+	default:
+		return nil
+	}
+}
+
+// log emits a completed call at DEBUG, WARN when slow or canceled, or
+// ERROR when it failed
+func (h *Hook) log(ctx context.Context, operation string, startTime time.Time, fields logger.LogContext, err error) {
+	duration := time.Since(startTime)
+	fields["db.system"] = "redis"
+	fields["duration_ms"] = duration.Milliseconds()
+
+	level := logger.LevelDEBUG
+	switch {
+	case errors.Is(err, redis.Nil):
+		fields["redis.miss"] = true
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		fields["error"] = err
+		level = logger.LevelWARN
+	case err != nil:
+		fields["error"] = err
+		level = logger.LevelERROR
+	case h.opts.SlowThreshold > 0 && duration >= h.opts.SlowThreshold:
+		fields["slow"] = true
+		level = logger.LevelWARN
+	}
+
+	logger.GetInstance().Log(ctx, level, logger.TypeDB, fmt.Sprintf("redis %s", operation), fields)
+}
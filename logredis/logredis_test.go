@@ -0,0 +1,127 @@
+package logredis
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	logger "github.com/rcommerz/logger-go"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	entriesMu sync.Mutex
+	entries   []logger.Entry
+)
+
+func TestMain(m *testing.M) {
+	log := logger.Initialize(logger.Config{
+		ServiceName:    "logredis-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          logger.LevelDEBUG,
+	})
+	log.RegisterHook(func(entry logger.Entry) logger.Entry {
+		entriesMu.Lock()
+		entries = append(entries, entry)
+		entriesMu.Unlock()
+		return entry
+	})
+	os.Exit(m.Run())
+}
+
+// takeEntry returns the only entry logged since the last call
+func takeEntry(t *testing.T) logger.Entry {
+	t.Helper()
+	entriesMu.Lock()
+	defer entriesMu.Unlock()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	entries = nil
+	return entry
+}
+
+func TestProcessHook(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		opts   *Options
+		err    error
+		level  logger.LogLevel
+		args   []interface{}
+		miss   bool
+		failed bool
+	}{
+		{name: "names only by default", level: logger.LevelDEBUG},
+		{name: "redacted args", opts: &Options{Args: ArgsRedacted}, level: logger.LevelDEBUG, args: []interface{}{"set", "session:1", "?"}},
+		{name: "full args", opts: &Options{Args: ArgsFull}, level: logger.LevelDEBUG, args: []interface{}{"set", "session:1", "token"}},
+		{name: "cache miss", err: redis.Nil, level: logger.LevelDEBUG, miss: true},
+		{name: "error", err: errors.New("READONLY"), level: logger.LevelERROR, failed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := NewHook(tt.opts).ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+				return tt.err
+			})
+			cmd := redis.NewStatusCmd(ctx, "set", "session:1", "token")
+			if err := hook(ctx, cmd); !errors.Is(err, tt.err) {
+				t.Fatalf("Expected error %v to pass through, got %v", tt.err, err)
+			}
+
+			entry := takeEntry(t)
+			if entry.Type != logger.TypeDB || entry.Level != tt.level || entry.Message != "redis set" {
+				t.Errorf("Unexpected entry %s %s %q", entry.Level, entry.Type, entry.Message)
+			}
+			if entry.Fields["db.system"] != "redis" || entry.Fields["db.operation"] != "set" {
+				t.Errorf("Unexpected fields %v", entry.Fields)
+			}
+
+			args, _ := entry.Fields["db.redis.args"].([]interface{})
+			if len(args) != len(tt.args) {
+				t.Fatalf("Expected args %v, got %v", tt.args, args)
+			}
+			for i := range args {
+				if args[i] != tt.args[i] {
+					t.Errorf("Expected args %v, got %v", tt.args, args)
+				}
+			}
+
+			if miss, _ := entry.Fields["redis.miss"].(bool); miss != tt.miss {
+				t.Errorf("Expected redis.miss=%v, got %v", tt.miss, entry.Fields["redis.miss"])
+			}
+			if _, failed := entry.Fields["error_message"]; failed != tt.failed {
+				t.Errorf("Expected error logged=%v, got %v", tt.failed, entry.Fields)
+			}
+		})
+	}
+}
+
+func TestProcessPipelineHook(t *testing.T) {
+	ctx := context.Background()
+	hook := NewHook(nil).ProcessPipelineHook(func(ctx context.Context, cmds []redis.Cmder) error {
+		cmds[1].SetErr(redis.Nil)
+		return nil
+	})
+
+	cmds := []redis.Cmder{
+		redis.NewStatusCmd(ctx, "set", "a", "1"),
+		redis.NewStringCmd(ctx, "get", "b"),
+	}
+	if err := hook(ctx, cmds); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := takeEntry(t)
+	if entry.Level != logger.LevelDEBUG || entry.Fields["pipeline_size"] != 2 {
+		t.Errorf("Expected successful pipeline of 2, got %s %v", entry.Level, entry.Fields)
+	}
+	if names, _ := entry.Fields["db.redis.commands"].([]string); len(names) != 2 || names[0] != "set" || names[1] != "get" {
+		t.Errorf("Expected command names, got %v", entry.Fields["db.redis.commands"])
+	}
+}
@@ -0,0 +1,145 @@
+// Package logresty logs outbound requests made with a resty client through
+// the rcommerz logger, one entry per attempt including retries:
+//
+//	client := logresty.Register(resty.New(), nil)
+package logresty
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	logger "github.com/rcommerz/logger-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Options configures the resty middleware
+type Options struct {
+	// SlowThreshold logs successful responses slower than this at WARN
+	// with slow_request (0 = disabled)
+	SlowThreshold time.Duration
+}
+
+// Register adds middleware to client that propagates the trace context to
+// the outbound request and logs every response with the fields of the HTTP
+// middlewares (method, host, path, status code, duration, sizes) and its
+// attempt number. Requests that fail
+// without a response are logged once all retries are exhausted. It returns
+// client for chaining.
+func Register(client *resty.Client, opts *Options) *resty.Client {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	log := logger.GetInstance()
+
+	client.OnBeforeRequest(func(_ *resty.Client, request *resty.Request) error {
+		otel.GetTextMapPropagator().Inject(request.Context(), propagation.HeaderCarrier(request.Header))
+		return nil
+	})
+
+	client.OnAfterResponse(func(_ *resty.Client, response *resty.Response) error {
+		request := response.Request
+		statusCode := response.StatusCode()
+		context := logger.HTTPFields(requestInfo(request), logger.HTTPResponseInfo{
+			StatusCode: statusCode,
+			Duration:   response.Time(),
+			Bytes:      response.Size(),
+		})
+		context["http.attempt"] = request.Attempt
+
+		level := logger.LevelINFO
+		switch {
+		case statusCode >= 500:
+			level = logger.LevelERROR
+			context["error.class"] = string(logger.ErrorClassDependency)
+			if statusCode == http.StatusGatewayTimeout {
+				context["error.class"] = string(logger.ErrorClassTimeout)
+			}
+		case statusCode >= 400:
+			level = logger.LevelWARN
+		case opts.SlowThreshold > 0 && response.Time() > opts.SlowThreshold:
+			level = logger.LevelWARN
+			context["slow_request"] = true
+		}
+
+		message := fmt.Sprintf("%s %s %d", request.Method, requestURL(request), statusCode)
+		log.Log(request.Context(), level, logger.TypeHTTP, message, context)
+		return nil
+	})
+
+	client.OnError(func(request *resty.Request, err error) {
+		// Errors with a response were logged by OnAfterResponse
+		var responseErr *resty.ResponseError
+		if errors.As(err, &responseErr) {
+			if responseErr.Response.RawResponse != nil {
+				return
+			}
+			err = responseErr.Err
+		}
+
+		var duration time.Duration
+		if !request.Time.IsZero() {
+			duration = time.Since(request.Time)
+		}
+		context := logger.HTTPFields(requestInfo(request), logger.HTTPResponseInfo{Duration: duration, Bytes: -1})
+		// No response was received
+		delete(context, "status_code")
+		if request.Time.IsZero() {
+			delete(context, "duration_ms")
+		}
+		context["http.attempt"] = request.Attempt
+		context["error"] = err
+		class := logger.ClassifyError(err)
+		if class == "" {
+			class = logger.ErrorClassDependency
+		}
+		context["error.class"] = string(class)
+
+		message := fmt.Sprintf("%s %s failed", request.Method, requestURL(request))
+		log.Log(request.Context(), logger.LevelERROR, logger.TypeHTTP, message, context)
+	})
+
+	return client
+}
+
+// requestInfo describes an outbound request with the field names of the
+// HTTP middlewares. The query is left out, since it may carry credentials.
+func requestInfo(request *resty.Request) logger.HTTPRequestInfo {
+	info := logger.HTTPRequestInfo{Method: request.Method, Bytes: -1}
+	if target := parseURL(request); target != nil {
+		info.Host = target.Host
+		info.Path = target.Path
+	}
+	if request.RawRequest != nil {
+		info.UserAgent = request.RawRequest.UserAgent()
+		info.Bytes = request.RawRequest.ContentLength
+	}
+	return info
+}
+
+// requestURL returns the request's host and path without the query, which
+// may carry credentials
+func requestURL(request *resty.Request) string {
+	target := parseURL(request)
+	if target == nil {
+		return request.URL
+	}
+	return target.Host + target.Path
+}
+
+// parseURL returns the URL the request was sent to
+func parseURL(request *resty.Request) *url.URL {
+	if request.RawRequest != nil {
+		return request.RawRequest.URL
+	}
+	target, err := url.Parse(request.URL)
+	if err != nil {
+		return nil
+	}
+	return target
+}
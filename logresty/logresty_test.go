@@ -0,0 +1,176 @@
+package logresty
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	logger "github.com/rcommerz/logger-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	entriesMu sync.Mutex
+	entries   []logger.Entry
+)
+
+func TestMain(m *testing.M) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	log := logger.Initialize(logger.Config{
+		ServiceName:    "logresty-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          logger.LevelDEBUG,
+	})
+	log.RegisterHook(func(entry logger.Entry) logger.Entry {
+		entriesMu.Lock()
+		entries = append(entries, entry)
+		entriesMu.Unlock()
+		return entry
+	})
+	os.Exit(m.Run())
+}
+
+// takeEntries returns and clears the entries logged so far
+func takeEntries() []logger.Entry {
+	entriesMu.Lock()
+	defer entriesMu.Unlock()
+	taken := entries
+	entries = nil
+	return taken
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("should log responses and propagate the trace", func(t *testing.T) {
+		var traceparent string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparent = r.Header.Get("traceparent")
+			_, _ = w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+
+		spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{0x4b, 0xf9},
+			SpanID:     trace.SpanID{0x01},
+			TraceFlags: trace.FlagsSampled,
+		})
+		ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+
+		_, err := Register(resty.New(), nil).R().SetContext(ctx).Get(server.URL + "/users?token=secret")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if traceparent == "" {
+			t.Error("Expected traceparent header on the outbound request")
+		}
+		logged := takeEntries()
+		if len(logged) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(logged))
+		}
+		expected := map[string]interface{}{
+			"method":         "GET",
+			"host":           strings.TrimPrefix(server.URL, "http://"),
+			"path":           "/users",
+			"status_code":    200,
+			"http.attempt":   1,
+			"request_bytes":  int64(0),
+			"response_bytes": int64(2),
+		}
+		for key, value := range expected {
+			if logged[0].Fields[key] != value {
+				t.Errorf("Expected %s=%v, got %v", key, value, logged[0].Fields[key])
+			}
+		}
+		if logged[0].Type != logger.TypeHTTP || logged[0].Level != logger.LevelINFO {
+			t.Errorf("Expected INFO http entry, got %s %s", logged[0].Level, logged[0].Type)
+		}
+		if _, ok := logged[0].Fields["duration_ms"]; !ok {
+			t.Error("Expected duration_ms")
+		}
+		if ua, _ := logged[0].Fields["user_agent"].(string); !strings.HasPrefix(ua, "go-resty") {
+			t.Errorf("Expected the resty user agent, got %v", logged[0].Fields["user_agent"])
+		}
+		if _, ok := logged[0].Fields["query"]; ok {
+			t.Error("Expected no query, which may carry credentials")
+		}
+	})
+
+	t.Run("should log every retry attempt", func(t *testing.T) {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+
+		client := Register(resty.New(), nil).
+			SetRetryCount(1).
+			SetRetryWaitTime(0).
+			AddRetryCondition(func(response *resty.Response, err error) bool {
+				return response.StatusCode() >= 500
+			})
+		if _, err := client.R().Get(server.URL); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		logged := takeEntries()
+		if len(logged) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(logged))
+		}
+		if logged[0].Level != logger.LevelERROR || logged[0].Fields["http.attempt"] != 1 {
+			t.Errorf("Expected failed first attempt, got %s %v", logged[0].Level, logged[0].Fields)
+		}
+		if logged[0].Fields["error.class"] != "dependency_error" {
+			t.Errorf("Expected error.class=dependency_error, got %v", logged[0].Fields["error.class"])
+		}
+		if logged[1].Level != logger.LevelINFO || logged[1].Fields["http.attempt"] != 2 {
+			t.Errorf("Expected successful second attempt, got %s %v", logged[1].Level, logged[1].Fields)
+		}
+	})
+
+	t.Run("should log client errors at WARN", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		if _, err := Register(resty.New(), nil).R().Get(server.URL); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		logged := takeEntries()
+		if len(logged) != 1 || logged[0].Level != logger.LevelWARN {
+			t.Fatalf("Expected 1 WARN entry, got %v", logged)
+		}
+	})
+
+	t.Run("should log requests that fail without a response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Close()
+
+		if _, err := Register(resty.New(), nil).R().Get(server.URL + "/users"); err == nil {
+			t.Fatal("Expected connection error")
+		}
+
+		logged := takeEntries()
+		if len(logged) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(logged))
+		}
+		if logged[0].Level != logger.LevelERROR || logged[0].Fields["error.class"] != "dependency_error" {
+			t.Errorf("Expected dependency ERROR entry, got %s %v", logged[0].Level, logged[0].Fields)
+		}
+		if logged[0].Fields["error_message"] == nil {
+			t.Error("Expected error_message")
+		}
+	})
+}
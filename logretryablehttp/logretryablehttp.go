@@ -0,0 +1,125 @@
+// Package logretryablehttp adapts the rcommerz logger to the
+// hashicorp/go-retryablehttp LeveledLogger, so request, failure and retry
+// messages are written as structured entries:
+//
+//	client := retryablehttp.NewClient()
+//	client.Logger = logretryablehttp.NewLogger(client)
+package logretryablehttp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	logger "github.com/rcommerz/logger-go"
+)
+
+// retryRequestPattern parses the "request" value of retry messages,
+// formatted by the client as "METHOD URL" with an optional " (status: N)"
+var retryRequestPattern = regexp.MustCompile(`^(\S+) (\S+)(?: \(status: (\d+)\))?$`)
+
+// Logger implements retryablehttp.LeveledLogger
+type Logger struct {
+	client *retryablehttp.Client
+}
+
+var _ retryablehttp.LeveledLogger = (*Logger)(nil)
+
+// NewLogger returns a LeveledLogger writing through the global logger.
+// client, when set, is used to number retry attempts from its RetryMax.
+//
+// Messages keep their level, except retries which are logged at WARN
+// since they indicate a failing dependency.
+func NewLogger(client *retryablehttp.Client) *Logger {
+	return &Logger{client: client}
+}
+
+// Debug logs a message at DEBUG, or WARN for retries
+func (l *Logger) Debug(msg string, keysAndValues ...interface{}) {
+	level := logger.LevelDEBUG
+	if msg == "retrying request" {
+		level = logger.LevelWARN
+	}
+	l.log(level, msg, keysAndValues)
+}
+
+// Info logs a message at INFO
+func (l *Logger) Info(msg string, keysAndValues ...interface{}) {
+	l.log(logger.LevelINFO, msg, keysAndValues)
+}
+
+// Warn logs a message at WARN
+func (l *Logger) Warn(msg string, keysAndValues ...interface{}) {
+	l.log(logger.LevelWARN, msg, keysAndValues)
+}
+
+// Error logs a message at ERROR
+func (l *Logger) Error(msg string, keysAndValues ...interface{}) {
+	l.log(logger.LevelERROR, msg, keysAndValues)
+}
+
+func (l *Logger) log(level logger.LogLevel, msg string, keysAndValues []interface{}) {
+	logger.GetInstance().Log(context.Background(), level, logger.TypeHTTP, msg, l.fields(keysAndValues))
+}
+
+// fields converts the client's alternating keys and values to a
+// LogContext, splitting the retry description into method, url and
+// status_code. URLs are logged without their query. A trailing key without
+// a value is ignored.
+func (l *Logger) fields(keysAndValues []interface{}) logger.LogContext {
+	context := logger.LogContext{"component": "retryablehttp"}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key, value := fmt.Sprint(keysAndValues[i]), keysAndValues[i+1]
+		switch key {
+		case "request":
+			description := fmt.Sprint(value)
+			if match := retryRequestPattern.FindStringSubmatch(description); match != nil {
+				context["method"] = match[1]
+				context["url"] = stripQuery(match[2])
+				if statusCode, err := strconv.Atoi(match[3]); err == nil {
+					context["status_code"] = statusCode
+				}
+			} else {
+				context["request"] = description
+			}
+		case "timeout":
+			if wait, ok := value.(time.Duration); ok {
+				context["backoff_ms"] = wait.Milliseconds()
+			} else {
+				context["backoff"] = value
+			}
+		case "remaining":
+			context["retries_remaining"] = value
+			if remaining, ok := value.(int); ok && l.client != nil {
+				// The client logs RetryMax-i remaining retries after attempt i+1
+				context["http.attempt"] = l.client.RetryMax - remaining + 1
+			}
+		case "url":
+			context["url"] = stripQuery(fmt.Sprint(value))
+		default:
+			context[key] = value
+		}
+	}
+	return context
+}
+
+// stripQuery removes the query, fragment and user info from a URL, which
+// may carry credentials
+func stripQuery(raw string) string {
+	target, err := url.Parse(raw)
+	if err != nil {
+		raw, _, _ = strings.Cut(raw, "?")
+		return raw
+	}
+	target.User = nil
+	target.RawQuery = ""
+	target.ForceQuery = false
+	target.Fragment = ""
+	target.RawFragment = ""
+	return target.String()
+}
@@ -0,0 +1,69 @@
+// Package logsarama routes IBM/sarama client logs into the rcommerz logger
+// as structured messaging entries.
+package logsarama
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	logger "github.com/rcommerz/logger-go"
+)
+
+// Adapter implements Sarama's StdLogger interface (Print, Printf and
+// Println), writing Sarama's messages as structured messaging entries
+type Adapter struct {
+	logger *logger.Logger
+	level  logger.LogLevel
+}
+
+// New returns an adapter for sarama.Logger. Messages are logged at INFO,
+// or WARN when they report an error or failure.
+//
+//	sarama.Logger = logsarama.New(log)
+func New(l *logger.Logger) *Adapter {
+	return &Adapter{logger: l, level: logger.LevelINFO}
+}
+
+// NewDebug returns an adapter for sarama.DebugLogger, logging messages at
+// DEBUG
+//
+//	sarama.DebugLogger = logsarama.NewDebug(log)
+func NewDebug(l *logger.Logger) *Adapter {
+	return &Adapter{logger: l, level: logger.LevelDEBUG}
+}
+
+func (a *Adapter) Print(v ...interface{}) {
+	a.log(fmt.Sprint(v...))
+}
+
+func (a *Adapter) Printf(format string, v ...interface{}) {
+	a.log(fmt.Sprintf(format, v...))
+}
+
+func (a *Adapter) Println(v ...interface{}) {
+	a.log(fmt.Sprintln(v...))
+}
+
+// failureWords mark Sarama messages that report a problem
+var failureWords = []string{"error", "failed", "unable", "cannot", "closed"}
+
+func (a *Adapter) log(message string) {
+	message = strings.TrimSpace(message)
+
+	level := a.level
+	if level == logger.LevelINFO {
+		lower := strings.ToLower(message)
+		for _, word := range failureWords {
+			if strings.Contains(lower, word) {
+				level = logger.LevelWARN
+				break
+			}
+		}
+	}
+
+	a.logger.Log(context.Background(), level, logger.TypeMessaging, message, logger.LogContext{
+		"messaging.system": "kafka",
+		"component":        "sarama",
+	})
+}
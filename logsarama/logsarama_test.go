@@ -0,0 +1,46 @@
+package logsarama
+
+import (
+	"testing"
+
+	logger "github.com/rcommerz/logger-go"
+	"github.com/rcommerz/logger-go/logtest"
+)
+
+// stdLogger mirrors sarama.StdLogger
+type stdLogger interface {
+	Print(v ...interface{})
+	Printf(format string, v ...interface{})
+	Println(v ...interface{})
+}
+
+func TestAdapter(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      func(stdLogger)
+		adapter  func(*logger.Logger) *Adapter
+		message  string
+		expected logger.LogLevel
+	}{
+		{"info", func(l stdLogger) { l.Printf("Connected to broker at %s (registered as #%d)\n", "kafka:9092", 1) }, New, "Connected to broker at kafka:9092 (registered as #1)", logger.LevelINFO},
+		{"failure", func(l stdLogger) { l.Println("Failed to connect to broker", "kafka:9092") }, New, "Failed to connect to broker kafka:9092", logger.LevelWARN},
+		{"debug", func(l stdLogger) {
+			l.Print("client/metadata fetching metadata for all topics from broker ", "kafka:9092")
+		}, NewDebug, "client/metadata fetching metadata for all topics from broker kafka:9092", logger.LevelDEBUG},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := logtest.NewRecorder()
+			tt.log(tt.adapter(rec.Logger))
+
+			if entries := rec.Entries(); len(entries) != 1 {
+				t.Fatalf("Expected 1 log entry, got %d", len(entries))
+			}
+			rec.AssertLogged(t, tt.expected, tt.message).
+				AssertType(t, logger.TypeMessaging).
+				AssertField(t, "component", "sarama").
+				AssertField(t, "messaging.system", "kafka")
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package logsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"time"
+)
+
+// Errors returned by BeginTx for drivers without driver.ConnBeginTx, with
+// database/sql's wording
+var (
+	errNonDefaultIsolation = errors.New("sql: driver does not support non-default isolation level")
+	errReadOnly            = errors.New("sql: driver does not support read-only transactions")
+)
+
+// wrappedDriver opens logging connections
+type wrappedDriver struct {
+	driver.Driver
+	opts *Options
+}
+
+func (d *wrappedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedConn{Conn: conn, opts: d.opts}, nil
+}
+
+func (d *wrappedDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.Driver.(driver.DriverContext); ok {
+		connector, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return &wrappedConnector{Connector: connector, driver: d}, nil
+	}
+	return &dsnConnector{name: name, driver: d}, nil
+}
+
+// wrappedConnector opens logging connections from a connector
+type wrappedConnector struct {
+	driver.Connector
+	driver *wrappedDriver
+}
+
+func (c *wrappedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedConn{Conn: conn, opts: c.driver.opts}, nil
+}
+
+func (c *wrappedConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// dsnConnector connects drivers that don't implement driver.DriverContext
+type dsnConnector struct {
+	name   string
+	driver *wrappedDriver
+}
+
+func (c *dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.name)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// wrappedConn logs statements run on a connection. Optional interfaces the
+// wrapped connection lacks return driver.ErrSkip, so database/sql falls
+// back to the required ones.
+type wrappedConn struct {
+	driver.Conn
+	opts *Options
+}
+
+func (c *wrappedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *wrappedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	st := statement{operation: "PREPARE", start: time.Now()}
+
+	var stmt driver.Stmt
+	var err error
+	if pc, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = pc.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		st.query = query
+		c.opts.logStatement(ctx, st, -1, err)
+		return nil, err
+	}
+	return &wrappedStmt{Stmt: stmt, conn: c, query: query}, nil
+}
+
+func (c *wrappedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *wrappedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	st := statement{operation: "BEGIN", start: time.Now()}
+
+	var tx driver.Tx
+	var err error
+	switch bc, ok := c.Conn.(driver.ConnBeginTx); {
+	case ok:
+		tx, err = bc.BeginTx(ctx, opts)
+	case opts.Isolation != driver.IsolationLevel(sql.LevelDefault):
+		// Reject options Begin cannot honor, as database/sql does
+		err = errNonDefaultIsolation
+	case opts.ReadOnly:
+		err = errReadOnly
+	default:
+		tx, err = c.Conn.Begin()
+	}
+	c.opts.logStatement(ctx, st, -1, err)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedTx{Tx: tx, ctx: ctx, opts: c.opts}, nil
+}
+
+func (c *wrappedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	st := statement{operation: "EXEC", query: query, args: args, start: time.Now()}
+	result, err := ec.ExecContext(ctx, query, args)
+	c.opts.logStatement(ctx, st, rowsAffected(result, err), err)
+	return result, err
+}
+
+func (c *wrappedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	st := statement{operation: "QUERY", query: query, args: args, start: time.Now()}
+	rows, err := qc.QueryContext(ctx, query, args)
+	c.opts.logStatement(ctx, st, -1, err)
+	return rows, err
+}
+
+func (c *wrappedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *wrappedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *wrappedConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *wrappedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nvc, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return nvc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// wrappedStmt logs executions of a prepared statement
+type wrappedStmt struct {
+	driver.Stmt
+	conn  *wrappedConn
+	query string
+}
+
+func (s *wrappedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *wrappedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	st := statement{operation: "EXEC", query: s.query, args: args, start: time.Now()}
+
+	var result driver.Result
+	var err error
+	if ec, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = ec.ExecContext(ctx, args)
+	} else {
+		result, err = s.Stmt.Exec(values(args))
+	}
+	s.conn.opts.logStatement(ctx, st, rowsAffected(result, err), err)
+	return result, err
+}
+
+func (s *wrappedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *wrappedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	st := statement{operation: "QUERY", query: s.query, args: args, start: time.Now()}
+
+	var rows driver.Rows
+	var err error
+	if qc, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = qc.QueryContext(ctx, args)
+	} else {
+		rows, err = s.Stmt.Query(values(args))
+	}
+	s.conn.opts.logStatement(ctx, st, -1, err)
+	return rows, err
+}
+
+// CheckNamedValue prefers the statement's checker, then the connection's,
+// as database/sql does
+func (s *wrappedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if nvc, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return nvc.CheckNamedValue(nv)
+	}
+	return s.conn.CheckNamedValue(nv)
+}
+
+func (s *wrappedStmt) ColumnConverter(idx int) driver.ValueConverter {
+	if cc, ok := s.Stmt.(driver.ColumnConverter); ok {
+		return cc.ColumnConverter(idx)
+	}
+	return driver.DefaultParameterConverter
+}
+
+// wrappedTx logs transaction commits and rollbacks
+type wrappedTx struct {
+	driver.Tx
+	ctx  context.Context
+	opts *Options
+}
+
+func (t *wrappedTx) Commit() error {
+	st := statement{operation: "COMMIT", start: time.Now()}
+	err := t.Tx.Commit()
+	t.opts.logStatement(t.ctx, st, -1, err)
+	return err
+}
+
+func (t *wrappedTx) Rollback() error {
+	st := statement{operation: "ROLLBACK", start: time.Now()}
+	err := t.Tx.Rollback()
+	t.opts.logStatement(t.ctx, st, -1, err)
+	return err
+}
+
+// rowsAffected returns the rows affected by a successful exec, or -1
+func rowsAffected(result driver.Result, err error) int64 {
+	if err != nil || result == nil {
+		return -1
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	plain := make([]driver.Value, len(args))
+	for i, arg := range args {
+		plain[i] = arg.Value
+	}
+	return plain
+}
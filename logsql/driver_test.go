@@ -0,0 +1,315 @@
+package logsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	logger "github.com/rcommerz/logger-go"
+)
+
+// ctxDriver implements the optional context interfaces. Statements fail
+// with errFake when the query is "FAIL" and are canceled when it is
+// "CANCEL"; prepared statements take delay to run.
+type ctxDriver struct {
+	delay time.Duration
+}
+
+var errFake = errors.New("fake failure")
+
+func (d ctxDriver) Open(string) (driver.Conn, error) { return &ctxConn{delay: d.delay}, nil }
+
+func (d ctxDriver) OpenConnector(name string) (driver.Connector, error) {
+	if name == "FAIL" {
+		return nil, errFake
+	}
+	return ctxConnector{d}, nil
+}
+
+type ctxConnector struct{ d ctxDriver }
+
+func (c ctxConnector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c ctxConnector) Driver() driver.Driver                        { return c.d }
+
+type ctxConn struct {
+	delay time.Duration
+}
+
+func queryErr(query string) error {
+	switch query {
+	case "FAIL":
+		return errFake
+	case "CANCEL":
+		return context.Canceled
+	}
+	return nil
+}
+
+func (c *ctxConn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("unused") }
+func (c *ctxConn) Close() error                              { return nil }
+func (c *ctxConn) Begin() (driver.Tx, error)                 { return nil, errors.New("unused") }
+
+func (c *ctxConn) PrepareContext(_ context.Context, query string) (driver.Stmt, error) {
+	if query == "BAD" {
+		return nil, errFake
+	}
+	return &ctxStmt{query: query, delay: c.delay}, nil
+}
+
+func (c *ctxConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if !opts.ReadOnly {
+		return nil, errors.New("expected the transaction options to be passed through")
+	}
+	return failingTx{}, nil
+}
+
+func (c *ctxConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if err := queryErr(query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *ctxConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if err := queryErr(query); err != nil {
+		return nil, err
+	}
+	return fakeRows{}, nil
+}
+
+func (c *ctxConn) Ping(context.Context) error         { return nil }
+func (c *ctxConn) ResetSession(context.Context) error { return nil }
+func (c *ctxConn) IsValid() bool                      { return true }
+
+// CheckNamedValue stores durations as milliseconds
+func (c *ctxConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if d, ok := nv.Value.(time.Duration); ok {
+		nv.Value = d.Milliseconds()
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+type ctxStmt struct {
+	query string
+	delay time.Duration
+}
+
+func (s *ctxStmt) Close() error                               { return nil }
+func (s *ctxStmt) NumInput() int                              { return -1 }
+func (s *ctxStmt) Exec([]driver.Value) (driver.Result, error) { return nil, errors.New("unused") }
+func (s *ctxStmt) Query([]driver.Value) (driver.Rows, error)  { return nil, errors.New("unused") }
+
+func (s *ctxStmt) ExecContext(context.Context, []driver.NamedValue) (driver.Result, error) {
+	time.Sleep(s.delay)
+	return driver.RowsAffected(3), nil
+}
+
+func (s *ctxStmt) QueryContext(context.Context, []driver.NamedValue) (driver.Rows, error) {
+	time.Sleep(s.delay)
+	return fakeRows{}, queryErr(s.query)
+}
+
+func (s *ctxStmt) ColumnConverter(int) driver.ValueConverter { return driver.Int32 }
+
+// failingTx fails to commit
+type failingTx struct{}
+
+func (failingTx) Commit() error   { return errFake }
+func (failingTx) Rollback() error { return nil }
+
+// operations returns the level and db.operation of the entries logged so far
+func operations() []string {
+	var logged []string
+	for _, entry := range takeEntries() {
+		logged = append(logged, string(entry.Level)+" "+entry.Fields["db.operation"].(string))
+	}
+	return logged
+}
+
+func expectOperations(t *testing.T, expected ...string) {
+	t.Helper()
+	logged := operations()
+	if len(logged) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, logged)
+	}
+	for i := range expected {
+		if logged[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, logged)
+		}
+	}
+}
+
+func TestContextDriver(t *testing.T) {
+	db := sql.OpenDB(WrapConnector(ctxConnector{ctxDriver{delay: 5 * time.Millisecond}}, &Options{SlowThreshold: time.Millisecond, KeepLiterals: true}))
+	defer db.Close()
+	ctx := context.Background()
+	takeEntries()
+
+	t.Run("conn", func(t *testing.T) {
+		if err := db.PingContext(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.ExecContext(ctx, "DELETE FROM t WHERE id = 1", time.Second); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.QueryContext(ctx, "FAIL"); !errors.Is(err, errFake) {
+			t.Fatalf("Expected errFake, got %v", err)
+		}
+		if _, err := db.ExecContext(ctx, "CANCEL"); err == nil {
+			t.Fatal("Expected the exec to be canceled")
+		}
+		logged := takeEntries()
+		if len(logged) != 3 || logged[0].Fields["db.statement"] != "DELETE FROM t WHERE id = 1" {
+			t.Fatalf("Expected 3 entries with literals kept, got %v", logged)
+		}
+		if logged[1].Level != logger.LevelERROR || logged[2].Level != logger.LevelWARN {
+			t.Errorf("Expected ERROR for failures and WARN for cancellations, got %s and %s", logged[1].Level, logged[2].Level)
+		}
+	})
+
+	t.Run("stmt", func(t *testing.T) {
+		stmt, err := db.PrepareContext(ctx, "UPDATE t SET n = n + 1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		if _, err := stmt.ExecContext(ctx, int64(1)); err != nil {
+			t.Fatal(err)
+		}
+		rows, err := stmt.QueryContext(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows.Close()
+
+		if _, err := db.PrepareContext(ctx, "BAD"); !errors.Is(err, errFake) {
+			t.Fatalf("Expected the prepare to fail, got %v", err)
+		}
+
+		logged := takeEntries()
+		if len(logged) != 3 || logged[0].Fields["slow"] != true || logged[0].Fields["rows_affected"] != int64(3) {
+			t.Fatalf("Expected a slow exec with rows_affected, got %v", logged)
+		}
+		if logged[2].Fields["db.statement"] != "BAD" || logged[2].Level != logger.LevelERROR {
+			t.Errorf("Expected the failed prepare to be logged with its query, got %v", logged[2])
+		}
+	})
+
+	t.Run("tx", func(t *testing.T) {
+		tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); !errors.Is(err, errFake) {
+			t.Fatalf("Expected the commit to fail, got %v", err)
+		}
+		expectOperations(t, "DEBUG BEGIN", "ERROR COMMIT")
+
+		tx, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+		expectOperations(t, "DEBUG BEGIN", "DEBUG ROLLBACK")
+	})
+
+	if _, err := WrapConnector(ctxConnector{}, nil).Driver().(driver.DriverContext).OpenConnector("FAIL"); !errors.Is(err, errFake) {
+		t.Errorf("Expected OpenConnector errors to be returned, got %v", err)
+	}
+}
+
+func TestLegacyDriver(t *testing.T) {
+	conn, err := Wrap(fakeDriver{}, nil).Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	takeEntries()
+
+	t.Run("should prepare and run statements without contexts", func(t *testing.T) {
+		stmt, err := conn.Prepare("INSERT INTO t VALUES (1)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stmt.Exec([]driver.Value{int64(1)}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stmt.Query(nil); err != nil {
+			t.Fatal(err)
+		}
+		if converter := stmt.(driver.ColumnConverter).ColumnConverter(0); converter != driver.DefaultParameterConverter {
+			t.Errorf("Expected the default converter, got %v", converter)
+		}
+		expectOperations(t, "DEBUG INSERT", "DEBUG INSERT")
+	})
+
+	t.Run("should skip optional interfaces the driver lacks", func(t *testing.T) {
+		wrapped := conn.(*wrappedConn)
+		if _, err := wrapped.ExecContext(context.Background(), "SELECT 1", nil); err != driver.ErrSkip {
+			t.Errorf("Expected driver.ErrSkip for exec, got %v", err)
+		}
+		if _, err := wrapped.QueryContext(context.Background(), "SELECT 1", nil); err != driver.ErrSkip {
+			t.Errorf("Expected driver.ErrSkip for query, got %v", err)
+		}
+		if err := wrapped.CheckNamedValue(&driver.NamedValue{}); err != driver.ErrSkip {
+			t.Errorf("Expected driver.ErrSkip for named values, got %v", err)
+		}
+		if wrapped.Ping(context.Background()) != nil || wrapped.ResetSession(context.Background()) != nil || !wrapped.IsValid() {
+			t.Error("Expected defaults for Ping, ResetSession and IsValid")
+		}
+		if len(operations()) != 0 {
+			t.Error("Expected skipped calls not to be logged")
+		}
+	})
+
+	t.Run("should reject transaction options Begin cannot honor", func(t *testing.T) {
+		wrapped := conn.(*wrappedConn)
+		tests := []struct {
+			opts     driver.TxOptions
+			expected error
+		}{
+			{driver.TxOptions{Isolation: driver.IsolationLevel(sql.LevelSerializable)}, errNonDefaultIsolation},
+			{driver.TxOptions{ReadOnly: true}, errReadOnly},
+		}
+		for _, tt := range tests {
+			if _, err := wrapped.BeginTx(context.Background(), tt.opts); err != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		}
+		expectOperations(t, "ERROR BEGIN", "ERROR BEGIN")
+
+		tx, err := conn.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+		expectOperations(t, "DEBUG BEGIN", "DEBUG ROLLBACK")
+	})
+}
+
+func TestOpen(t *testing.T) {
+	sql.Register("logsql-fake", fakeDriver{})
+
+	db, err := Open("logsql-fake", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	takeEntries()
+
+	if _, err := db.Exec("DELETE FROM t"); err != nil {
+		t.Fatal(err)
+	}
+	expectOperations(t, "DEBUG DELETE")
+
+	if _, err := Open("logsql-missing", "", nil); err == nil {
+		t.Error("Expected an error for an unknown driver")
+	}
+}
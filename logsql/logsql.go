@@ -0,0 +1,132 @@
+// Package logsql wraps database/sql drivers so every statement is logged
+// through the rcommerz logger with its query, duration, rows affected and
+// error, as log_type "db".
+package logsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	logger "github.com/rcommerz/logger-go"
+	"github.com/rcommerz/logger-go/internal/sqltext"
+)
+
+// Options configures statement logging
+type Options struct {
+	// System is the database system, logged as db.system (e.g. "postgresql")
+	System string
+	// SlowThreshold logs statements slower than this at WARN (0 = disabled)
+	SlowThreshold time.Duration
+	// KeepLiterals logs query text as-is. By default string and numeric
+	// literals are replaced with ? so inlined values are not logged.
+	KeepLiterals bool
+	// LogArgs logs statement arguments as db.args. Values still pass
+	// through the logger's Redactor.
+	LogArgs bool
+}
+
+// Wrap returns a driver that logs statements executed through d
+func Wrap(d driver.Driver, opts *Options) driver.Driver {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &wrappedDriver{Driver: d, opts: opts}
+}
+
+// WrapConnector returns a connector that logs statements executed through
+// connections from c, for use with sql.OpenDB
+func WrapConnector(c driver.Connector, opts *Options) driver.Connector {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &wrappedConnector{Connector: c, driver: &wrappedDriver{Driver: c.Driver(), opts: opts}}
+}
+
+// Open opens a database using the registered driver driverName, logging
+// statements executed through it
+func Open(driverName, dataSourceName string, opts *Options) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	d := db.Driver()
+	_ = db.Close()
+
+	connector, err := Wrap(d, opts).(driver.DriverContext).OpenConnector(dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
+
+// statement describes a logged driver call
+type statement struct {
+	operation string
+	query     string
+	args      []driver.NamedValue
+	start     time.Time
+}
+
+// logStatement logs a completed driver call. driver.ErrSkip is not an
+// error: database/sql retries the call another way.
+func (o *Options) logStatement(ctx context.Context, st statement, rowsAffected int64, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+
+	duration := time.Since(st.start)
+	canceled := errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+
+	context := logger.LogContext{
+		"db.operation": st.operation,
+		"duration_ms":  duration.Milliseconds(),
+	}
+	if o.System != "" {
+		context["db.system"] = o.System
+	}
+	if st.query != "" {
+		context["db.statement"] = o.statementText(st.query)
+		if op := sqltext.Operation(st.query); op != "" {
+			context["db.operation"] = op
+		}
+	}
+	if o.LogArgs && len(st.args) > 0 {
+		args := make([]interface{}, len(st.args))
+		for i, arg := range st.args {
+			args[i] = arg.Value
+		}
+		context["db.args"] = args
+	}
+	if rowsAffected >= 0 {
+		context["rows_affected"] = rowsAffected
+	}
+
+	level := logger.LevelDEBUG
+	switch {
+	case canceled:
+		context["error"] = err
+		level = logger.LevelWARN
+	case err != nil:
+		context["error"] = err
+		level = logger.LevelERROR
+	case o.SlowThreshold > 0 && duration >= o.SlowThreshold:
+		context["slow"] = true
+		level = logger.LevelWARN
+	}
+
+	message := fmt.Sprintf("sql %s", strings.ToLower(context["db.operation"].(string)))
+	logger.GetInstance().Log(ctx, level, logger.TypeDB, message, context)
+}
+
+// statementText returns the query as it should be logged
+func (o *Options) statementText(query string) string {
+	if o.KeepLiterals {
+		return query
+	}
+	return sqltext.RedactLiterals(query)
+}
@@ -0,0 +1,160 @@
+package logsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	logger "github.com/rcommerz/logger-go"
+)
+
+var (
+	entriesMu sync.Mutex
+	entries   []logger.Entry
+)
+
+func TestMain(m *testing.M) {
+	log := logger.Initialize(logger.Config{
+		ServiceName:    "logsql-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          logger.LevelDEBUG,
+	})
+	log.RegisterHook(func(entry logger.Entry) logger.Entry {
+		entriesMu.Lock()
+		entries = append(entries, entry)
+		entriesMu.Unlock()
+		return entry
+	})
+	os.Exit(m.Run())
+}
+
+// takeEntries returns and clears the entries logged so far
+func takeEntries() []logger.Entry {
+	entriesMu.Lock()
+	defer entriesMu.Unlock()
+	taken := entries
+	entries = nil
+	return taken
+}
+
+// fakeDriver is a minimal driver: Exec succeeds with 2 rows affected
+// unless the query is "FAIL", and Query returns no rows
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{query: query}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+type fakeStmt struct{ query string }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	if s.query == "FAIL" {
+		return nil, errors.New("syntax error")
+	}
+	return driver.RowsAffected(2), nil
+}
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) { return fakeRows{}, nil }
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string         { return []string{"id"} }
+func (fakeRows) Close() error              { return nil }
+func (fakeRows) Next([]driver.Value) error { return io.EOF }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+func TestWrap(t *testing.T) {
+	connector, err := Wrap(fakeDriver{}, &Options{System: "fake", LogArgs: true}).(driver.DriverContext).OpenConnector("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	ctx := context.Background()
+	takeEntries()
+
+	result, err := db.ExecContext(ctx, "UPDATE users SET name = 'bob' WHERE id = $1", 7)
+	if err != nil {
+		t.Fatalf("Expected exec to succeed, got %v", err)
+	}
+	if n, _ := result.RowsAffected(); n != 2 {
+		t.Errorf("Expected 2 rows affected, got %d", n)
+	}
+
+	logged := takeEntries()
+	if len(logged) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(logged))
+	}
+	entry := logged[0]
+	if entry.Type != logger.TypeDB || entry.Level != logger.LevelDEBUG {
+		t.Errorf("Expected DEBUG db entry, got %s %s", entry.Level, entry.Type)
+	}
+	expected := map[string]interface{}{
+		"db.system":     "fake",
+		"db.operation":  "UPDATE",
+		"db.statement":  "UPDATE users SET name = ? WHERE id = $1",
+		"rows_affected": int64(2),
+	}
+	for key, value := range expected {
+		if entry.Fields[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, entry.Fields[key])
+		}
+	}
+	if args, ok := entry.Fields["db.args"].([]interface{}); !ok || len(args) != 1 || args[0] != int64(7) {
+		t.Errorf("Expected db.args [7], got %v", entry.Fields["db.args"])
+	}
+
+	t.Run("should log errors at ERROR", func(t *testing.T) {
+		if _, err := db.ExecContext(ctx, "FAIL"); err == nil {
+			t.Fatal("Expected exec to fail")
+		}
+		logged := takeEntries()
+		if len(logged) != 1 || logged[0].Level != logger.LevelERROR || logged[0].Fields["error_message"] != "syntax error" {
+			t.Errorf("Expected an ERROR entry with the error, got %v", logged)
+		}
+	})
+
+	t.Run("should log queries and transactions", func(t *testing.T) {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, err := tx.QueryContext(ctx, "SELECT id FROM users")
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows.Close()
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+
+		var operations []interface{}
+		for _, entry := range takeEntries() {
+			operations = append(operations, entry.Fields["db.operation"])
+		}
+		want := []interface{}{"BEGIN", "SELECT", "COMMIT"}
+		if len(operations) != len(want) {
+			t.Fatalf("Expected operations %v, got %v", want, operations)
+		}
+		for i := range want {
+			if operations[i] != want[i] {
+				t.Errorf("Expected operations %v, got %v", want, operations)
+			}
+		}
+	})
+}
@@ -0,0 +1,85 @@
+// Package logtemporal adapts the rcommerz logger to the Temporal SDK's
+// log.Logger interface, so workflow, activity and worker logs are written
+// as structured entries:
+//
+//	c, err := client.Dial(client.Options{Logger: logtemporal.NewLogger()})
+package logtemporal
+
+import (
+	"context"
+	"fmt"
+
+	logger "github.com/rcommerz/logger-go"
+	"go.temporal.io/sdk/log"
+)
+
+// fieldNames maps the keys the Temporal SDK logs to this package's
+// snake_case field names. Other keys are logged unchanged.
+var fieldNames = map[string]string{
+	"Namespace":    "temporal.namespace",
+	"TaskQueue":    "temporal.task_queue",
+	"WorkerID":     "temporal.worker_id",
+	"WorkflowID":   "workflow_id",
+	"RunID":        "run_id",
+	"WorkflowType": "workflow_type",
+	"ActivityID":   "activity_id",
+	"ActivityType": "activity_type",
+	"Attempt":      "attempt",
+	"Error":        "error",
+}
+
+// Logger implements the Temporal SDK's log.Logger and log.WithLogger
+type Logger struct {
+	fields logger.LogContext
+}
+
+var _ log.WithLogger = (*Logger)(nil)
+
+// NewLogger returns a Temporal logger writing through the global logger.
+// Temporal levels map to the logger's levels of the same name.
+func NewLogger() *Logger {
+	return &Logger{fields: logger.LogContext{"component": "temporal"}}
+}
+
+// Debug logs a message at DEBUG
+func (l *Logger) Debug(msg string, keyvals ...interface{}) {
+	logger.GetInstance().Debug(context.Background(), msg, l.context(keyvals))
+}
+
+// Info logs a message at INFO
+func (l *Logger) Info(msg string, keyvals ...interface{}) {
+	logger.GetInstance().Info(context.Background(), msg, l.context(keyvals))
+}
+
+// Warn logs a message at WARN
+func (l *Logger) Warn(msg string, keyvals ...interface{}) {
+	logger.GetInstance().Warn(context.Background(), msg, l.context(keyvals))
+}
+
+// Error logs a message at ERROR
+func (l *Logger) Error(msg string, keyvals ...interface{}) {
+	logger.GetInstance().Error(context.Background(), msg, l.context(keyvals))
+}
+
+// With returns a child logger adding keyvals to every entry, as the SDK
+// does with the workflow and activity identifiers
+func (l *Logger) With(keyvals ...interface{}) log.Logger {
+	return &Logger{fields: l.context(keyvals)}
+}
+
+// context merges the logger's fields with Temporal's alternating keys and
+// values. A trailing key without a value is ignored.
+func (l *Logger) context(keyvals []interface{}) logger.LogContext {
+	context := make(logger.LogContext, len(l.fields)+len(keyvals)/2)
+	for key, value := range l.fields {
+		context[key] = value
+	}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		if name, ok := fieldNames[key]; ok {
+			key = name
+		}
+		context[key] = keyvals[i+1]
+	}
+	return context
+}
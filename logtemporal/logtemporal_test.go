@@ -0,0 +1,103 @@
+package logtemporal
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	logger "github.com/rcommerz/logger-go"
+	"go.temporal.io/sdk/log"
+)
+
+var (
+	entriesMu sync.Mutex
+	entries   []logger.Entry
+)
+
+func TestMain(m *testing.M) {
+	log := logger.Initialize(logger.Config{
+		ServiceName:    "logtemporal-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          logger.LevelDEBUG,
+	})
+	log.RegisterHook(func(entry logger.Entry) logger.Entry {
+		entriesMu.Lock()
+		entries = append(entries, entry)
+		entriesMu.Unlock()
+		return entry
+	})
+	os.Exit(m.Run())
+}
+
+// takeEntries returns and clears the entries logged so far
+func takeEntries() []logger.Entry {
+	entriesMu.Lock()
+	defer entriesMu.Unlock()
+	taken := entries
+	entries = nil
+	return taken
+}
+
+func TestLogger(t *testing.T) {
+	tests := []struct {
+		name  string
+		log   func(log.Logger)
+		level logger.LogLevel
+	}{
+		{"debug", func(l log.Logger) { l.Debug("Task processing") }, logger.LevelDEBUG},
+		{"info", func(l log.Logger) { l.Info("Started Worker") }, logger.LevelINFO},
+		{"warn", func(l log.Logger) { l.Warn("Activity heartbeat timeout") }, logger.LevelWARN},
+		{"error", func(l log.Logger) { l.Error("Activity error") }, logger.LevelERROR},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.log(NewLogger())
+
+			logged := takeEntries()
+			if len(logged) != 1 {
+				t.Fatalf("Expected 1 entry, got %d", len(logged))
+			}
+			if logged[0].Level != tt.level {
+				t.Errorf("Expected %s, got %s", tt.level, logged[0].Level)
+			}
+			if logged[0].Fields["component"] != "temporal" {
+				t.Errorf("Expected component=temporal, got %v", logged[0].Fields["component"])
+			}
+		})
+	}
+
+	t.Run("should preserve workflow identifiers", func(t *testing.T) {
+		workflowLogger := log.With(NewLogger(), "Namespace", "default", "WorkflowID", "order-42", "RunID", "run-1")
+		workflowLogger.Info("Workflow started", "Attempt", 2, "custom", "value")
+
+		logged := takeEntries()
+		if len(logged) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(logged))
+		}
+		expected := map[string]interface{}{
+			"temporal.namespace": "default",
+			"workflow_id":        "order-42",
+			"run_id":             "run-1",
+			"attempt":            2,
+			"custom":             "value",
+		}
+		for key, value := range expected {
+			if logged[0].Fields[key] != value {
+				t.Errorf("Expected %s=%v, got %v", key, value, logged[0].Fields[key])
+			}
+		}
+	})
+
+	t.Run("should not mutate the parent logger", func(t *testing.T) {
+		parent := NewLogger()
+		parent.With("WorkflowID", "order-42")
+		parent.Info("Worker stopped")
+
+		logged := takeEntries()
+		if _, ok := logged[0].Fields["workflow_id"]; ok {
+			t.Error("Expected parent logger to be unchanged")
+		}
+	})
+}
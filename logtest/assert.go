@@ -0,0 +1,113 @@
+package logtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	logger "github.com/rcommerz/logger-go"
+)
+
+// levelRank orders levels for AssertNoneAbove
+var levelRank = map[logger.LogLevel]int{
+	logger.LevelDEBUG: 0,
+	logger.LevelINFO:  1,
+	logger.LevelWARN:  2,
+	logger.LevelERROR: 3,
+}
+
+// EntryAssertion makes further assertions on the entry matched by
+// AssertLogged
+type EntryAssertion struct {
+	// Entry is the matched entry, nil when AssertLogged failed
+	Entry *Entry
+}
+
+// AssertLogged fails t unless an entry with the level and message was
+// recorded, and returns an assertion on the first such entry:
+//
+//	rec.AssertLogged(t, logger.LevelERROR, "Payment failed").
+//		AssertField(t, "order_id", "123")
+func (r *Recorder) AssertLogged(t testing.TB, level logger.LogLevel, message string) *EntryAssertion {
+	t.Helper()
+	entries := r.Entries()
+	for i := range entries {
+		if entries[i].Level == level && entries[i].Message == message {
+			return &EntryAssertion{Entry: &entries[i]}
+		}
+	}
+	t.Errorf("Expected %s entry %q, recorded:%s", level, message, summarize(entries))
+	return &EntryAssertion{}
+}
+
+// AssertNoneAbove fails t if an entry above level was recorded, e.g.
+// AssertNoneAbove(t, logger.LevelWARN) for no errors
+func (r *Recorder) AssertNoneAbove(t testing.TB, level logger.LogLevel) {
+	t.Helper()
+	var above []Entry
+	for _, entry := range r.Entries() {
+		if levelRank[entry.Level] > levelRank[level] {
+			above = append(above, entry)
+		}
+	}
+	if len(above) > 0 {
+		t.Errorf("Expected no entries above %s, recorded:%s", level, summarize(above))
+	}
+}
+
+// AssertField fails t unless the entry has the field with a value equal to
+// expected once encoded, so AssertField(t, "items", 3) matches the decoded
+// int64. It returns a for chaining.
+func (a *EntryAssertion) AssertField(t testing.TB, key string, expected interface{}) *EntryAssertion {
+	t.Helper()
+	if a.Entry == nil {
+		return a
+	}
+
+	actual, ok := a.Entry.Fields[key]
+	if !ok {
+		t.Errorf("Expected field %s on %q, got %v", key, a.Entry.Message, a.Entry.Fields)
+		return a
+	}
+	if want := normalize(expected); !reflect.DeepEqual(actual, want) {
+		t.Errorf("Expected field %s = %v (%T) on %q, got %v (%T)", key, want, want, a.Entry.Message, actual, actual)
+	}
+	return a
+}
+
+// AssertType fails t unless the entry has the log type. It returns a for
+// chaining.
+func (a *EntryAssertion) AssertType(t testing.TB, logType logger.LogType) *EntryAssertion {
+	t.Helper()
+	if a.Entry != nil && a.Entry.Type != logType {
+		t.Errorf("Expected type %s on %q, got %s", logType, a.Entry.Message, a.Entry.Type)
+	}
+	return a
+}
+
+// normalize encodes and decodes value as the recorder does
+func normalize(value interface{}) interface{} {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	entry, err := decodeEntry([]byte(`{"value":` + string(encoded) + `}`))
+	if err != nil {
+		return value
+	}
+	return entry.Fields["value"]
+}
+
+// summarize lists entries for failure messages
+func summarize(entries []Entry) string {
+	if len(entries) == 0 {
+		return " none"
+	}
+	var b strings.Builder
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "\n\t%s %q %v", entry.Level, entry.Message, entry.Fields)
+	}
+	return b.String()
+}
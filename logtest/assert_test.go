@@ -0,0 +1,54 @@
+package logtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	logger "github.com/rcommerz/logger-go"
+)
+
+// recordingT records failures instead of failing the test
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	rec := NewRecorder()
+	ctx := context.Background()
+	rec.Info(ctx, "Order created", logger.LogContext{"order_id": "123", "items": 3})
+	rec.Error(ctx, "Payment failed", logger.LogContext{"order_id": "123", "error": errors.New("declined")})
+
+	t.Run("should pass on matching entries", func(t *testing.T) {
+		rec.AssertLogged(t, logger.LevelERROR, "Payment failed").
+			AssertType(t, logger.TypeError).
+			AssertField(t, "order_id", "123").
+			AssertField(t, "error_message", "declined")
+		rec.AssertLogged(t, logger.LevelINFO, "Order created").AssertField(t, "items", 3)
+		rec.AssertNoneAbove(t, logger.LevelERROR)
+	})
+
+	t.Run("should fail on mismatches", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		rec.AssertLogged(rt, logger.LevelWARN, "Payment failed").AssertField(rt, "order_id", "123")
+		rec.AssertLogged(rt, logger.LevelINFO, "Order created").
+			AssertField(rt, "order_id", "456").
+			AssertField(rt, "customer_id", "c1").
+			AssertField(rt, "items", make(chan int)).
+			AssertType(rt, logger.TypeAudit)
+		rec.AssertNoneAbove(rt, logger.LevelWARN)
+
+		// The failed AssertLogged does not cascade into its AssertField
+		if len(rt.failures) != 6 {
+			t.Errorf("Expected 6 failures, got %d: %v", len(rt.failures), rt.failures)
+		}
+	})
+}
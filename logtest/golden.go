@@ -0,0 +1,110 @@
+package logtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// GoldenTime replaces @timestamp in golden output
+const GoldenTime = "2000-01-01T00:00:00.000Z"
+
+// MaskedValue replaces the values of masked keys in golden output
+const MaskedValue = "<masked>"
+
+// UpdateGoldenEnv names the environment variable that, when set to 1,
+// makes AssertGolden write the golden files instead of comparing them
+const UpdateGoldenEnv = "LOGTEST_UPDATE"
+
+// GoldenJSON encodes entries deterministically for golden files: an
+// indented JSON array with sorted keys, @timestamp set to GoldenTime,
+// host.name removed and the values of masked keys (e.g. duration_ms,
+// caller) replaced with MaskedValue
+func GoldenJSON(entries []Entry, masked ...string) ([]byte, error) {
+	encoded := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		fields := make(map[string]interface{}, len(entry.Fields)+6)
+		for key, value := range entry.Fields {
+			fields[key] = value
+		}
+		delete(fields, "host.name")
+
+		fields[timeKey] = GoldenTime
+		fields[levelKey] = string(entry.Level)
+		fields[typeKey] = string(entry.Type)
+		fields[messageKey] = entry.Message
+		if entry.TraceID != "" {
+			fields[traceKey] = entry.TraceID
+			fields[spanKey] = entry.SpanID
+		}
+		for _, key := range masked {
+			if _, ok := fields[key]; ok {
+				fields[key] = MaskedValue
+			}
+		}
+		encoded = append(encoded, fields)
+	}
+
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(encoded); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// AssertGolden fails t unless the recorded entries, encoded with
+// GoldenJSON, match the golden file at path (conventionally under
+// testdata/). Run the tests with LOGTEST_UPDATE=1 to create or update it.
+func (r *Recorder) AssertGolden(t testing.TB, path string, masked ...string) {
+	t.Helper()
+	actual, err := GoldenJSON(r.Entries(), masked...)
+	if err != nil {
+		t.Errorf("Encoding golden entries: %v", err)
+		return
+	}
+
+	if os.Getenv(UpdateGoldenEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("Creating golden directory: %v", err)
+			return
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Errorf("Writing golden file: %v", err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("Reading golden file (run with %s=1 to create it): %v", UpdateGoldenEnv, err)
+		return
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("Log output differs from %s (run with %s=1 to update it)%s", path, UpdateGoldenEnv, firstDifference(string(expected), string(actual)))
+	}
+}
+
+// firstDifference describes the first line that differs
+func firstDifference(expected, actual string) string {
+	expectedLines, actualLines := strings.Split(expected, "\n"), strings.Split(actual, "\n")
+	for i := 0; i < len(expectedLines) || i < len(actualLines); i++ {
+		var want, got string
+		if i < len(expectedLines) {
+			want = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			got = actualLines[i]
+		}
+		if want != got {
+			return fmt.Sprintf(" at line %d:\n\twant: %s\n\tgot:  %s", i+1, want, got)
+		}
+	}
+	return ""
+}
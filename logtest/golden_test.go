@@ -0,0 +1,93 @@
+package logtest
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	logger "github.com/rcommerz/logger-go"
+)
+
+func TestGoldenMiddleware(t *testing.T) {
+	rec := NewRecorderWithConfig(logger.Config{ServiceName: "orders", ServiceVersion: "1.0.0", Env: "test", Level: logger.LevelDEBUG})
+	app := fiber.New()
+	app.Use(logger.FiberMiddleware(&logger.MiddlewareOptions{Logger: rec.Logger}))
+	app.Get("/orders/:id", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusBadGateway).SendString("upstream down")
+	})
+
+	for _, path := range []string{"/orders/123?expand=items", "/fail"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("X-Request-ID", "req-1")
+		req.Header.Set("User-Agent", "logtest")
+		if _, err := app.Test(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec.AssertGolden(t, "testdata/middleware.golden.json", "duration_ms")
+}
+
+func TestGoldenAudit(t *testing.T) {
+	rec := NewRecorderWithConfig(logger.Config{ServiceName: "orders", ServiceVersion: "1.0.0", Env: "test", Level: logger.LevelINFO})
+	err := rec.RecordAudit(context.Background(), logger.AuditEvent{
+		Actor:      "admin-1",
+		Action:     "order.refund",
+		Resource:   "order",
+		ResourceID: "123",
+		Outcome:    logger.AuditSuccess,
+		Before:     map[string]interface{}{"status": "paid"},
+		After:      map[string]interface{}{"status": "refunded"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec.AssertGolden(t, "testdata/audit.golden.json")
+}
+
+func TestAssertGolden(t *testing.T) {
+	rec := NewRecorder()
+	rec.Info(context.Background(), "hello", logger.LogContext{"count": 1, "duration_ms": 12})
+	path := filepath.Join(t.TempDir(), "hello.golden.json")
+
+	t.Run("should report a missing golden file", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		rec.AssertGolden(rt, path)
+		if len(rt.failures) != 1 {
+			t.Errorf("Expected a failure, got %v", rt.failures)
+		}
+	})
+
+	t.Run("should write and then match the golden file", func(t *testing.T) {
+		t.Setenv(UpdateGoldenEnv, "1")
+		rec.AssertGolden(t, path, "duration_ms")
+
+		os.Unsetenv(UpdateGoldenEnv)
+		rec.AssertGolden(t, path, "duration_ms")
+	})
+
+	t.Run("should report an unwritable golden path", func(t *testing.T) {
+		t.Setenv(UpdateGoldenEnv, "1")
+		rt := &recordingT{TB: t}
+		rec.AssertGolden(rt, filepath.Join(path, "nested", "hello.golden.json"))
+		if len(rt.failures) != 1 {
+			t.Errorf("Expected a failure, got %v", rt.failures)
+		}
+	})
+
+	t.Run("should report differences", func(t *testing.T) {
+		rec.Info(context.Background(), "again", nil)
+		rt := &recordingT{TB: t}
+		rec.AssertGolden(rt, path, "duration_ms")
+		if len(rt.failures) != 1 {
+			t.Errorf("Expected a failure, got %v", rt.failures)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+// Package logtest records what the rcommerz logger writes, so tests can
+// assert on an application's logging without capturing stdout. Code under
+// test is handed the recorder's Logger, and the entries it wrote are read
+// back, decoded, with Entries:
+//
+//	rec := logtest.NewRecorder()
+//	svc := NewOrderService(rec.Logger)
+//	svc.Cancel(ctx, "123")
+//
+//	entries := rec.Entries()
+//	if entries[0].Fields["order_id"] != "123" { ... }
+package logtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"sync"
+	"time"
+
+	logger "github.com/rcommerz/logger-go"
+)
+
+// Keys decoded into Entry rather than Entry.Fields
+const (
+	timeKey    = "@timestamp"
+	levelKey   = "log.level"
+	messageKey = "message"
+	typeKey    = "log_type"
+	traceKey   = "trace_id"
+	spanKey    = "span_id"
+)
+
+// timeLayout is the ISO8601 layout of @timestamp
+const timeLayout = "2006-01-02T15:04:05.000Z0700"
+
+// Entry is a recorded log entry
+type Entry struct {
+	Time    time.Time
+	Level   logger.LogLevel
+	Type    logger.LogType
+	Message string
+	TraceID string
+	SpanID  string
+	// Fields holds every other key, including service.name, host.name and
+	// caller. Integers are decoded as int64 and other numbers as float64.
+	Fields map[string]interface{}
+}
+
+// Recorder is a Logger that keeps the entries it writes
+type Recorder struct {
+	*logger.Logger
+
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewRecorder returns a recorder logging every level, independently of
+// the singleton
+func NewRecorder() *Recorder {
+	return NewRecorderWithConfig(logger.Config{ServiceName: "logtest", Level: logger.LevelDEBUG})
+}
+
+// NewRecorderWithConfig returns a recorder built from config, e.g. to test
+// redaction or per-type levels. Output and Encoding are replaced, and
+// SecurityFormat must not be set, as entries are decoded from JSON.
+func NewRecorderWithConfig(config logger.Config) *Recorder {
+	r := &Recorder{}
+	config.Output = (*recordWriter)(r)
+	config.Encoding = logger.EncodingJSON
+	r.Logger = logger.New(config)
+	return r
+}
+
+// Entries returns the entries recorded so far, oldest first
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+// Reset discards the entries recorded so far
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+// recordWriter decodes encoded entries into the recorder
+type recordWriter Recorder
+
+func (w *recordWriter) Write(p []byte) (int, error) {
+	var decoded []Entry
+	for _, line := range bytes.Split(bytes.TrimSpace(p), []byte("\n")) {
+		entry, err := decodeEntry(line)
+		if err != nil {
+			return 0, err
+		}
+		decoded = append(decoded, entry)
+	}
+
+	w.mu.Lock()
+	w.entries = append(w.entries, decoded...)
+	w.mu.Unlock()
+	return len(p), nil
+}
+
+// decodeEntry decodes one JSON-encoded entry
+func decodeEntry(line []byte) (Entry, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return Entry{}, err
+	}
+
+	entry := Entry{
+		Level:   logger.LogLevel(take(fields, levelKey)),
+		Type:    logger.LogType(take(fields, typeKey)),
+		Message: take(fields, messageKey),
+		TraceID: take(fields, traceKey),
+		SpanID:  take(fields, spanKey),
+	}
+	entry.Time = entryTime(fields)
+	for key, value := range fields {
+		fields[key] = numbers(value)
+	}
+	entry.Fields = fields
+	return entry, nil
+}
+
+// entryTime removes @timestamp from fields, parsing either format of
+// Config.TimeFormat
+func entryTime(fields map[string]interface{}) time.Time {
+	value := fields[timeKey]
+	delete(fields, timeKey)
+
+	switch v := value.(type) {
+	case string:
+		t, _ := time.Parse(timeLayout, v)
+		return t
+	case json.Number:
+		millis, _ := v.Float64()
+		return time.UnixMicro(int64(math.Round(millis * 1000)))
+	}
+	return time.Time{}
+}
+
+// take removes key from fields, returning its string value
+func take(fields map[string]interface{}, key string) string {
+	value, _ := fields[key].(string)
+	delete(fields, key)
+	return value
+}
+
+// numbers converts decoded json.Numbers, including nested ones, to int64
+// or float64
+func numbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, nested := range v {
+			v[key] = numbers(nested)
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = numbers(nested)
+		}
+	}
+	return value
+}
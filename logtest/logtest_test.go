@@ -0,0 +1,81 @@
+package logtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	logger "github.com/rcommerz/logger-go"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("should record decoded entries", func(t *testing.T) {
+		rec := NewRecorder()
+		spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{1},
+			SpanID:     trace.SpanID{2},
+			TraceFlags: trace.FlagsSampled,
+		})
+		ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+
+		rec.Info(ctx, "Order created", logger.LogContext{"order_id": "123", "items": 3, "total": 9.5})
+		rec.Error(ctx, "Payment failed", logger.LogContext{"error": errors.New("declined")})
+
+		entries := rec.Entries()
+		if len(entries) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(entries))
+		}
+		entry := entries[0]
+		if entry.Level != logger.LevelINFO || entry.Type != logger.TypeNormal || entry.Message != "Order created" {
+			t.Errorf("Unexpected entry: %+v", entry)
+		}
+		if entry.TraceID != spanContext.TraceID().String() || entry.SpanID != spanContext.SpanID().String() {
+			t.Errorf("Expected trace ids, got %s %s", entry.TraceID, entry.SpanID)
+		}
+		if entry.Fields["order_id"] != "123" || entry.Fields["items"] != int64(3) || entry.Fields["total"] != 9.5 {
+			t.Errorf("Unexpected fields: %v", entry.Fields)
+		}
+		if entry.Fields["service.name"] != "logtest" || entry.Fields["message"] != nil {
+			t.Errorf("Expected constant fields only, got %v", entry.Fields)
+		}
+		if time.Since(entry.Time) > time.Minute {
+			t.Errorf("Expected entry time to be decoded, got %s", entry.Time)
+		}
+		if entries[1].Level != logger.LevelERROR || entries[1].Fields["error_message"] != "declined" {
+			t.Errorf("Unexpected error entry: %+v", entries[1])
+		}
+
+		rec.Reset()
+		if len(rec.Entries()) != 0 {
+			t.Error("Expected no entries after Reset")
+		}
+	})
+
+	t.Run("should apply the given config", func(t *testing.T) {
+		rec := NewRecorderWithConfig(logger.Config{
+			ServiceName: "orders",
+			Level:       logger.LevelWARN,
+			Redactor:    logger.NewRedactor([]string{"password"}),
+		})
+
+		rec.Info(context.Background(), "ignored", nil)
+		rec.Warn(context.Background(), "login", logger.LogContext{"password": "hunter2"})
+
+		entries := rec.Entries()
+		if len(entries) != 1 || entries[0].Fields["password"] != logger.RedactedValue {
+			t.Errorf("Expected one redacted entry, got %+v", entries)
+		}
+	})
+
+	t.Run("should decode epoch millis timestamps", func(t *testing.T) {
+		rec := NewRecorderWithConfig(logger.Config{ServiceName: "orders", TimeFormat: logger.TimeFormatEpochMillis})
+		rec.Info(context.Background(), "hello", nil)
+
+		entries := rec.Entries()
+		if len(entries) != 1 || time.Since(entries[0].Time) > time.Minute {
+			t.Errorf("Expected entry time to be decoded, got %+v", entries)
+		}
+	})
+}
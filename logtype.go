@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// registeredLogTypes holds the default minimum levels of user-defined log
+// types. The map is replaced rather than changed on registration, so
+// loggers read it without locking.
+var registeredLogTypes struct {
+	mu     sync.Mutex
+	levels atomic.Pointer[map[LogType]LogLevel]
+}
+
+// RegisterLogType defines a domain log type (e.g. "payment") for use with
+// Log. Entries of the type are logged from defaultLevel up, unless
+// Config.TypeLevels overrides it; an empty defaultLevel uses Config.Level.
+// Registering a name again replaces its default level.
+func RegisterLogType(name string, defaultLevel LogLevel) LogType {
+	logType := LogType(name)
+
+	registeredLogTypes.mu.Lock()
+	defer registeredLogTypes.mu.Unlock()
+	levels := map[LogType]LogLevel{logType: defaultLevel}
+	if current := registeredLogTypes.levels.Load(); current != nil {
+		for registered, level := range *current {
+			if registered != logType {
+				levels[registered] = level
+			}
+		}
+	}
+	registeredLogTypes.levels.Store(&levels)
+	return logType
+}
+
+// typeLevels resolves the minimum level of each log type from
+// Config.TypeLevels and the registered types. The resolved levels are
+// shared by a logger and its children, and resolved again after a type is
+// registered.
+type typeLevels struct {
+	config   map[LogType]LogLevel
+	resolved atomic.Pointer[resolvedTypeLevels]
+}
+
+// resolvedTypeLevels are the levels resolved from one version of the
+// registered types
+type resolvedTypeLevels struct {
+	registered *map[LogType]LogLevel
+	levels     map[LogType]LogLevel
+}
+
+func newTypeLevels(config map[LogType]LogLevel) *typeLevels {
+	t := &typeLevels{config: make(map[LogType]LogLevel, len(config))}
+	for logType, level := range config {
+		t.config[logType] = level
+	}
+	return t
+}
+
+// level returns the minimum level configured or registered for logType,
+// if any
+func (t *typeLevels) level(logType LogType) (LogLevel, bool) {
+	registered := registeredLogTypes.levels.Load()
+	resolved := t.resolved.Load()
+	if resolved == nil || resolved.registered != registered {
+		resolved = t.resolve(registered)
+	}
+	level, ok := resolved.levels[logType]
+	return level, ok
+}
+
+// resolve merges the registered default levels with the configured ones,
+// which take precedence
+func (t *typeLevels) resolve(registered *map[LogType]LogLevel) *resolvedTypeLevels {
+	resolved := &resolvedTypeLevels{registered: registered, levels: make(map[LogType]LogLevel, len(t.config))}
+	if registered != nil {
+		for logType, level := range *registered {
+			if level != "" {
+				resolved.levels[logType] = level
+			}
+		}
+	}
+	for logType, level := range t.config {
+		resolved.levels[logType] = level
+	}
+	t.resolved.Store(resolved)
+	return resolved
+}
+
+// typeLevel returns the minimum level configured or registered for
+// logType, if any. Loggers not built by New only use registered levels.
+func (l *Logger) typeLevel(logType LogType) (LogLevel, bool) {
+	if l.typeLevels == nil {
+		if registered := registeredLogTypes.levels.Load(); registered != nil {
+			level := (*registered)[logType]
+			return level, level != ""
+		}
+		return "", false
+	}
+	return l.typeLevels.level(logType)
+}
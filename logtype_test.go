@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRegisterLogType(t *testing.T) {
+	payment := RegisterLogType("payment", LevelDEBUG)
+	inventory := RegisterLogType("inventory", LevelERROR)
+	integration := RegisterLogType("integration", "")
+
+	logger, _ := setupObservedLogger(Config{
+		ServiceName:    "log-type-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelINFO,
+		TypeLevels:     map[LogType]LogLevel{inventory: LevelWARN},
+	})
+	observedCore, observedLogs := observer.New(zapcore.InfoLevel)
+	logger.zap = zap.New(observedCore)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		logType LogType
+		level   LogLevel
+		logged  bool
+	}{
+		{"default level below global level", payment, LevelDEBUG, true},
+		{"config overrides default level", inventory, LevelWARN, true},
+		{"below config level", inventory, LevelINFO, false},
+		{"no default uses global level", integration, LevelDEBUG, false},
+		{"no default at global level", integration, LevelINFO, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observedLogs.TakeAll()
+			logger.Log(ctx, tt.level, tt.logType, "domain event", nil)
+
+			logs := observedLogs.TakeAll()
+			if logged := len(logs) == 1; logged != tt.logged {
+				t.Fatalf("Expected logged=%v, got %d entries", tt.logged, len(logs))
+			}
+			if tt.logged && logs[0].ContextMap()["log_type"] != string(tt.logType) {
+				t.Errorf("Expected log_type=%s, got %v", tt.logType, logs[0].ContextMap()["log_type"])
+			}
+		})
+	}
+}
+
+func TestTypeLevelsAfterRegistration(t *testing.T) {
+	logger := New(Config{ServiceName: "log-type-test", Level: LevelINFO})
+	child := logger.With(LogContext{"component": "billing"})
+
+	refund := LogType("refund")
+	if _, ok := child.typeLevel(refund); ok {
+		t.Fatal("Expected no level for an unregistered type")
+	}
+
+	RegisterLogType("refund", LevelDEBUG)
+	if level, ok := child.typeLevel(refund); !ok || level != LevelDEBUG {
+		t.Errorf("Expected types registered after New to apply, got %s %v", level, ok)
+	}
+	if level, _ := NewNop().typeLevel(refund); level != LevelDEBUG {
+		t.Errorf("Expected loggers without resolved levels to use the registry, got %s", level)
+	}
+}
@@ -0,0 +1,169 @@
+// Package logwebsocket logs the lifecycle of Fiber WebSocket connections:
+// open, close, duration, close code, traffic and errors.
+package logwebsocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	logger "github.com/rcommerz/logger-go"
+)
+
+// Locals keys used to carry request data into the upgraded connection
+const (
+	localsPath        = "logwebsocket.path"
+	localsRoute       = "logwebsocket.route"
+	localsUserContext = "logwebsocket.user_context"
+)
+
+// Conn wraps a WebSocket connection and counts the traffic flowing through it.
+// Use its Read/Write methods so the close entry reports accurate totals.
+type Conn struct {
+	*websocket.Conn
+
+	bytesSent        atomic.Int64
+	bytesReceived    atomic.Int64
+	messagesSent     atomic.Int64
+	messagesReceived atomic.Int64
+	closeCode        atomic.Int64
+	lastErr          atomic.Value
+}
+
+// ReadMessage reads a message and records its size or the close code
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	messageType, data, err := c.Conn.ReadMessage()
+	if err != nil {
+		c.recordError(err)
+		return messageType, data, err
+	}
+
+	c.messagesReceived.Add(1)
+	c.bytesReceived.Add(int64(len(data)))
+	return messageType, data, nil
+}
+
+// WriteMessage writes a message and records its size
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	if err := c.Conn.WriteMessage(messageType, data); err != nil {
+		c.recordError(err)
+		return err
+	}
+
+	c.messagesSent.Add(1)
+	c.bytesSent.Add(int64(len(data)))
+	return nil
+}
+
+// ReadJSON reads the next message and decodes it into v
+func (c *Conn) ReadJSON(v interface{}) error {
+	_, data, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// WriteJSON encodes v and writes it as a text message
+func (c *Conn) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.WriteMessage(websocket.TextMessage, data)
+}
+
+func (c *Conn) recordError(err error) {
+	var closeErr *fastws.CloseError
+	if errors.As(err, &closeErr) {
+		c.closeCode.Store(int64(closeErr.Code))
+	}
+	c.lastErr.Store(err)
+}
+
+// New returns a Fiber handler that upgrades the connection to WebSocket and
+// logs when it opens and closes. It accepts the same config as websocket.New.
+func New(handler func(*Conn), config ...websocket.Config) fiber.Handler {
+	log := logger.GetInstance()
+
+	upgrade := websocket.New(func(ws *websocket.Conn) {
+		conn := &Conn{Conn: ws}
+
+		ctx, ok := ws.Locals(localsUserContext).(context.Context)
+		if !ok {
+			ctx = context.Background()
+		}
+
+		path, _ := ws.Locals(localsPath).(string)
+		context := logger.LogContext{
+			"path": path,
+			"ip":   ws.IP(),
+		}
+		if route, _ := ws.Locals(localsRoute).(string); route != "" {
+			context["http.route"] = route
+		}
+
+		log.HTTP(ctx, fmt.Sprintf("WebSocket %s opened", path), context)
+
+		startTime := time.Now()
+		defer func() {
+			context["duration_ms"] = time.Since(startTime).Milliseconds()
+			context["bytes_sent"] = conn.bytesSent.Load()
+			context["bytes_received"] = conn.bytesReceived.Load()
+			context["messages_sent"] = conn.messagesSent.Load()
+			context["messages_received"] = conn.messagesReceived.Load()
+			if code := conn.closeCode.Load(); code != 0 {
+				context["close_code"] = code
+			}
+
+			message := fmt.Sprintf("WebSocket %s closed", path)
+
+			if r := recover(); r != nil {
+				context["panic"] = fmt.Sprint(r)
+				log.Error(ctx, message, context)
+				// Let the websocket RecoverHandler deal with the panic
+				panic(r)
+			}
+
+			if err, ok := conn.lastErr.Load().(error); ok && isUnexpectedClose(err) {
+				context["error_message"] = err.Error()
+				log.Warn(ctx, message, context)
+				return
+			}
+
+			log.HTTP(ctx, message, context)
+		}()
+
+		handler(conn)
+	}, config...)
+
+	return func(c *fiber.Ctx) error {
+		// The connection outlives the handler, so copy strings Fiber reuses
+		c.Locals(localsPath, utils.CopyString(c.Path()))
+		c.Locals(localsRoute, utils.CopyString(c.Route().Path))
+		c.Locals(localsUserContext, c.UserContext())
+		return upgrade(c)
+	}
+}
+
+// isUnexpectedClose reports whether the connection ended with anything other
+// than a normal close handshake
+func isUnexpectedClose(err error) bool {
+	var closeErr *fastws.CloseError
+	if !errors.As(err, &closeErr) {
+		return true
+	}
+	switch closeErr.Code {
+	case websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,186 @@
+package logwebsocket
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	logger "github.com/rcommerz/logger-go"
+)
+
+var (
+	entriesMu sync.Mutex
+	entries   []logger.Entry
+)
+
+func TestMain(m *testing.M) {
+	log := logger.Initialize(logger.Config{
+		ServiceName:    "logwebsocket-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          logger.LevelDEBUG,
+	})
+	log.RegisterHook(func(entry logger.Entry) logger.Entry {
+		entriesMu.Lock()
+		entries = append(entries, entry)
+		entriesMu.Unlock()
+		return entry
+	})
+	os.Exit(m.Run())
+}
+
+// waitEntry returns the entry logged with message. The close entry is
+// logged after the handler returns, so it is waited for.
+func waitEntry(t *testing.T, message string) logger.Entry {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		entriesMu.Lock()
+		for _, entry := range entries {
+			if entry.Message == message {
+				entriesMu.Unlock()
+				return entry
+			}
+		}
+		entriesMu.Unlock()
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected an entry %q", message)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestNew(t *testing.T) {
+	done := make(chan *Conn, 1)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/ws/:room", New(func(c *Conn) {
+		defer func() { done <- c }()
+		for {
+			messageType, data, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := c.WriteMessage(messageType, data); err != nil {
+				return
+			}
+		}
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go func() { _ = app.Listener(ln) }()
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	client, _, err := fastws.DefaultDialer.Dial("ws://"+ln.Addr().String()+"/ws/lobby", nil)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+
+	if err := client.WriteMessage(websocket.TextMessage, []byte("hello")); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	if _, data, err := client.ReadMessage(); err != nil || string(data) != "hello" {
+		t.Fatalf("Expected echo, got %q (%v)", data, err)
+	}
+
+	closeMsg := fastws.FormatCloseMessage(websocket.CloseNormalClosure, "bye")
+	if err := client.WriteMessage(websocket.CloseMessage, closeMsg); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+
+	conn := <-done
+	_ = client.Close()
+
+	if got := conn.bytesReceived.Load(); got != 5 {
+		t.Errorf("Expected 5 bytes received, got %d", got)
+	}
+	if got := conn.bytesSent.Load(); got != 5 {
+		t.Errorf("Expected 5 bytes sent, got %d", got)
+	}
+	if got := conn.closeCode.Load(); got != websocket.CloseNormalClosure {
+		t.Errorf("Expected close code 1000, got %d", got)
+	}
+}
+
+func TestNewEntries(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/ws/:room", New(func(c *Conn) {
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go func() { _ = app.Listener(ln) }()
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	entriesMu.Lock()
+	entries = nil
+	entriesMu.Unlock()
+
+	client, _, err := fastws.DefaultDialer.Dial("ws://"+ln.Addr().String()+"/ws/support", nil)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	opened := waitEntry(t, "WebSocket /ws/support opened")
+
+	time.Sleep(20 * time.Millisecond)
+
+	closeMsg := fastws.FormatCloseMessage(websocket.CloseGoingAway, "bye")
+	if err := client.WriteMessage(websocket.CloseMessage, closeMsg); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+	closed := waitEntry(t, "WebSocket /ws/support closed")
+	_ = client.Close()
+
+	if opened.Fields["path"] != "/ws/support" || opened.Fields["http.route"] != "/ws/:room" {
+		t.Errorf("Expected path and route on the open entry, got %v", opened.Fields)
+	}
+	if closed.Level != logger.LevelINFO {
+		t.Errorf("Expected the close entry at INFO, got %s", closed.Level)
+	}
+	if closed.Fields["path"] != "/ws/support" || closed.Fields["http.route"] != "/ws/:room" {
+		t.Errorf("Expected path and route on the close entry, got %v", closed.Fields)
+	}
+	if closed.Fields["close_code"] != int64(websocket.CloseGoingAway) {
+		t.Errorf("Expected close code 1001, got %v", closed.Fields["close_code"])
+	}
+	if duration, ok := closed.Fields["duration_ms"].(int64); !ok || duration < 20 {
+		t.Errorf("Expected a duration of at least 20ms, got %v", closed.Fields["duration_ms"])
+	}
+}
+
+func TestIsUnexpectedClose(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"normal closure", &fastws.CloseError{Code: websocket.CloseNormalClosure}, false},
+		{"going away", &fastws.CloseError{Code: websocket.CloseGoingAway}, false},
+		{"abnormal closure", &fastws.CloseError{Code: websocket.CloseAbnormalClosure}, true},
+		{"network error", errors.New("connection reset"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUnexpectedClose(tt.err); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Masker replaces matches of Pattern in string values with a partially
+// masked form
+type Masker struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Mask    func(match string) string
+}
+
+// PANMasker masks payment card numbers that pass the Luhn check, keeping
+// the first 6 and last 4 digits (e.g. 411111******1111)
+var PANMasker = Masker{
+	Name:    "pan",
+	Pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+	Mask: func(match string) string {
+		digits := stripSeparators(match)
+		if !luhnValid(digits) {
+			return match
+		}
+		return digits[:6] + strings.Repeat("*", len(digits)-10) + digits[len(digits)-4:]
+	},
+}
+
+// EmailMasker masks the local part of email addresses, keeping its first
+// character (e.g. j***@example.com)
+var EmailMasker = Masker{
+	Name:    "email",
+	Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	Mask: func(match string) string {
+		at := strings.LastIndex(match, "@")
+		return match[:1] + "***" + match[at:]
+	},
+}
+
+// PhoneMasker masks international phone numbers, keeping the last 4 digits
+// (e.g. +*********4567)
+var PhoneMasker = Masker{
+	Name:    "phone",
+	Pattern: regexp.MustCompile(`\+\d(?:[ -]?\d){6,14}\b`),
+	Mask: func(match string) string {
+		digits := stripSeparators(match[1:])
+		return "+" + strings.Repeat("*", len(digits)-4) + digits[len(digits)-4:]
+	},
+}
+
+// IBANMasker masks IBANs, keeping the country code, check digits and last
+// 4 characters (e.g. DE89**************3000)
+var IBANMasker = Masker{
+	Name:    "iban",
+	Pattern: regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?\b`),
+	Mask: func(match string) string {
+		iban := stripSeparators(match)
+		return iban[:4] + strings.Repeat("*", len(iban)-8) + iban[len(iban)-4:]
+	},
+}
+
+// DefaultMaskers returns the built-in PII maskers
+func DefaultMaskers() []Masker {
+	return []Masker{IBANMasker, PANMasker, PhoneMasker, EmailMasker}
+}
+
+// WithMaskers returns a copy of the Redactor that also applies maskers to
+// string values. A nil Redactor yields one that only masks.
+func (r *Redactor) WithMaskers(maskers ...Masker) *Redactor {
+	masked := &Redactor{}
+	if r != nil {
+		masked.keys = r.keys
+		masked.values = append(masked.values, r.values...)
+	}
+	for _, masker := range maskers {
+		masked.values = append(masked.values, valueRule{pattern: masker.Pattern, replace: masker.Mask})
+	}
+	return masked
+}
+
+// stripSeparators removes spaces and dashes
+func stripSeparators(s string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(s)
+}
+
+// luhnValid reports whether a digit string passes the Luhn checksum
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
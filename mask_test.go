@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMaskers(t *testing.T) {
+	redactor := (*Redactor)(nil).WithMaskers(DefaultMaskers()...)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"card number", "card 4111111111111111 declined", "card 411111******1111 declined"},
+		{"card number with spaces", "4111 1111 1111 1111", "411111******1111"},
+		{"non-Luhn number", "order 1234567890123", "order 1234567890123"},
+		{"email", "sent to jane.doe@example.com", "sent to j***@example.com"},
+		{"phone", "call +1 415-555-2671", "call +*******2671"},
+		{"iban", "IBAN DE89 3704 0044 0532 0130 00", "IBAN DE89**************3000"},
+		{"plain text", "nothing to mask here", "nothing to mask here"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactor.RedactString(tt.input); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMaskPIIConfig(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "mask-test",
+		ServiceVersion: "1.0.0",
+		Env:            "production",
+		Level:          LevelDEBUG,
+		Redactor:       DefaultRedactor(),
+		MaskPII:        true,
+	})
+
+	logger.Info(context.Background(), "payment from jane@example.com", LogContext{
+		"card":     "5555555555554444",
+		"password": "x",
+	})
+
+	entry := observedLogs.TakeAll()[0]
+	fields := entry.ContextMap()
+	if entry.Message != "payment from j***@example.com" {
+		t.Errorf("Expected email in message to be masked, got %q", entry.Message)
+	}
+	if fields["card"] != "555555******4444" {
+		t.Errorf("Expected card to be masked, got %v", fields["card"])
+	}
+	if fields["password"] != RedactedValue {
+		t.Errorf("Expected configured Redactor to still apply, got %v", fields["password"])
+	}
+}
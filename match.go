@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+)
+
+// compileGlob converts a path glob into an anchored regular expression.
+// "*" matches within a path segment, "**" matches across segments and "?"
+// matches a single non-separator character.
+func compileGlob(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// compileKeyGlob converts a field key glob into an anchored,
+// case-insensitive regular expression where "*" matches any characters
+func compileKeyGlob(glob string) *regexp.Regexp {
+	parts := strings.Split(glob, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("(?i)^" + strings.Join(parts, ".*") + "$")
+}
+
+// isGlob reports whether a path contains glob metacharacters
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?")
+}
+
+// requestMatcher decides whether a request is excluded from logging by
+// exact path, path glob, regular expression or HTTP method
+type requestMatcher struct {
+	exact    map[string]struct{}
+	globs    []*regexp.Regexp
+	patterns []*regexp.Regexp
+	methods  map[string]struct{}
+}
+
+// newRequestMatcher compiles the exclusion rules once at middleware setup
+func newRequestMatcher(paths []string, patterns []*regexp.Regexp, methods []string) *requestMatcher {
+	m := &requestMatcher{
+		exact:    make(map[string]struct{}),
+		patterns: patterns,
+		methods:  make(map[string]struct{}, len(methods)),
+	}
+	for _, path := range paths {
+		if isGlob(path) {
+			m.globs = append(m.globs, compileGlob(path))
+		} else {
+			m.exact[path] = struct{}{}
+		}
+	}
+	for _, method := range methods {
+		m.methods[strings.ToUpper(method)] = struct{}{}
+	}
+	return m
+}
+
+// matches reports whether the request should be excluded
+func (m *requestMatcher) matches(method, path string) bool {
+	if _, ok := m.methods[method]; ok {
+		return true
+	}
+	if _, ok := m.exact[path]; ok {
+		return true
+	}
+	for _, glob := range m.globs {
+		if glob.MatchString(path) {
+			return true
+		}
+	}
+	for _, pattern := range m.patterns {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
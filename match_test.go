@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestCompileGlob(t *testing.T) {
+	tests := []struct {
+		glob     string
+		path     string
+		expected bool
+	}{
+		{"/health/*", "/health/live", true},
+		{"/health/*", "/health/live/extra", false},
+		{"/static/**", "/static/css/app.css", true},
+		{"/api/*/status", "/api/orders/status", true},
+		{"/v?/ping", "/v1/ping", true},
+		{"/v?/ping", "/v10/ping", false},
+		{"/a.b/*", "/aXb/c", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.path, func(t *testing.T) {
+			if got := compileGlob(tt.glob).MatchString(tt.path); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRequestMatcher(t *testing.T) {
+	m := newRequestMatcher(
+		[]string{"/health", "/static/**"},
+		[]*regexp.Regexp{regexp.MustCompile(`^/internal/v\d+/`)},
+		[]string{"options"},
+	)
+
+	tests := []struct {
+		method   string
+		path     string
+		expected bool
+	}{
+		{"GET", "/health", true},
+		{"GET", "/healthz", false},
+		{"GET", "/static/js/app.js", true},
+		{"POST", "/internal/v2/sync", true},
+		{"OPTIONS", "/api/orders", true},
+		{"GET", "/api/orders", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			if got := m.matches(tt.method, tt.path); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// EntryMetrics receives counts of the entries the logger writes, so error
+// rates can be alerted on without a log query. See the logprometheus
+// package for a Prometheus implementation.
+type EntryMetrics interface {
+	// EntryLogged is called for every entry written
+	EntryLogged(level LogLevel, logType LogType)
+	// EntryDropped is called when an entry is not written, with the reason
+	EntryDropped(reason string)
+	// SinkError is called when the output fails to write an entry
+	SinkError()
+}
+
+// metricsCore reports entries written by the wrapped core to EntryMetrics
+type metricsCore struct {
+	zapcore.Core
+	metrics EntryMetrics
+}
+
+func (c *metricsCore) With(fields []zapcore.Field) zapcore.Core {
+	return &metricsCore{Core: c.Core.With(fields), metrics: c.metrics}
+}
+
+func (c *metricsCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *metricsCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if err := c.Core.Write(entry, fields); err != nil {
+		c.metrics.SinkError()
+		c.metrics.EntryDropped("sink_error")
+		return err
+	}
+
+	logType := TypeNormal
+	for _, field := range fields {
+		if field.Key == "log_type" && field.Type == zapcore.StringType {
+			logType = LogType(field.String)
+			break
+		}
+	}
+	c.metrics.EntryLogged(logLevel(entry.Level), logType)
+	return nil
+}
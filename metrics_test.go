@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type recordedMetrics struct {
+	logged     map[string]int
+	dropped    map[string]int
+	sinkErrors int
+}
+
+func newRecordedMetrics() *recordedMetrics {
+	return &recordedMetrics{logged: map[string]int{}, dropped: map[string]int{}}
+}
+
+func (m *recordedMetrics) EntryLogged(level LogLevel, logType LogType) {
+	m.logged[string(level)+"/"+string(logType)]++
+}
+
+func (m *recordedMetrics) EntryDropped(reason string) {
+	m.dropped[reason]++
+}
+
+func (m *recordedMetrics) SinkError() {
+	m.sinkErrors++
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func (failingWriter) Sync() error {
+	return nil
+}
+
+func TestMetricsCore(t *testing.T) {
+	t.Run("should count written entries by level and type", func(t *testing.T) {
+		metrics := newRecordedMetrics()
+		observedCore, _ := observer.New(zapcore.InfoLevel)
+		l := &Logger{zap: zap.New(&metricsCore{Core: observedCore, metrics: metrics})}
+
+		ctx := context.Background()
+		l.Info(ctx, "one", nil)
+		l.Error(ctx, "two", nil)
+		l.Error(ctx, "three", nil)
+		l.Log(ctx, LevelWARN, TypeRPC, "four", nil)
+		l.Debug(ctx, "filtered", nil)
+
+		expected := map[string]int{"INFO/normal": 1, "ERROR/error": 2, "WARN/rpc": 1}
+		if len(metrics.logged) != len(expected) {
+			t.Errorf("Expected %v, got %v", expected, metrics.logged)
+		}
+		for key, count := range expected {
+			if metrics.logged[key] != count {
+				t.Errorf("Expected %d %s entries, got %d", count, key, metrics.logged[key])
+			}
+		}
+	})
+
+	t.Run("should count sink errors as dropped entries", func(t *testing.T) {
+		metrics := newRecordedMetrics()
+		core := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), failingWriter{}, zapcore.InfoLevel)
+		l := &Logger{zap: zap.New(&metricsCore{Core: core, metrics: metrics}, zap.ErrorOutput(zapcore.AddSync(failingWriter{})))}
+
+		l.Info(context.Background(), "lost", nil)
+
+		if metrics.sinkErrors != 1 || metrics.dropped["sink_error"] != 1 {
+			t.Errorf("Expected 1 sink error and dropped entry, got %d and %v", metrics.sinkErrors, metrics.dropped)
+		}
+		if len(metrics.logged) != 0 {
+			t.Errorf("Expected no logged entries, got %v", metrics.logged)
+		}
+	})
+}
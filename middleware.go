@@ -1,17 +1,201 @@
 package logger
 
 import (
+	"crypto/subtle"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.uber.org/zap/zapcore"
 )
 
+// LocalsLogger is the c.Locals key under which FiberMiddleware stores the
+// request-scoped logger
+const LocalsLogger = "logger"
+
 // MiddlewareOptions configures the HTTP logging middleware
 type MiddlewareOptions struct {
-	ExcludePaths   []string
+	// Logger, when set, logs requests instead of the singleton (e.g. a
+	// logtest recorder's logger)
+	Logger *Logger
+
+	// ExcludePaths lists paths that are not logged. Entries may be globs:
+	// "*" matches within a segment and "**" across segments (e.g. /static/**).
+	ExcludePaths []string
+	// ExcludePatterns excludes paths matching any of these regular expressions
+	ExcludePatterns []*regexp.Regexp
+	// ExcludeMethods excludes requests by HTTP method (e.g. OPTIONS, HEAD)
+	ExcludeMethods []string
+	// DebugToken enables a per-request debug override: requests carrying this
+	// secret in DebugHeader are logged at DEBUG with request and response
+	// bodies captured. Empty disables the override.
+	DebugToken string
+	// DebugHeader carries the debug token (default "X-Debug-Token")
+	DebugHeader string
+	// AggregateEntries buffers entries logged through the request-scoped
+	// logger (FromFiber) and emits them nested under "entries" in the
+	// request's log entry instead of as separate lines. The request entry is
+	// raised to WARN or ERROR if any buffered entry was. Audit and security
+	// entries are still written on their own.
+	AggregateEntries bool
+	// SamplePaths logs only 1 of every N successful requests for these paths
+	// or globs (e.g. {"/health": 100}) instead of excluding them entirely.
+	// Failed requests are always logged. Takes precedence over exclusions,
+	// but not over Skip.
+	SamplePaths map[string]int
+	// RequestIDHeader is read for an incoming request ID and echoed on the
+	// response; a UUID is generated when absent. Defaults to X-Request-ID.
+	RequestIDHeader string
+	// Skip, when set, is called for every request and excludes it from
+	// logging when it returns true (e.g. internal IPs, CORS preflights),
+	// even for SamplePaths
+	Skip func(c *fiber.Ctx) bool
+
 	IncludeHeaders bool
 	IncludeBody    bool
+
+	// LogRequestStart also logs a "METHOD path started" entry with
+	// request_phase=started when a request arrives, so long-running and
+	// hung requests are visible before they complete. The completion entry
+	// then carries request_phase=completed. Sampled paths and access-log-only
+	// setups get no start entry.
+	LogRequestStart bool
+	// ProgressInterval, when set, logs a "METHOD path in progress" entry
+	// with elapsed_ms and request_phase=in_progress every interval while
+	// the handler runs, e.g. for long polls. Streamed responses such as
+	// server-sent events are sent after the handler returns; wrap their
+	// stream writer with TrackStream to also log progress with
+	// response_bytes and a final summary.
+	ProgressInterval time.Duration
+
+	// MessageTemplate formats the entry message with text/template over an
+	// HTTPExchange, e.g. "{{.Request.Method}} {{.Request.Path}}
+	// {{.Response.StatusCode}} in {{.Response.Duration}}" (default "METHOD
+	// path status")
+	MessageTemplate string
+	// MessageFormatter, when set, formats the entry message instead of
+	// MessageTemplate
+	MessageFormatter HTTPMessageFunc
+
+	// CaptureHeaders logs these request headers individually as
+	// header.<name> fields (e.g. CF-Ray → header.cf_ray), keeping entries
+	// small without IncludeHeaders
+	CaptureHeaders []string
+
+	// MaxBodyBytes caps the captured request body; longer bodies are
+	// truncated with a marker. Defaults to 4096.
+	MaxBodyBytes int
+	// BodyContentTypes lists the media types (or "type/" prefixes) whose
+	// bodies are captured. Defaults to JSON, XML, form and text payloads.
+	BodyContentTypes []string
+	// ResponseBodyMinStatus captures the response body when the status code is
+	// at least this value (e.g. 500, or 400 to include client errors). Zero disables it.
+	ResponseBodyMinStatus int
+
+	// IncludeResponseHeaders adds response headers as response_headers
+	IncludeResponseHeaders bool
+	// ResponseHeaders restricts captured response headers to these names
+	// (e.g. Content-Type, Cache-Control, X-RateLimit-Remaining). All when empty.
+	ResponseHeaders []string
+
+	// RouteLevels overrides the level of successful (< 400) request entries
+	// per path glob, e.g. {"/api/webhooks/*", LevelDEBUG}. 4xx and 5xx
+	// responses keep their WARN and ERROR levels.
+	RouteLevels []RouteLevel
+
+	// SlowRequestThreshold logs requests that take longer than this at WARN
+	// with slow_request=true, even when they succeed. Zero disables it.
+	SlowRequestThreshold time.Duration
+
+	// SemanticConventions emits OpenTelemetry HTTP field names
+	// (http.request.method, url.path, http.response.status_code,
+	// client.address, user_agent.original, ...) instead of the defaults
+	SemanticConventions bool
+
+	// Enricher, when set, is called after the request is handled and its
+	// fields are merged into the entry (e.g. store_id, cart_id, AB-test bucket)
+	Enricher func(c *fiber.Ctx) LogContext
+
+	// Tenant extracts a tenant identifier and attaches it as tenant_id to the
+	// request entry and to c.UserContext(), so handler logs carry it too
+	Tenant *TenantSource
+
+	// SessionCookie logs the value of this cookie as session_id
+	SessionCookie string
+	// SessionLocalsKey logs this c.Locals value as session_id; it takes
+	// precedence over SessionCookie
+	SessionLocalsKey string
+	// APIKeyHeader logs a non-reversible api_key_fingerprint (first 8 hex
+	// characters of the SHA-256) of the key presented in this header
+	APIKeyHeader string
+
+	// RedactHeaders adds header names to the default redaction list
+	// (Authorization, Cookie, Set-Cookie, API-key headers, ...)
+	RedactHeaders []string
+	// HashRedactedHeaders replaces redacted header values with a short SHA-256
+	// digest instead of "[REDACTED]", keeping them correlatable
+	HashRedactedHeaders bool
+	// RedactQueryParams adds query parameter names to the default redaction
+	// list (token, api_key, password, code, ...)
+	RedactQueryParams []string
+
+	// AccessLog, when set, also writes every logged request as a combined
+	// log format line, before sampling. With Only set, entries buffered by
+	// AggregateEntries are dropped.
+	AccessLog *AccessLogOptions
+
+	// ParseUserAgent adds the browser, OS and device class parsed from the
+	// User-Agent header as user_agent.name, user_agent.version,
+	// user_agent.os, user_agent.os_version and user_agent.device (bot,
+	// mobile or desktop)
+	ParseUserAgent bool
+
+	// TrustedProxies lists the IPs and CIDRs of load balancers and proxies
+	// whose X-Forwarded-For and X-Real-IP headers are believed, so ip is
+	// the real client rather than the proxy (default: none)
+	TrustedProxies []string
+	// LogForwardingChain adds the X-Forwarded-For addresses followed by the
+	// connection's peer as forwarded_for
+	LogForwardingChain bool
+
+	// IncludeTLS adds http.version and, for TLS connections, tls.version,
+	// tls.cipher and, with client certificates (mTLS), tls.client.subject
+	// and tls.client.fingerprint (default: false)
+	IncludeTLS bool
+}
+
+// RouteLevel overrides the level of successful request entries for paths
+// matching Pattern (a path glob such as /api/webhooks/*)
+type RouteLevel struct {
+	Pattern string
+	Level   LogLevel
+}
+
+// routeLevelMatcher resolves per-route level overrides; the first match wins
+type routeLevelMatcher struct {
+	patterns []*regexp.Regexp
+	levels   []LogLevel
+}
+
+func newRouteLevelMatcher(routes []RouteLevel) *routeLevelMatcher {
+	m := &routeLevelMatcher{}
+	for _, route := range routes {
+		m.patterns = append(m.patterns, compileGlob(route.Pattern))
+		m.levels = append(m.levels, route.Level)
+	}
+	return m
+}
+
+func (m *routeLevelMatcher) level(path string) (LogLevel, bool) {
+	for i, pattern := range m.patterns {
+		if pattern.MatchString(path) {
+			return m.levels[i], true
+		}
+	}
+	return "", false
 }
 
 // FiberMiddleware returns a Fiber middleware that logs HTTP requests
@@ -20,47 +204,220 @@ func FiberMiddleware(opts *MiddlewareOptions) fiber.Handler {
 		opts = &MiddlewareOptions{}
 	}
 
-	logger := GetInstance()
+	baseLogger := opts.Logger
+	if baseLogger == nil {
+		baseLogger = GetInstance()
+	}
+
+	debugHeader := opts.DebugHeader
+	if debugHeader == "" {
+		debugHeader = "X-Debug-Token"
+	}
+	redactHeaders := opts.RedactHeaders
+	if opts.DebugToken != "" {
+		redactHeaders = append([]string{debugHeader}, redactHeaders...)
+	}
+	redactor := newHeaderRedactor(redactHeaders, opts.HashRedactedHeaders)
+
+	requestIDHeader := opts.RequestIDHeader
+	if requestIDHeader == "" {
+		requestIDHeader = fiber.HeaderXRequestID
+	}
+	redactedParams := nameSet(defaultRedactedQueryParams, opts.RedactQueryParams)
+	excluded := newRequestMatcher(opts.ExcludePaths, opts.ExcludePatterns, opts.ExcludeMethods)
+	routeLevels := newRouteLevelMatcher(opts.RouteLevels)
+	sampler := newPathSampler(opts.SamplePaths)
+	accessLog := newAccessLog(opts.AccessLog, baseLogger)
+	proxies := newProxyResolver(opts.TrustedProxies, opts.LogForwardingChain)
+	captured := newHeaderCapture(opts.CaptureHeaders, opts.SemanticConventions)
+	formatMessage := newHTTPMessageFunc(opts.MessageTemplate, opts.MessageFormatter)
 
 	return func(c *fiber.Ctx) error {
-		// Skip excluded paths
+		// Skip requests the predicate excludes, then excluded paths and
+		// methods unless they are sampled
+		if opts.Skip != nil && opts.Skip(c) {
+			return c.Next()
+		}
 		path := c.Path()
-		for _, excludePath := range opts.ExcludePaths {
-			if path == excludePath {
-				return c.Next()
+		sampleRule := sampler.rule(path)
+		if sampleRule == nil && excluded.matches(c.Method(), path) {
+			return c.Next()
+		}
+
+		startTime := baseLogger.now()
+		middlewareRoute := c.Route()
+
+		// Elevate this request to DEBUG when it carries the debug token
+		logger := baseLogger
+		debug := opts.DebugToken != "" &&
+			subtle.ConstantTimeCompare([]byte(c.Get(debugHeader)), []byte(opts.DebugToken)) == 1
+		if debug {
+			logger = baseLogger.withDebug()
+		}
+
+		// Build request-scoped fields shared by the context and the request logger
+		requestID := c.Get(requestIDHeader)
+		if requestID == "" {
+			requestID = utils.UUIDv4()
+		}
+		c.Set(requestIDHeader, requestID)
+		scoped := LogContext{"request_id": requestID}
+
+		var tenantID string
+		if opts.Tenant != nil {
+			if tenantID = opts.Tenant.extract(c); tenantID != "" {
+				scoped["tenant_id"] = tenantID
 			}
 		}
 
-		startTime := time.Now()
+		c.SetUserContext(WithContextFields(c.UserContext(), scoped))
+		requestLogger := logger.With(scoped)
+		var buffer *entryBuffer
+		if opts.AggregateEntries {
+			buffer = &entryBuffer{omit: scoped}
+			requestLogger = requestLogger.withBuffer(buffer)
+		}
+		c.Locals(LocalsLogger, requestLogger)
+
+		// Announce the request before it is handled
+		logStart := opts.LogRequestStart && sampleRule == nil && (opts.AccessLog == nil || !opts.AccessLog.Only)
+		if logStart {
+			logger.HTTP(c.UserContext(), c.Method()+" "+path+" started", LogContext{
+				"method":        c.Method(),
+				"path":          path,
+				"request_phase": "started",
+			})
+		}
+
+		// Report on long-running handlers while they run
+		var progress *progressTracker
+		if opts.ProgressInterval > 0 {
+			c.Locals(localsProgress, progressOptions{logger: logger, start: startTime, interval: opts.ProgressInterval})
+			progress = startProgress(logger, c.UserContext(), c.Method(), path, startTime, opts.ProgressInterval, false)
+		}
 
 		// Process request
 		err := c.Next()
+		if progress != nil {
+			progress.stop()
+		}
 
 		// Calculate duration
-		duration := time.Since(startTime)
+		duration := baseLogger.since(startTime)
+
+		// Collect entries buffered by the request-scoped logger
+		var entries []map[string]interface{}
+		var maxLevel zapcore.Level
+		if buffer != nil {
+			entries, maxLevel = buffer.drain()
+		}
+
+		// Resolve the client behind trusted proxies
+		clientIP := c.IP()
+		var forwardedFor []string
+		if proxies != nil {
+			clientIP, forwardedFor = proxies.resolve(c.Context().RemoteIP().String(), c.Get(headerForwardedFor), c.Get(headerRealIP))
+		}
+
+		if accessLog != nil {
+			accessLog.write(fiberAccessRecord(c, clientIP, startTime, redactedParams))
+			if opts.AccessLog.Only {
+				return err
+			}
+		}
+
+		// Keep 1 in N successful requests on sampled paths
+		if sampleRule != nil && !debug && len(entries) == 0 && c.Response().StatusCode() < 400 && !sampleRule.keep() {
+			return err
+		}
 
 		// Build log context
-		context := LogContext{
-			"method":      c.Method(),
-			"path":        path,
-			"status_code": c.Response().StatusCode(),
-			"duration_ms": duration.Milliseconds(),
-			"ip":          c.IP(),
-			"user_agent":  c.Get("User-Agent"),
+		req := HTTPRequestInfo{
+			Method:    c.Method(),
+			Path:      path,
+			Route:     MatchedRoute(c, middlewareRoute),
+			IP:        clientIP,
+			UserAgent: c.Get("User-Agent"),
+			Bytes:     int64(requestBytes(c)),
+		}
+		resp := HTTPResponseInfo{
+			StatusCode: c.Response().StatusCode(),
+			Duration:   duration,
+			Bytes:      int64(responseBytes(c)),
 		}
 
 		// Add query params if present
-		if len(c.Context().QueryArgs().String()) > 0 {
-			context["query"] = c.Context().QueryArgs().String()
+		if query := c.Context().QueryArgs().String(); len(query) > 0 {
+			req.Query = redactQuery(query, redactedParams)
 		}
 
 		// Add headers if requested
 		if opts.IncludeHeaders {
-			headers := make(map[string]string)
+			req.Headers = make(map[string]string)
 			c.Request().Header.VisitAll(func(key, value []byte) {
-				headers[string(key)] = string(value)
+				req.Headers[string(key)] = redactor.redact(string(key), string(value))
 			})
-			context["headers"] = headers
+		}
+
+		// Add response headers if requested
+		if opts.IncludeResponseHeaders {
+			resp.Headers = responseHeaders(c, opts.ResponseHeaders, redactor)
+		}
+
+		context := HTTPFields(req, resp)
+		if len(forwardedFor) > 0 {
+			context["forwarded_for"] = forwardedFor
+		}
+		if logStart {
+			context["request_phase"] = "completed"
+		}
+		captureHeaders(context, captured, func(name string) string { return c.Get(name) }, redactor)
+		if opts.ParseUserAgent {
+			addUserAgentFields(context, req.UserAgent)
+		}
+		if opts.IncludeTLS {
+			version := strings.TrimPrefix(string(c.Request().Header.Protocol()), "HTTP/")
+			addTLSFields(context, version, c.Context().TLSConnectionState())
+		}
+
+		// Add request body if requested; streamed bodies are not read into
+		// memory
+		if (opts.IncludeBody || debug) && !c.Request().IsBodyStream() {
+			if body, ok := captureBody(c.Get(fiber.HeaderContentType), c.Body(), opts.BodyContentTypes, opts.MaxBodyBytes, logger.config.Redactor); ok {
+				context["request_body"] = body
+			}
+		}
+
+		// Add response body for error responses if requested
+		if (debug || (opts.ResponseBodyMinStatus > 0 && c.Response().StatusCode() >= opts.ResponseBodyMinStatus)) && !c.Response().IsBodyStream() {
+			contentType := string(c.Response().Header.ContentType())
+			if body, ok := captureBody(contentType, c.Response().Body(), opts.BodyContentTypes, opts.MaxBodyBytes, logger.config.Redactor); ok {
+				context["response_body"] = body
+			}
+		}
+
+		context["request_id"] = requestID
+		if debug {
+			context["debug"] = true
+		}
+		if len(entries) > 0 {
+			context["entries"] = entries
+		}
+		if sampleRule != nil {
+			context["sample_rate"] = int(sampleRule.rate)
+		}
+		if tenantID != "" {
+			context["tenant_id"] = tenantID
+		}
+
+		// Add session and credential fingerprint fields
+		if sessionID := sessionID(c, opts); sessionID != "" {
+			context["session_id"] = sessionID
+		}
+		if opts.APIKeyHeader != "" {
+			if apiKey := c.Get(opts.APIKeyHeader); apiKey != "" {
+				context["api_key_fingerprint"] = fingerprint(apiKey)
+			}
 		}
 
 		// Add user_id from locals if available
@@ -68,8 +425,19 @@ func FiberMiddleware(opts *MiddlewareOptions) fiber.Handler {
 			context["user_id"] = userID
 		}
 
+		if opts.SemanticConventions {
+			applySemanticConventions(context)
+		}
+
+		// Add application-specific fields
+		if opts.Enricher != nil {
+			for key, value := range opts.Enricher(c) {
+				context[key] = value
+			}
+		}
+
 		// Build message
-		message := fmt.Sprintf("%s %s %d", c.Method(), path, c.Response().StatusCode())
+		message := formatMessage(req, resp)
 
 		// Log based on status code
 		statusCode := c.Response().StatusCode()
@@ -78,6 +446,15 @@ func FiberMiddleware(opts *MiddlewareOptions) fiber.Handler {
 			logger.Error(ctx, message, context)
 		} else if statusCode >= 400 {
 			logger.Warn(ctx, message, context)
+		} else if len(entries) > 0 && maxLevel >= zapcore.WarnLevel {
+			logger.Log(ctx, logLevel(maxLevel), TypeHTTP, message, context)
+		} else if opts.SlowRequestThreshold > 0 && duration > opts.SlowRequestThreshold {
+			context["slow_request"] = true
+			logger.Log(ctx, LevelWARN, TypeHTTP, message, context)
+		} else if debug {
+			logger.Log(ctx, LevelDEBUG, TypeHTTP, message, context)
+		} else if level, ok := routeLevels.level(path); ok {
+			logger.Log(ctx, level, TypeHTTP, message, context)
 		} else {
 			logger.HTTP(ctx, message, context)
 		}
@@ -86,27 +463,114 @@ func FiberMiddleware(opts *MiddlewareOptions) fiber.Handler {
 	}
 }
 
-// RecoveryMiddleware returns a Fiber middleware that recovers from panics and logs them
-func RecoveryMiddleware() fiber.Handler {
-	logger := GetInstance()
-
-	return func(c *fiber.Ctx) (err error) {
-		defer func() {
-			if r := recover(); r != nil {
-				context := LogContext{
-					"method":      c.Method(),
-					"path":        c.Path(),
-					"panic":       r,
-					"status_code": 500,
-				}
-
-				logger.Error(c.UserContext(), "Panic recovered", context)
-				err = c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": "internal server error",
-				})
-			}
-		}()
+// MatchedRoute returns the route pattern of the handler that served the
+// request (e.g. /users/:id), or "" when no handler route matched. before is
+// c.Route() as seen by the calling middleware before c.Next().
+func MatchedRoute(c *fiber.Ctx, before *fiber.Route) string {
+	if route := c.Route(); route != before {
+		return route.Path
+	}
+	return ""
+}
 
-		return c.Next()
+// FromFiber returns the request-scoped logger stored by FiberMiddleware,
+// carrying request_id, method, route and user_id. It falls back to the
+// singleton when the middleware is not installed.
+func FromFiber(c *fiber.Ctx) *Logger {
+	logger, ok := c.Locals(LocalsLogger).(*Logger)
+	if !ok {
+		logger = GetInstance()
+	}
+
+	fields := LogContext{
+		"method":     c.Method(),
+		"http.route": c.Route().Path,
+	}
+	if userID := c.Locals("user_id"); userID != nil {
+		fields["user_id"] = userID
+	}
+
+	return logger.With(fields)
+}
+
+// sessionID reads the session identifier from Locals or the session cookie
+func sessionID(c *fiber.Ctx, opts *MiddlewareOptions) string {
+	if opts.SessionLocalsKey != "" {
+		if value := c.Locals(opts.SessionLocalsKey); value != nil {
+			return fmt.Sprint(value)
+		}
+	}
+	if opts.SessionCookie != "" {
+		return c.Cookies(opts.SessionCookie)
+	}
+	return ""
+}
+
+// fiberAccessRecord collects the combined log format details of a request
+func fiberAccessRecord(c *fiber.Ctx, clientIP string, startTime time.Time, redactedParams map[string]struct{}) accessRecord {
+	uri := c.Path()
+	if query := c.Context().QueryArgs().String(); len(query) > 0 {
+		uri += "?" + redactQuery(query, redactedParams)
+	}
+
+	var user string
+	if userID := c.Locals("user_id"); userID != nil {
+		user = fmt.Sprint(userID)
+	}
+
+	return accessRecord{
+		ip:        clientIP,
+		user:      user,
+		time:      startTime,
+		method:    c.Method(),
+		uri:       uri,
+		protocol:  string(c.Request().Header.Protocol()),
+		status:    c.Response().StatusCode(),
+		bytes:     responseBytes(c),
+		referer:   c.Get(fiber.HeaderReferer),
+		userAgent: c.Get(fiber.HeaderUserAgent),
+	}
+}
+
+// requestBytes returns the request body size from Content-Length, falling
+// back to the buffered body, or -1 when unknown
+func requestBytes(c *fiber.Ctx) int {
+	if length := c.Request().Header.ContentLength(); length >= 0 {
+		return length
+	}
+	if c.Request().IsBodyStream() {
+		return -1
+	}
+	return len(c.Request().Body())
+}
+
+// responseBytes returns the response body size without consuming streamed
+// bodies, or -1 when unknown
+func responseBytes(c *fiber.Ctx) int {
+	if c.Response().IsBodyStream() {
+		if length := c.Response().Header.ContentLength(); length >= 0 {
+			return length
+		}
+		return -1
+	}
+	return len(c.Response().Body())
+}
+
+// responseHeaders collects the redacted response headers, limited to names when given
+func responseHeaders(c *fiber.Ctx, names []string, redactor *headerRedactor) map[string]string {
+	headers := make(map[string]string)
+
+	if len(names) == 0 {
+		c.Response().Header.VisitAll(func(key, value []byte) {
+			headers[string(key)] = redactor.redact(string(key), string(value))
+		})
+		return headers
+	}
+
+	for _, name := range names {
+		if value := c.Response().Header.Peek(name); len(value) > 0 {
+			headers[name] = redactor.redact(name, string(value))
+		}
 	}
+	return headers
 }
@@ -1,14 +1,20 @@
 package logger
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http/httptest"
+	"regexp"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 // =============================================================================
@@ -261,6 +267,899 @@ func TestFiberMiddlewareComprehensive(t *testing.T) {
 	})
 }
 
+func TestFiberMiddlewareExclusions(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-exclusion-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{
+		ExcludePaths:    []string{"/health/*", "/static/**"},
+		ExcludePatterns: []*regexp.Regexp{regexp.MustCompile(`^/debug/`)},
+		ExcludeMethods:  []string{"OPTIONS"},
+	}))
+	app.All("/*", func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		logged bool
+	}{
+		{"GET", "/health/live", false},
+		{"GET", "/static/img/logo.png", false},
+		{"GET", "/debug/pprof", false},
+		{"OPTIONS", "/api/orders", false},
+		{"GET", "/api/orders", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			observedLogs.TakeAll()
+			_, _ = app.Test(httptest.NewRequest(tt.method, tt.path, nil))
+
+			if logged := observedLogs.Len() > 0; logged != tt.logged {
+				t.Errorf("Expected logged=%v, got %v", tt.logged, logged)
+			}
+		})
+	}
+}
+
+func TestFiberMiddlewareSkip(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-skip-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{
+		SamplePaths: map[string]int{"/health": 1},
+		Skip: func(c *fiber.Ctx) bool {
+			return c.Get("X-Internal-Probe") == "true"
+		},
+	}))
+	app.Get("/api/orders", func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+	app.Get("/health", func(c *fiber.Ctx) error {
+		return c.SendStatus(500)
+	})
+
+	t.Run("should skip requests matching the predicate", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		req := httptest.NewRequest("GET", "/api/orders", nil)
+		req.Header.Set("X-Internal-Probe", "true")
+		resp, _ := app.Test(req)
+
+		if resp.StatusCode != 200 {
+			t.Errorf("Expected skipped request to be served, got %d", resp.StatusCode)
+		}
+		if n := observedLogs.Len(); n != 0 {
+			t.Errorf("Expected no log entries, got %d", n)
+		}
+	})
+
+	t.Run("should skip sampled paths", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		req := httptest.NewRequest("GET", "/health", nil)
+		req.Header.Set("X-Internal-Probe", "true")
+		_, _ = app.Test(req)
+
+		if n := observedLogs.Len(); n != 0 {
+			t.Errorf("Expected Skip to take precedence over SamplePaths, got %d entries", n)
+		}
+	})
+
+	t.Run("should log other requests", func(t *testing.T) {
+		observedLogs.TakeAll()
+		_, _ = app.Test(httptest.NewRequest("GET", "/api/orders", nil))
+
+		if n := observedLogs.Len(); n != 1 {
+			t.Errorf("Expected 1 log entry, got %d", n)
+		}
+	})
+}
+
+func TestFiberMiddlewareRouteLevels(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-route-levels-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{
+		RouteLevels: []RouteLevel{
+			{Pattern: "/api/webhooks/*", Level: LevelDEBUG},
+			{Pattern: "/api/**", Level: LevelINFO},
+		},
+	}))
+	app.Post("/api/webhooks/:provider", func(c *fiber.Ctx) error {
+		if c.Params("provider") == "broken" {
+			return c.SendStatus(500)
+		}
+		return c.SendStatus(200)
+	})
+
+	tests := []struct {
+		path     string
+		expected zapcore.Level
+	}{
+		{"/api/webhooks/stripe", zapcore.DebugLevel},
+		{"/api/webhooks/broken", zapcore.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			observedLogs.TakeAll()
+			_, _ = app.Test(httptest.NewRequest("POST", tt.path, nil))
+
+			logs := observedLogs.TakeAll()
+			if len(logs) != 1 {
+				t.Fatalf("Expected 1 log entry, got %d", len(logs))
+			}
+			if logs[0].Level != tt.expected {
+				t.Errorf("Expected level %v, got %v", tt.expected, logs[0].Level)
+			}
+		})
+	}
+}
+
+func TestFiberMiddlewareSamplePaths(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-sample-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	healthy := true
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{
+		ExcludePaths: []string{"/health"},
+		SamplePaths:  map[string]int{"/health": 3},
+	}))
+	app.Get("/health", func(c *fiber.Ctx) error {
+		if !healthy {
+			return c.SendStatus(503)
+		}
+		return c.SendStatus(200)
+	})
+
+	for i := 0; i < 7; i++ {
+		_, _ = app.Test(httptest.NewRequest("GET", "/health", nil))
+	}
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 3 {
+		t.Fatalf("Expected 3 sampled log entries, got %d", len(logs))
+	}
+	if logs[0].ContextMap()["sample_rate"] != int64(3) {
+		t.Errorf("Expected sample_rate 3, got %v", logs[0].ContextMap()["sample_rate"])
+	}
+
+	healthy = false
+	for i := 0; i < 2; i++ {
+		_, _ = app.Test(httptest.NewRequest("GET", "/health", nil))
+	}
+	if logs := observedLogs.TakeAll(); len(logs) != 2 {
+		t.Errorf("Expected failed requests to always be logged, got %d entries", len(logs))
+	}
+}
+
+func TestPathSamplerRules(t *testing.T) {
+	s := newPathSampler(map[string]int{
+		"/internal/*": 10,
+		"/ready":      0,
+	})
+
+	if rule := s.rule("/internal/metrics"); rule == nil || rule.rate != 10 {
+		t.Errorf("Expected glob rule with rate 10, got %+v", rule)
+	}
+	if rule := s.rule("/ready"); rule == nil || !rule.keep() || !rule.keep() {
+		t.Error("Expected rate below 1 to keep every request")
+	}
+	if rule := s.rule("/api/users"); rule != nil {
+		t.Errorf("Expected no rule for unsampled path, got %+v", rule)
+	}
+}
+
+func TestFiberMiddlewareDebugToken(t *testing.T) {
+	logger, _ := setupObservedLogger(Config{
+		ServiceName:    "middleware-debug-token-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelINFO,
+	})
+	observedCore, observedLogs := observer.New(zapcore.InfoLevel)
+	logger.zap = zap.New(observedCore)
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{
+		DebugToken:     "s3cret",
+		IncludeHeaders: true,
+	}))
+	app.Post("/orders", func(c *fiber.Ctx) error {
+		FromFiber(c).Debug(c.UserContext(), "loading order", nil)
+		return c.JSON(fiber.Map{"id": 1})
+	})
+
+	tests := []struct {
+		name    string
+		token   string
+		entries int
+		debug   bool
+	}{
+		{"valid token", "s3cret", 2, true},
+		{"wrong token", "guess", 1, false},
+		{"no token", "", 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observedLogs.TakeAll()
+			req := httptest.NewRequest("POST", "/orders", strings.NewReader(`{"sku":"A1"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.token != "" {
+				req.Header.Set("X-Debug-Token", tt.token)
+			}
+			_, _ = app.Test(req)
+
+			logs := observedLogs.TakeAll()
+			if len(logs) != tt.entries {
+				t.Fatalf("Expected %d log entries, got %d", tt.entries, len(logs))
+			}
+
+			entry := logs[len(logs)-1]
+			ctx := entry.ContextMap()
+			if !tt.debug {
+				if _, ok := ctx["request_body"]; ok {
+					t.Error("Expected no request_body without debug token")
+				}
+				return
+			}
+
+			if entry.Level != zapcore.DebugLevel {
+				t.Errorf("Expected DEBUG level, got %v", entry.Level)
+			}
+			if ctx["debug"] != true {
+				t.Errorf("Expected debug=true, got %v", ctx["debug"])
+			}
+			if ctx["request_body"] != `{"sku":"A1"}` {
+				t.Errorf("Expected request body to be captured, got %v", ctx["request_body"])
+			}
+			if ctx["response_body"] != `{"id":1}` {
+				t.Errorf("Expected response body to be captured, got %v", ctx["response_body"])
+			}
+			headers := ctx["headers"].(map[string]string)
+			if headers["X-Debug-Token"] != RedactedValue {
+				t.Errorf("Expected debug token to be redacted, got %v", headers["X-Debug-Token"])
+			}
+		})
+	}
+}
+
+func TestFiberMiddlewareAggregateEntries(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-aggregate-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{AggregateEntries: true}))
+	app.Get("/orders", func(c *fiber.Ctx) error {
+		log := FromFiber(c)
+		log.Info(c.UserContext(), "loading orders", LogContext{"count": 3})
+		if c.Query("fail") != "" {
+			log.Error(c.UserContext(), "cache unavailable", nil)
+		}
+		return c.SendStatus(200)
+	})
+	app.Get("/quiet", func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	_, _ = app.Test(httptest.NewRequest("GET", "/orders", nil))
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 aggregated log entry, got %d", len(logs))
+	}
+	if logs[0].Level != zapcore.InfoLevel {
+		t.Errorf("Expected INFO level, got %v", logs[0].Level)
+	}
+
+	entries, ok := logs[0].ContextMap()["entries"].([]map[string]interface{})
+	if !ok || len(entries) != 1 {
+		t.Fatalf("Expected 1 nested entry, got %v", logs[0].ContextMap()["entries"])
+	}
+	if entries[0]["message"] != "loading orders" || entries[0]["count"] != int64(3) {
+		t.Errorf("Unexpected nested entry: %v", entries[0])
+	}
+	if entries[0]["log.level"] != "INFO" || entries[0]["log_type"] != "normal" {
+		t.Errorf("Expected level and log_type on nested entry, got %v", entries[0])
+	}
+	if _, ok := entries[0]["request_id"]; ok {
+		t.Error("Expected request_id to be omitted from nested entries")
+	}
+
+	_, _ = app.Test(httptest.NewRequest("GET", "/orders?fail=1", nil))
+	logs = observedLogs.TakeAll()
+	if len(logs) != 1 || logs[0].Level != zapcore.ErrorLevel {
+		t.Fatalf("Expected request entry raised to ERROR, got %v", logs)
+	}
+
+	_, _ = app.Test(httptest.NewRequest("GET", "/quiet", nil))
+	logs = observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(logs))
+	}
+	if _, ok := logs[0].ContextMap()["entries"]; ok {
+		t.Error("Expected no entries field when nothing was buffered")
+	}
+}
+
+func TestEntryBufferDrained(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "entry-buffer-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	buffer := &entryBuffer{}
+	buffered := logger.withBuffer(buffer)
+	buffered.Info(context.Background(), "during request", nil)
+	if len(observedLogs.All()) != 0 {
+		t.Fatal("Expected entry to be buffered")
+	}
+
+	entries, _ := buffer.drain()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 buffered entry, got %d", len(entries))
+	}
+
+	buffered.Info(context.Background(), "after request", nil)
+	if logs := observedLogs.TakeAll(); len(logs) != 1 || logs[0].Message != "after request" {
+		t.Errorf("Expected entries after drain to be written directly, got %v", logs)
+	}
+}
+
+func TestFiberMiddlewareSlowRequests(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-slow-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{
+		SlowRequestThreshold: 20 * time.Millisecond,
+	}))
+	app.Get("/api/slow", func(c *fiber.Ctx) error {
+		time.Sleep(30 * time.Millisecond)
+		return c.SendStatus(200)
+	})
+	app.Get("/api/fast", func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	t.Run("should warn on slow successful requests", func(t *testing.T) {
+		observedLogs.TakeAll()
+		_, _ = app.Test(httptest.NewRequest("GET", "/api/slow", nil), -1)
+
+		logs := observedLogs.TakeAll()
+		if len(logs) != 1 || logs[0].Level != zapcore.WarnLevel {
+			t.Fatalf("Expected a single WARN entry, got %v", logs)
+		}
+		fields := logs[0].ContextMap()
+		if fields["slow_request"] != true || fields["log_type"] != "http" {
+			t.Errorf("Expected slow_request=true and log_type=http, got %v", fields)
+		}
+	})
+
+	t.Run("should not flag fast requests", func(t *testing.T) {
+		observedLogs.TakeAll()
+		_, _ = app.Test(httptest.NewRequest("GET", "/api/fast", nil))
+
+		logs := observedLogs.TakeAll()
+		if _, flagged := logs[0].ContextMap()["slow_request"]; flagged || logs[0].Level != zapcore.InfoLevel {
+			t.Errorf("Expected INFO entry without slow_request, got %v", logs[0])
+		}
+	})
+}
+
+func TestFiberMiddlewarePayloadSizes(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-sizes-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(nil))
+	app.Post("/api/echo", func(c *fiber.Ctx) error {
+		return c.Send(c.Body())
+	})
+
+	_, _ = app.Test(httptest.NewRequest("POST", "/api/echo", strings.NewReader("0123456789")))
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(logs))
+	}
+
+	fields := logs[0].ContextMap()
+	if fields["request_bytes"] != int64(10) {
+		t.Errorf("Expected request_bytes=10, got %v", fields["request_bytes"])
+	}
+	if fields["response_bytes"] != int64(10) {
+		t.Errorf("Expected response_bytes=10, got %v", fields["response_bytes"])
+	}
+}
+
+func TestFiberMiddlewareEnricher(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-enricher-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{
+		Enricher: func(c *fiber.Ctx) LogContext {
+			return LogContext{
+				"store_id": c.Get("X-Store-Id"),
+				"cart_id":  c.Locals("cart_id"),
+			}
+		},
+	}))
+	app.Post("/api/cart", func(c *fiber.Ctx) error {
+		c.Locals("cart_id", "cart-9")
+		return c.SendStatus(200)
+	})
+
+	req := httptest.NewRequest("POST", "/api/cart", nil)
+	req.Header.Set("X-Store-Id", "store-1")
+	_, _ = app.Test(req)
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(logs))
+	}
+
+	fields := logs[0].ContextMap()
+	if fields["store_id"] != "store-1" {
+		t.Errorf("Expected store_id=store-1, got %v", fields["store_id"])
+	}
+	if fields["cart_id"] != "cart-9" {
+		t.Errorf("Expected cart_id set by handler, got %v", fields["cart_id"])
+	}
+}
+
+func TestFiberMiddlewareSessionAndAPIKey(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-session-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{
+		SessionCookie: "sid",
+		APIKeyHeader:  "X-Api-Key",
+	}))
+	app.Get("/api/me", func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	req := httptest.NewRequest("GET", "/api/me", nil)
+	req.Header.Set("Cookie", "sid=sess-123")
+	req.Header.Set("X-Api-Key", "sk_live_abcdef")
+	_, _ = app.Test(req)
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(logs))
+	}
+
+	fields := logs[0].ContextMap()
+	if fields["session_id"] != "sess-123" {
+		t.Errorf("Expected session_id=sess-123, got %v", fields["session_id"])
+	}
+
+	fp, _ := fields["api_key_fingerprint"].(string)
+	if len(fp) != 8 || fp != fingerprint("sk_live_abcdef") {
+		t.Errorf("Expected 8-char fingerprint, got %q", fp)
+	}
+}
+
+func TestFromFiber(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "from-fiber-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "usr-7")
+		return c.Next()
+	})
+	app.Use(FiberMiddleware(nil))
+	app.Get("/api/orders/:id", func(c *fiber.Ctx) error {
+		FromFiber(c).Info(context.Background(), "Loading order", nil)
+		return c.SendStatus(200)
+	})
+
+	t.Run("should propagate incoming request ID", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		req := httptest.NewRequest("GET", "/api/orders/1", nil)
+		req.Header.Set("X-Request-ID", "req-abc")
+		resp, _ := app.Test(req)
+
+		if got := resp.Header.Get("X-Request-ID"); got != "req-abc" {
+			t.Errorf("Expected X-Request-ID echoed, got %q", got)
+		}
+
+		logs := observedLogs.TakeAll()
+		if len(logs) != 2 {
+			t.Fatalf("Expected handler and request entries, got %d", len(logs))
+		}
+
+		handlerFields := logs[0].ContextMap()
+		expected := map[string]interface{}{
+			"request_id": "req-abc",
+			"method":     "GET",
+			"http.route": "/api/orders/:id",
+			"user_id":    "usr-7",
+		}
+		for key, value := range expected {
+			if handlerFields[key] != value {
+				t.Errorf("Expected %s=%v, got %v", key, value, handlerFields[key])
+			}
+		}
+
+		if logs[1].ContextMap()["request_id"] != "req-abc" {
+			t.Errorf("Expected request entry to carry request_id, got %v", logs[1].ContextMap()["request_id"])
+		}
+	})
+
+	t.Run("should generate request ID when absent", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		resp, _ := app.Test(httptest.NewRequest("GET", "/api/orders/2", nil))
+
+		requestID := resp.Header.Get("X-Request-ID")
+		if requestID == "" {
+			t.Fatal("Expected generated X-Request-ID")
+		}
+		for _, entry := range observedLogs.TakeAll() {
+			if entry.ContextMap()["request_id"] != requestID {
+				t.Errorf("Expected request_id=%s, got %v", requestID, entry.ContextMap()["request_id"])
+			}
+		}
+	})
+
+	t.Run("should fall back to singleton without middleware", func(t *testing.T) {
+		bare := fiber.New()
+		bare.Get("/", func(c *fiber.Ctx) error {
+			if FromFiber(c) == nil {
+				t.Error("Expected a logger")
+			}
+			return nil
+		})
+		_, _ = bare.Test(httptest.NewRequest("GET", "/", nil))
+	})
+}
+
+func TestFiberMiddlewareRoute(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-route-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(nil))
+	api := app.Group("/api")
+	api.Get("/users/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	t.Run("should log matched route pattern and raw path", func(t *testing.T) {
+		observedLogs.TakeAll()
+		_, _ = app.Test(httptest.NewRequest("GET", "/api/users/42", nil))
+
+		fields := observedLogs.TakeAll()[0].ContextMap()
+		if fields["http.route"] != "/api/users/:id" {
+			t.Errorf("Expected http.route=/api/users/:id, got %v", fields["http.route"])
+		}
+		if fields["path"] != "/api/users/42" {
+			t.Errorf("Expected path=/api/users/42, got %v", fields["path"])
+		}
+	})
+
+	t.Run("should omit route for unmatched requests", func(t *testing.T) {
+		observedLogs.TakeAll()
+		_, _ = app.Test(httptest.NewRequest("GET", "/missing", nil))
+
+		fields := observedLogs.TakeAll()[0].ContextMap()
+		if route, ok := fields["http.route"]; ok {
+			t.Errorf("Expected no http.route for 404, got %v", route)
+		}
+	})
+}
+
+// =============================================================================
+// FIBER MIDDLEWARE BODY CAPTURE TESTS
+// =============================================================================
+
+func TestFiberMiddlewareBody(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-body-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{
+		IncludeBody:  true,
+		MaxBodyBytes: 16,
+	}))
+	app.Post("/api/orders", func(c *fiber.Ctx) error {
+		return c.SendStatus(201)
+	})
+
+	t.Run("should capture JSON request body", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		req := httptest.NewRequest("POST", "/api/orders", strings.NewReader(`{"sku":"A1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		_, _ = app.Test(req)
+
+		logs := observedLogs.TakeAll()
+		if len(logs) != 1 {
+			t.Fatalf("Expected 1 log entry, got %d", len(logs))
+		}
+		if body := logs[0].ContextMap()["request_body"]; body != `{"sku":"A1"}` {
+			t.Errorf("Expected request_body to be captured, got %v", body)
+		}
+	})
+
+	t.Run("should truncate large bodies", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		req := httptest.NewRequest("POST", "/api/orders", strings.NewReader(strings.Repeat("x", 40)))
+		req.Header.Set("Content-Type", "text/plain")
+		_, _ = app.Test(req)
+
+		logs := observedLogs.TakeAll()
+		body, _ := logs[0].ContextMap()["request_body"].(string)
+		if !strings.HasSuffix(body, "...(truncated, 40 bytes)") {
+			t.Errorf("Expected truncation marker, got %q", body)
+		}
+	})
+
+	t.Run("should skip multipart bodies", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		req := httptest.NewRequest("POST", "/api/orders", strings.NewReader("--x--"))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+		_, _ = app.Test(req)
+
+		logs := observedLogs.TakeAll()
+		if _, ok := logs[0].ContextMap()["request_body"]; ok {
+			t.Error("Expected multipart body to be skipped")
+		}
+	})
+}
+
+func TestFiberMiddlewareResponseBody(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-response-body-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{
+		ResponseBodyMinStatus: 500,
+	}))
+	app.Get("/api/ok", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"status": "ok"})
+	})
+	app.Get("/api/invalid", func(c *fiber.Ctx) error {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid"})
+	})
+	app.Get("/api/fail", func(c *fiber.Ctx) error {
+		return c.Status(502).JSON(fiber.Map{"error": "upstream down"})
+	})
+	app.Get("/api/stream", func(c *fiber.Ctx) error {
+		c.Type("json")
+		return c.Status(503).SendStream(io.MultiReader(strings.NewReader(`{"error":`), strings.NewReader(`"draining"}`)))
+	})
+
+	tests := []struct {
+		path     string
+		expected interface{}
+	}{
+		{"/api/ok", nil},
+		{"/api/invalid", nil},
+		{"/api/fail", `{"error":"upstream down"}`},
+		{"/api/stream", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			observedLogs.TakeAll()
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if body, _ := io.ReadAll(resp.Body); tt.path == "/api/stream" && string(body) != `{"error":"draining"}` {
+				t.Errorf("Expected the streamed body to reach the client, got %q", body)
+			}
+
+			logs := observedLogs.TakeAll()
+			if len(logs) != 1 {
+				t.Fatalf("Expected 1 log entry, got %d", len(logs))
+			}
+			if body := logs[0].ContextMap()["response_body"]; body != tt.expected {
+				t.Errorf("Expected response_body=%v, got %v", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestFiberMiddlewareResponseHeaders(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-response-headers-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	handler := func(c *fiber.Ctx) error {
+		c.Set("Cache-Control", "no-store")
+		c.Set("X-RateLimit-Remaining", "41")
+		c.Set("X-Internal", "secret")
+		return c.JSON(fiber.Map{"ok": true})
+	}
+
+	t.Run("should capture selected response headers", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		app := fiber.New()
+		app.Use(FiberMiddleware(&MiddlewareOptions{
+			IncludeResponseHeaders: true,
+			ResponseHeaders:        []string{"Content-Type", "X-RateLimit-Remaining"},
+		}))
+		app.Get("/api/limited", handler)
+		_, _ = app.Test(httptest.NewRequest("GET", "/api/limited", nil))
+
+		logs := observedLogs.TakeAll()
+		headers, ok := logs[0].ContextMap()["response_headers"].(map[string]string)
+		if !ok {
+			t.Fatalf("Expected response_headers map, got %T", logs[0].ContextMap()["response_headers"])
+		}
+		if headers["X-RateLimit-Remaining"] != "41" || headers["Content-Type"] != "application/json" {
+			t.Errorf("Unexpected response headers %v", headers)
+		}
+		if _, leaked := headers["X-Internal"]; leaked {
+			t.Error("Expected unselected header to be omitted")
+		}
+	})
+
+	t.Run("should capture all response headers without a list", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		app := fiber.New()
+		app.Use(FiberMiddleware(&MiddlewareOptions{IncludeResponseHeaders: true}))
+		app.Get("/api/all", handler)
+		_, _ = app.Test(httptest.NewRequest("GET", "/api/all", nil))
+
+		logs := observedLogs.TakeAll()
+		headers, _ := logs[0].ContextMap()["response_headers"].(map[string]string)
+		if headers["X-Internal"] != "secret" || headers["Cache-Control"] != "no-store" {
+			t.Errorf("Expected all response headers, got %v", headers)
+		}
+	})
+}
+
+func TestFiberMiddlewareHeaderRedaction(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-redaction-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{
+		IncludeHeaders:         true,
+		IncludeResponseHeaders: true,
+		RedactHeaders:          []string{"X-Tenant-Secret"},
+	}))
+	app.Get("/api/login", func(c *fiber.Ctx) error {
+		c.Cookie(&fiber.Cookie{Name: "session", Value: "s3cr3t"})
+		return c.SendStatus(200)
+	})
+
+	req := httptest.NewRequest("GET", "/api/login", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("X-Tenant-Secret", "tenant")
+	req.Header.Set("X-Request-Id", "req-1")
+	_, _ = app.Test(req)
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(logs))
+	}
+
+	headers, _ := logs[0].ContextMap()["headers"].(map[string]string)
+	if headers["Authorization"] != RedactedValue || headers["X-Tenant-Secret"] != RedactedValue {
+		t.Errorf("Expected sensitive request headers to be redacted, got %v", headers)
+	}
+	if headers["X-Request-Id"] != "req-1" {
+		t.Errorf("Expected X-Request-Id to be kept, got %v", headers["X-Request-Id"])
+	}
+
+	responseHeaders, _ := logs[0].ContextMap()["response_headers"].(map[string]string)
+	if responseHeaders["Set-Cookie"] != RedactedValue {
+		t.Errorf("Expected Set-Cookie to be redacted, got %v", responseHeaders["Set-Cookie"])
+	}
+}
+
+func TestFiberMiddlewareQueryRedaction(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-query-redaction-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{
+		RedactQueryParams: []string{"signature"},
+	}))
+	app.Get("/api/reset", func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	_, _ = app.Test(httptest.NewRequest("GET", "/api/reset?token=abc&signature=sig&lang=en", nil))
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(logs))
+	}
+
+	query := logs[0].ContextMap()["query"]
+	if query != "token=[REDACTED]&signature=[REDACTED]&lang=en" {
+		t.Errorf("Expected sensitive query params to be redacted, got %v", query)
+	}
+}
+
 // =============================================================================
 // RECOVERY MIDDLEWARE TESTS
 // =============================================================================
@@ -468,3 +1367,43 @@ func TestMiddlewareIntegration(t *testing.T) {
 		}
 	})
 }
+
+func TestFiberMiddlewareLogRequestStart(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "middleware-start-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{LogRequestStart: true}))
+	app.Get("/export", func(c *fiber.Ctx) error {
+		if n := observedLogs.Len(); n != 1 {
+			t.Errorf("Expected the start entry before the handler finished, got %d entries", n)
+		}
+		return c.SendStatus(200)
+	})
+
+	req := httptest.NewRequest("GET", "/export", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	_, _ = app.Test(req)
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 2 {
+		t.Fatalf("Expected start and completion entries, got %d", len(logs))
+	}
+
+	start := logs[0].ContextMap()
+	if logs[0].Message != "GET /export started" || start["request_phase"] != "started" || start["request_id"] != "req-42" || start["path"] != "/export" {
+		t.Errorf("Unexpected start entry %q: %v", logs[0].Message, start)
+	}
+	if _, ok := start["status_code"]; ok {
+		t.Error("Expected the start entry to stay lightweight")
+	}
+
+	completed := logs[1].ContextMap()
+	if completed["request_phase"] != "completed" || completed["request_id"] != "req-42" || completed["status_code"] != int64(200) {
+		t.Errorf("Unexpected completion entry: %v", completed)
+	}
+}
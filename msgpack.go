@@ -0,0 +1,539 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// MessagePack type markers used by msgpackEncoder
+const (
+	msgpackNil     = 0xc0
+	msgpackFalse   = 0xc2
+	msgpackTrue    = 0xc3
+	msgpackBin8    = 0xc4
+	msgpackBin16   = 0xc5
+	msgpackBin32   = 0xc6
+	msgpackExt8    = 0xc7
+	msgpackFloat32 = 0xca
+	msgpackFloat64 = 0xcb
+	msgpackUint8   = 0xcc
+	msgpackUint16  = 0xcd
+	msgpackUint32  = 0xce
+	msgpackUint64  = 0xcf
+	msgpackInt8    = 0xd0
+	msgpackInt16   = 0xd1
+	msgpackInt32   = 0xd2
+	msgpackInt64   = 0xd3
+	msgpackStr8    = 0xd9
+	msgpackStr16   = 0xda
+	msgpackStr32   = 0xdb
+	msgpackArray16 = 0xdc
+	msgpackArray32 = 0xdd
+	msgpackMap16   = 0xde
+	msgpackMap32   = 0xdf
+
+	// msgpackTimestamp is the extension type of timestamps
+	msgpackTimestamp = 0xff
+)
+
+var msgpackPool = buffer.NewPool()
+
+// msgpackEntryPool reuses the encoders of individual entries
+var msgpackEntryPool = sync.Pool{
+	New: func() interface{} {
+		return &msgpackEncoder{frames: make([]msgpackFrame, 0, 8)}
+	},
+}
+
+// msgpackEncoder encodes entries as MessagePack maps, one after another
+// without separators, since each map is self-delimiting. Fields are written
+// as they are added; maps and arrays of unknown length get a 32-bit header
+// whose count is filled in when they are closed.
+type msgpackEncoder struct {
+	cfg    *zapcore.EncoderConfig
+	buf    *buffer.Buffer
+	frames []msgpackFrame
+}
+
+// msgpackFrame is an open map or array
+type msgpackFrame struct {
+	// offset is the position of the header's count, -1 for the root of
+	// context fields, which has no header
+	offset int
+	count  uint32
+}
+
+func newMsgpackEncoder(cfg zapcore.EncoderConfig) *msgpackEncoder {
+	return &msgpackEncoder{
+		cfg:    &cfg,
+		buf:    msgpackPool.Get(),
+		frames: []msgpackFrame{{offset: -1}},
+	}
+}
+
+func (e *msgpackEncoder) Clone() zapcore.Encoder {
+	clone := &msgpackEncoder{
+		cfg:    e.cfg,
+		buf:    msgpackPool.Get(),
+		frames: append([]msgpackFrame(nil), e.frames...),
+	}
+	clone.buf.Write(e.buf.Bytes())
+	return clone
+}
+
+func (e *msgpackEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := msgpackEntryPool.Get().(*msgpackEncoder)
+	final.cfg, final.buf, final.frames = e.cfg, msgpackPool.Get(), final.frames[:0]
+	final.open(msgpackMap32)
+
+	if e.cfg.TimeKey != "" {
+		final.AddTime(e.cfg.TimeKey, entry.Time)
+	}
+	if e.cfg.LevelKey != "" {
+		final.AddString(e.cfg.LevelKey, entry.Level.CapitalString())
+	}
+	if entry.LoggerName != "" && e.cfg.NameKey != "" {
+		final.AddString(e.cfg.NameKey, entry.LoggerName)
+	}
+	if entry.Caller.Defined && e.cfg.CallerKey != "" {
+		final.AddString(e.cfg.CallerKey, entry.Caller.TrimmedPath())
+	}
+	if e.cfg.MessageKey != "" {
+		final.AddString(e.cfg.MessageKey, entry.Message)
+	}
+
+	// Append the context fields, including namespaces left open by With
+	base := final.buf.Len()
+	final.buf.Write(e.buf.Bytes())
+	final.frames[0].count += e.frames[0].count
+	for _, frame := range e.frames[1:] {
+		frame.offset += base
+		final.frames = append(final.frames, frame)
+	}
+
+	for _, field := range fields {
+		field.AddTo(final)
+	}
+	final.closeTo(1)
+
+	if entry.Stack != "" && e.cfg.StacktraceKey != "" {
+		final.AddString(e.cfg.StacktraceKey, entry.Stack)
+	}
+	final.closeTo(0)
+
+	buf := final.buf
+	final.buf = nil
+	msgpackEntryPool.Put(final)
+	return buf, nil
+}
+
+// open writes a map or array header with a count to be filled in by close
+func (e *msgpackEncoder) open(header byte) {
+	e.buf.AppendByte(header)
+	e.frames = append(e.frames, msgpackFrame{offset: e.buf.Len()})
+	e.buf.Write([]byte{0, 0, 0, 0})
+}
+
+// closeTo closes open maps and arrays until depth remain
+func (e *msgpackEncoder) closeTo(depth int) {
+	for len(e.frames) > depth {
+		frame := e.frames[len(e.frames)-1]
+		e.frames = e.frames[:len(e.frames)-1]
+		binary.BigEndian.PutUint32(e.buf.Bytes()[frame.offset:], frame.count)
+	}
+}
+
+// element counts a key-value pair or array element in the innermost map
+// or array
+func (e *msgpackEncoder) element() {
+	e.frames[len(e.frames)-1].count++
+}
+
+// key writes the key of a key-value pair and counts the pair
+func (e *msgpackEncoder) key(key string) {
+	e.writeString(key)
+	e.element()
+}
+
+func (e *msgpackEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	e.key(key)
+	return e.writeArray(marshaler)
+}
+
+func (e *msgpackEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	e.key(key)
+	return e.writeObject(marshaler)
+}
+
+func (e *msgpackEncoder) AddBinary(key string, value []byte) {
+	e.key(key)
+	e.writeBinary(value)
+}
+
+func (e *msgpackEncoder) AddByteString(key string, value []byte) {
+	e.key(key)
+	e.writeString(string(value))
+}
+
+func (e *msgpackEncoder) AddBool(key string, value bool) {
+	e.key(key)
+	e.writeBool(value)
+}
+
+func (e *msgpackEncoder) AddComplex128(key string, value complex128) {
+	e.key(key)
+	e.writeString(strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+func (e *msgpackEncoder) AddComplex64(key string, value complex64) {
+	e.key(key)
+	e.writeString(strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+// AddDuration writes durations in seconds, like the JSON encoder
+func (e *msgpackEncoder) AddDuration(key string, value time.Duration) {
+	e.key(key)
+	e.writeFloat64(value.Seconds())
+}
+
+func (e *msgpackEncoder) AddFloat64(key string, value float64) {
+	e.key(key)
+	e.writeFloat64(value)
+}
+
+func (e *msgpackEncoder) AddFloat32(key string, value float32) {
+	e.key(key)
+	e.writeFloat32(value)
+}
+
+func (e *msgpackEncoder) AddInt(key string, value int)     { e.AddInt64(key, int64(value)) }
+func (e *msgpackEncoder) AddInt32(key string, value int32) { e.AddInt64(key, int64(value)) }
+func (e *msgpackEncoder) AddInt16(key string, value int16) { e.AddInt64(key, int64(value)) }
+func (e *msgpackEncoder) AddInt8(key string, value int8)   { e.AddInt64(key, int64(value)) }
+
+func (e *msgpackEncoder) AddInt64(key string, value int64) {
+	e.key(key)
+	e.writeInt64(value)
+}
+
+func (e *msgpackEncoder) AddString(key, value string) {
+	e.key(key)
+	e.writeString(value)
+}
+
+// AddTime writes times as MessagePack timestamps
+func (e *msgpackEncoder) AddTime(key string, value time.Time) {
+	e.key(key)
+	e.writeTime(value)
+}
+
+func (e *msgpackEncoder) AddUint(key string, value uint)       { e.AddUint64(key, uint64(value)) }
+func (e *msgpackEncoder) AddUint32(key string, value uint32)   { e.AddUint64(key, uint64(value)) }
+func (e *msgpackEncoder) AddUint16(key string, value uint16)   { e.AddUint64(key, uint64(value)) }
+func (e *msgpackEncoder) AddUint8(key string, value uint8)     { e.AddUint64(key, uint64(value)) }
+func (e *msgpackEncoder) AddUintptr(key string, value uintptr) { e.AddUint64(key, uint64(value)) }
+
+func (e *msgpackEncoder) AddUint64(key string, value uint64) {
+	e.key(key)
+	e.writeUint64(value)
+}
+
+func (e *msgpackEncoder) AddReflected(key string, value interface{}) error {
+	e.key(key)
+	return e.writeReflected(value)
+}
+
+// OpenNamespace nests the following fields in a map under key, until the
+// enclosing object or the entry ends
+func (e *msgpackEncoder) OpenNamespace(key string) {
+	e.key(key)
+	e.open(msgpackMap32)
+}
+
+func (e *msgpackEncoder) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	e.element()
+	return e.writeArray(marshaler)
+}
+
+func (e *msgpackEncoder) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	e.element()
+	return e.writeObject(marshaler)
+}
+
+func (e *msgpackEncoder) AppendReflected(value interface{}) error {
+	e.element()
+	return e.writeReflected(value)
+}
+
+func (e *msgpackEncoder) AppendBool(value bool) {
+	e.element()
+	e.writeBool(value)
+}
+
+func (e *msgpackEncoder) AppendByteString(value []byte) {
+	e.element()
+	e.writeString(string(value))
+}
+
+func (e *msgpackEncoder) AppendComplex128(value complex128) {
+	e.element()
+	e.writeString(strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+func (e *msgpackEncoder) AppendComplex64(value complex64) {
+	e.element()
+	e.writeString(strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+func (e *msgpackEncoder) AppendDuration(value time.Duration) {
+	e.element()
+	e.writeFloat64(value.Seconds())
+}
+
+func (e *msgpackEncoder) AppendFloat64(value float64) {
+	e.element()
+	e.writeFloat64(value)
+}
+
+func (e *msgpackEncoder) AppendFloat32(value float32) {
+	e.element()
+	e.writeFloat32(value)
+}
+
+func (e *msgpackEncoder) AppendInt(value int)     { e.AppendInt64(int64(value)) }
+func (e *msgpackEncoder) AppendInt32(value int32) { e.AppendInt64(int64(value)) }
+func (e *msgpackEncoder) AppendInt16(value int16) { e.AppendInt64(int64(value)) }
+func (e *msgpackEncoder) AppendInt8(value int8)   { e.AppendInt64(int64(value)) }
+
+func (e *msgpackEncoder) AppendInt64(value int64) {
+	e.element()
+	e.writeInt64(value)
+}
+
+func (e *msgpackEncoder) AppendString(value string) {
+	e.element()
+	e.writeString(value)
+}
+
+func (e *msgpackEncoder) AppendTime(value time.Time) {
+	e.element()
+	e.writeTime(value)
+}
+
+func (e *msgpackEncoder) AppendUint(value uint)       { e.AppendUint64(uint64(value)) }
+func (e *msgpackEncoder) AppendUint32(value uint32)   { e.AppendUint64(uint64(value)) }
+func (e *msgpackEncoder) AppendUint16(value uint16)   { e.AppendUint64(uint64(value)) }
+func (e *msgpackEncoder) AppendUint8(value uint8)     { e.AppendUint64(uint64(value)) }
+func (e *msgpackEncoder) AppendUintptr(value uintptr) { e.AppendUint64(uint64(value)) }
+
+func (e *msgpackEncoder) AppendUint64(value uint64) {
+	e.element()
+	e.writeUint64(value)
+}
+
+func (e *msgpackEncoder) writeArray(marshaler zapcore.ArrayMarshaler) error {
+	depth := len(e.frames)
+	e.open(msgpackArray32)
+	err := marshaler.MarshalLogArray(e)
+	e.closeTo(depth)
+	return err
+}
+
+func (e *msgpackEncoder) writeObject(marshaler zapcore.ObjectMarshaler) error {
+	depth := len(e.frames)
+	e.open(msgpackMap32)
+	err := marshaler.MarshalLogObject(e)
+	e.closeTo(depth)
+	return err
+}
+
+// writeReflected writes value as the JSON encoder would serialize it. If
+// it cannot be serialized, nil is written and the error returned.
+func (e *msgpackEncoder) writeReflected(value interface{}) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		e.writeNil()
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		e.writeNil()
+		return err
+	}
+	e.writeDecoded(decoded)
+	return nil
+}
+
+// writeDecoded writes a value decoded from JSON
+func (e *msgpackEncoder) writeDecoded(value interface{}) {
+	switch v := value.(type) {
+	case bool:
+		e.writeBool(v)
+	case string:
+		e.writeString(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			e.writeInt64(i)
+		} else {
+			f, _ := v.Float64()
+			e.writeFloat64(f)
+		}
+	case []interface{}:
+		e.writeHeader(len(v), 0x90, msgpackArray16, msgpackArray32)
+		for _, elem := range v {
+			e.writeDecoded(elem)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		e.writeHeader(len(v), 0x80, msgpackMap16, msgpackMap32)
+		for _, key := range keys {
+			e.writeString(key)
+			e.writeDecoded(v[key])
+		}
+	default:
+		e.writeNil()
+	}
+}
+
+// writeHeader writes the header of a map or array of known length
+func (e *msgpackEncoder) writeHeader(n int, fix, header16, header32 byte) {
+	switch {
+	case n < 16:
+		e.buf.AppendByte(fix | byte(n))
+	case n <= math.MaxUint16:
+		e.buf.AppendByte(header16)
+		e.writeBigEndian(uint64(n), 2)
+	default:
+		e.buf.AppendByte(header32)
+		e.writeBigEndian(uint64(n), 4)
+	}
+}
+
+func (e *msgpackEncoder) writeNil() {
+	e.buf.AppendByte(msgpackNil)
+}
+
+func (e *msgpackEncoder) writeBool(value bool) {
+	if value {
+		e.buf.AppendByte(msgpackTrue)
+	} else {
+		e.buf.AppendByte(msgpackFalse)
+	}
+}
+
+func (e *msgpackEncoder) writeInt64(value int64) {
+	switch {
+	case value >= 0:
+		e.writeUint64(uint64(value))
+	case value >= -32:
+		e.buf.AppendByte(byte(value))
+	case value >= math.MinInt8:
+		e.buf.AppendByte(msgpackInt8)
+		e.writeBigEndian(uint64(value), 1)
+	case value >= math.MinInt16:
+		e.buf.AppendByte(msgpackInt16)
+		e.writeBigEndian(uint64(value), 2)
+	case value >= math.MinInt32:
+		e.buf.AppendByte(msgpackInt32)
+		e.writeBigEndian(uint64(value), 4)
+	default:
+		e.buf.AppendByte(msgpackInt64)
+		e.writeBigEndian(uint64(value), 8)
+	}
+}
+
+func (e *msgpackEncoder) writeUint64(value uint64) {
+	switch {
+	case value < 128:
+		e.buf.AppendByte(byte(value))
+	case value <= math.MaxUint8:
+		e.buf.AppendByte(msgpackUint8)
+		e.writeBigEndian(value, 1)
+	case value <= math.MaxUint16:
+		e.buf.AppendByte(msgpackUint16)
+		e.writeBigEndian(value, 2)
+	case value <= math.MaxUint32:
+		e.buf.AppendByte(msgpackUint32)
+		e.writeBigEndian(value, 4)
+	default:
+		e.buf.AppendByte(msgpackUint64)
+		e.writeBigEndian(value, 8)
+	}
+}
+
+func (e *msgpackEncoder) writeFloat64(value float64) {
+	e.buf.AppendByte(msgpackFloat64)
+	e.writeBigEndian(math.Float64bits(value), 8)
+}
+
+func (e *msgpackEncoder) writeFloat32(value float32) {
+	e.buf.AppendByte(msgpackFloat32)
+	e.writeBigEndian(uint64(math.Float32bits(value)), 4)
+}
+
+func (e *msgpackEncoder) writeString(value string) {
+	n := len(value)
+	switch {
+	case n < 32:
+		e.buf.AppendByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		e.buf.AppendByte(msgpackStr8)
+		e.writeBigEndian(uint64(n), 1)
+	case n <= math.MaxUint16:
+		e.buf.AppendByte(msgpackStr16)
+		e.writeBigEndian(uint64(n), 2)
+	default:
+		e.buf.AppendByte(msgpackStr32)
+		e.writeBigEndian(uint64(n), 4)
+	}
+	e.buf.AppendString(value)
+}
+
+func (e *msgpackEncoder) writeBinary(value []byte) {
+	n := len(value)
+	switch {
+	case n <= math.MaxUint8:
+		e.buf.AppendByte(msgpackBin8)
+		e.writeBigEndian(uint64(n), 1)
+	case n <= math.MaxUint16:
+		e.buf.AppendByte(msgpackBin16)
+		e.writeBigEndian(uint64(n), 2)
+	default:
+		e.buf.AppendByte(msgpackBin32)
+		e.writeBigEndian(uint64(n), 4)
+	}
+	e.buf.Write(value)
+}
+
+// writeTime writes the 96-bit timestamp extension, which covers all times
+func (e *msgpackEncoder) writeTime(value time.Time) {
+	e.buf.AppendByte(msgpackExt8)
+	e.buf.AppendByte(12)
+	e.buf.AppendByte(msgpackTimestamp)
+	e.writeBigEndian(uint64(value.Nanosecond()), 4)
+	e.writeBigEndian(uint64(value.Unix()), 8)
+}
+
+// writeBigEndian writes the low size bytes of value, most significant first
+func (e *msgpackEncoder) writeBigEndian(value uint64, size int) {
+	for shift := (size - 1) * 8; shift >= 0; shift -= 8 {
+		e.buf.AppendByte(byte(value >> shift))
+	}
+}
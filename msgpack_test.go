@@ -0,0 +1,310 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// decodeMsgpack decodes a stream of MessagePack maps
+func decodeMsgpack(t *testing.T, data []byte) []map[string]interface{} {
+	t.Helper()
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	var entries []map[string]interface{}
+	for {
+		var entry map[string]interface{}
+		err := decoder.Decode(&entry)
+		if err == io.EOF {
+			return entries
+		}
+		if err != nil {
+			t.Fatalf("Decoding MessagePack: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func TestMsgpackEncoding(t *testing.T) {
+	var output bytes.Buffer
+	logger := New(Config{ServiceName: "msgpack-test", Level: LevelINFO, Encoding: EncodingMsgpack, Output: &output})
+	child := logger.With(LogContext{"component": "checkout"})
+
+	child.Info(context.Background(), "Order created", LogContext{
+		"order_id": "ord-123",
+		"items":    3,
+		"offset":   -1000,
+		"total":    99.5,
+		"paid":     true,
+		"timeout":  1500 * time.Millisecond,
+		"lines":    []map[string]interface{}{{"sku": "A", "qty": 2}},
+	})
+	child.Error(context.Background(), "Payment failed", LogContext{"error": errors.New("declined")})
+
+	entries := decodeMsgpack(t, output.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	entry := entries[0]
+	if entry["message"] != "Order created" || entry["log.level"] != "INFO" || entry["log_type"] != "normal" {
+		t.Errorf("Unexpected entry metadata: %v", entry)
+	}
+	if timestamp, ok := entry["@timestamp"].(time.Time); !ok || time.Since(timestamp) > time.Minute {
+		t.Errorf("Expected a timestamp, got %v", entry["@timestamp"])
+	}
+	if entry["service.name"] != "msgpack-test" || entry["component"] != "checkout" {
+		t.Errorf("Expected constant and child fields, got %v", entry)
+	}
+	if entry["order_id"] != "ord-123" || entry["items"] != int8(3) || entry["offset"] != int16(-1000) ||
+		entry["total"] != 99.5 || entry["paid"] != true || entry["timeout"] != 1.5 {
+		t.Errorf("Unexpected fields: %v", entry)
+	}
+	lines, ok := entry["lines"].([]interface{})
+	if !ok || len(lines) != 1 || lines[0].(map[string]interface{})["sku"] != "A" {
+		t.Errorf("Expected nested lines, got %v", entry["lines"])
+	}
+	if entries[1]["error_message"] != "declined" || entries[1]["log.level"] != "ERROR" {
+		t.Errorf("Unexpected error entry: %v", entries[1])
+	}
+}
+
+// namespaced marshals an object with a namespace, which lasts until the
+// object ends
+type namespaced struct{}
+
+func (namespaced) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", "outer")
+	enc.OpenNamespace("inner")
+	enc.AddInt("depth", 2)
+	return enc.AddArray("tags", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		arr.AppendString("a")
+		arr.AppendUint64(1 << 40)
+		return nil
+	}))
+}
+
+func TestMsgpackEncoderNamespaces(t *testing.T) {
+	encoder := newMsgpackEncoder(encoderConfig())
+	zap.String("service.name", "svc").AddTo(encoder)
+	zap.Namespace("request").AddTo(encoder)
+	zap.String("id", "req-1").AddTo(encoder)
+
+	// Clones keep the open namespace without affecting the original
+	clone := encoder.Clone()
+	zap.Int("attempt", 2).AddTo(clone)
+
+	buf, err := clone.EncodeEntry(zapcore.Entry{Level: zapcore.WarnLevel, Message: "retry", Time: time.Unix(1700000000, 5)}, []zapcore.Field{
+		zap.Object("object", namespaced{}),
+		zap.Binary("raw", []byte{1, 2}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := decodeMsgpack(t, buf.Bytes())[0]
+
+	if entry["service.name"] != "svc" || !entry["@timestamp"].(time.Time).Equal(time.Unix(1700000000, 5)) {
+		t.Errorf("Unexpected root fields: %v", entry)
+	}
+	request := entry["request"].(map[string]interface{})
+	if request["id"] != "req-1" || request["attempt"] != int8(2) || !bytes.Equal(request["raw"].([]byte), []byte{1, 2}) {
+		t.Errorf("Expected fields nested in the namespace, got %v", request)
+	}
+	object := request["object"].(map[string]interface{})
+	inner := object["inner"].(map[string]interface{})
+	tags := inner["tags"].([]interface{})
+	if object["name"] != "outer" || inner["depth"] != int8(2) || tags[0] != "a" || tags[1] != uint64(1<<40) {
+		t.Errorf("Unexpected object: %v", object)
+	}
+
+	original, _ := encoder.EncodeEntry(zapcore.Entry{Message: "original"}, nil)
+	if _, ok := decodeMsgpack(t, original.Bytes())[0]["request"].(map[string]interface{})["attempt"]; ok {
+		t.Error("Expected the clone's fields not to leak into the original")
+	}
+}
+
+// everyType marshals a value of every type zap encoders support
+type everyType struct{}
+
+func (everyType) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddByteString("byte_string", []byte("bytes"))
+	enc.AddComplex128("complex128", complex(1, 2))
+	enc.AddComplex64("complex64", complex(3, -4))
+	enc.AddFloat32("float32", 1.5)
+	enc.AddInt("int", -1)
+	enc.AddInt32("int32", -32)
+	enc.AddInt16("int16", -16)
+	enc.AddInt8("int8", -8)
+	enc.AddUint("uint", 1)
+	enc.AddUint32("uint32", 32)
+	enc.AddUint16("uint16", 16)
+	enc.AddUint8("uint8", 8)
+	enc.AddUintptr("uintptr", 7)
+	enc.AddTime("time", time.Unix(1700000000, 0))
+	if err := enc.AddReflected("reflected", map[string]interface{}{"ids": []int{1, 2}, "ratio": 0.5, "ok": true, "none": nil}); err != nil {
+		return err
+	}
+	if err := enc.AddObject("object", namespaced{}); err != nil {
+		return err
+	}
+	return enc.AddArray("array", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		arr.AppendBool(true)
+		arr.AppendByteString([]byte("bytes"))
+		arr.AppendComplex128(complex(1, 2))
+		arr.AppendComplex64(complex(3, -4))
+		arr.AppendDuration(time.Second)
+		arr.AppendFloat64(2.5)
+		arr.AppendFloat32(1.5)
+		arr.AppendInt(-1)
+		arr.AppendInt64(-64)
+		arr.AppendInt32(-32)
+		arr.AppendInt16(-16)
+		arr.AppendInt8(-8)
+		arr.AppendTime(time.Unix(1700000000, 0))
+		arr.AppendUint(1)
+		arr.AppendUint64(64)
+		arr.AppendUint32(32)
+		arr.AppendUint16(16)
+		arr.AppendUint8(8)
+		arr.AppendUintptr(7)
+		if err := arr.AppendReflected([]string{"x"}); err != nil {
+			return err
+		}
+		if err := arr.AppendObject(namespaced{}); err != nil {
+			return err
+		}
+		return arr.AppendArray(zapcore.ArrayMarshalerFunc(func(inner zapcore.ArrayEncoder) error {
+			inner.AppendString("nested")
+			return nil
+		}))
+	}))
+}
+
+// everyTypeValues are the fmt forms of everyType's scalar values, in
+// object order
+var everyTypeValues = []struct{ key, value string }{
+	{"byte_string", "bytes"},
+	{"complex128", "(1+2i)"},
+	{"complex64", "(3-4i)"},
+	{"float32", "1.5"},
+	{"int", "-1"},
+	{"int32", "-32"},
+	{"int16", "-16"},
+	{"int8", "-8"},
+	{"uint", "1"},
+	{"uint32", "32"},
+	{"uint16", "16"},
+	{"uint8", "8"},
+	{"uintptr", "7"},
+}
+
+// everyTypeArray are the fmt forms of everyType's array elements, except
+// its time, object and nested array
+var everyTypeArray = map[int]string{
+	0: "true", 1: "bytes", 2: "(1+2i)", 3: "(3-4i)", 4: "1", 5: "2.5", 6: "1.5",
+	7: "-1", 8: "-64", 9: "-32", 10: "-16", 11: "-8",
+	13: "1", 14: "64", 15: "32", 16: "16", 17: "8", 18: "7",
+}
+
+func TestMsgpackEncoderTypes(t *testing.T) {
+	encoder := newMsgpackEncoder(encoderConfig())
+	buf, err := encoder.EncodeEntry(zapcore.Entry{Message: "types"}, []zapcore.Field{
+		zap.Object("all", everyType{}),
+		zap.Reflect("unencodable", func() {}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := decodeMsgpack(t, buf.Bytes())[0]
+
+	all := entry["all"].(map[string]interface{})
+	for _, field := range everyTypeValues {
+		if got := fmt.Sprint(all[field.key]); got != field.value {
+			t.Errorf("Expected %s=%s, got %s", field.key, field.value, got)
+		}
+	}
+	if timestamp, ok := all["time"].(time.Time); !ok || !timestamp.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Expected a timestamp, got %v", all["time"])
+	}
+	reflected := all["reflected"].(map[string]interface{})
+	if fmt.Sprint(reflected["ids"]) != "[1 2]" || reflected["ratio"] != 0.5 || reflected["ok"] != true || reflected["none"] != nil {
+		t.Errorf("Expected the reflected value decoded from JSON, got %v", reflected)
+	}
+
+	array := all["array"].([]interface{})
+	for i, value := range everyTypeArray {
+		if got := fmt.Sprint(array[i]); got != value {
+			t.Errorf("Expected array[%d]=%s, got %s", i, value, got)
+		}
+	}
+	if timestamp, ok := array[12].(time.Time); !ok || !timestamp.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Expected a timestamp element, got %v", array[12])
+	}
+	if fmt.Sprint(array[19]) != "[x]" || array[20].(map[string]interface{})["name"] != "outer" || fmt.Sprint(array[21]) != "[nested]" {
+		t.Errorf("Expected reflected, object and array elements, got %v", array[19:])
+	}
+
+	if _, ok := entry["unencodable"]; !ok || entry["unencodableError"] == nil {
+		t.Errorf("Expected nil and an error for an unencodable value, got %v", entry)
+	}
+}
+
+func TestMsgpackEncoderSizes(t *testing.T) {
+	ints := []int64{-1, -100, -1000, -100000, -10000000000, 200, 1000, 100000, 10000000000}
+	uints := []uint64{1, 200, 1000, 100000, 10000000000}
+	strs := []string{"s", strings.Repeat("s", 100), strings.Repeat("s", 1000), strings.Repeat("s", 70000)}
+	bins := [][]byte{[]byte("b"), bytes.Repeat([]byte("b"), 1000), bytes.Repeat([]byte("b"), 70000)}
+	many := make([]string, 70000)
+
+	fields := []zapcore.Field{zap.Bool("false", false), zap.Strings("many", many)}
+	for i, value := range ints {
+		fields = append(fields, zap.Int64(fmt.Sprintf("int%d", i), value))
+	}
+	for i, value := range uints {
+		fields = append(fields, zap.Uint64(fmt.Sprintf("uint%d", i), value))
+	}
+	for i, value := range strs {
+		fields = append(fields, zap.String(fmt.Sprintf("str%d", i), value))
+	}
+	for i, value := range bins {
+		fields = append(fields, zap.Binary(fmt.Sprintf("bin%d", i), value))
+	}
+
+	buf, err := newMsgpackEncoder(encoderConfig()).EncodeEntry(zapcore.Entry{Message: "sizes"}, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := decodeMsgpack(t, buf.Bytes())[0]
+
+	if entry["false"] != false || len(entry["many"].([]interface{})) != len(many) {
+		t.Errorf("Expected false and %d elements", len(many))
+	}
+	for i, value := range ints {
+		if got := fmt.Sprint(entry[fmt.Sprintf("int%d", i)]); got != fmt.Sprint(value) {
+			t.Errorf("Expected int %d, got %s", value, got)
+		}
+	}
+	for i, value := range uints {
+		if got := fmt.Sprint(entry[fmt.Sprintf("uint%d", i)]); got != fmt.Sprint(value) {
+			t.Errorf("Expected uint %d, got %s", value, got)
+		}
+	}
+	for i, value := range strs {
+		if entry[fmt.Sprintf("str%d", i)] != value {
+			t.Errorf("Expected a string of length %d", len(value))
+		}
+	}
+	for i, value := range bins {
+		if got, _ := entry[fmt.Sprintf("bin%d", i)].([]byte); !bytes.Equal(got, value) {
+			t.Errorf("Expected binary of length %d, got %d", len(value), len(got))
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewNop returns a Logger that discards every entry, for libraries that
+// take a *Logger and for tests and benchmarks that want no output. It is
+// independent of the singleton, which it neither requires nor changes.
+// Like zap's no-op logger, Fatal still runs OnFatal hooks and exits.
+func NewNop() *Logger {
+	return &Logger{
+		zap:          zap.NewNop(),
+		hooks:        &hookChain{},
+		transformers: &transformerSet{},
+		fatalHooks:   &fatalHooks{},
+		level:        zap.NewAtomicLevelAt(zapcore.InvalidLevel),
+	}
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestNewNop(t *testing.T) {
+	instance = nil
+	once = sync.Once{}
+	ctx := context.Background()
+
+	logger := NewNop()
+	child := logger.With(LogContext{"component": "db"})
+	child.Info(ctx, "info", nil)
+	child.Error(ctx, "error", LogContext{"error": errors.New("failed")})
+	child.Log(ctx, LevelWARN, TypeJob, "job", nil)
+	logger.SetLevel(LevelDEBUG, "test")
+	logger.Debug(ctx, "debug", nil)
+	logger.LogStartup(ctx)
+	if err := logger.RecordAudit(ctx, AuditEvent{Actor: "u1", Action: "order.delete", Resource: "order", Outcome: AuditSuccess}); err != nil {
+		t.Errorf("Expected nop audit to succeed, got %v", err)
+	}
+	if err := logger.Shutdown(ctx, "test"); err != nil {
+		t.Errorf("Expected nop shutdown to succeed, got %v", err)
+	}
+
+	if instance != nil {
+		t.Error("Expected NewNop not to initialize the singleton")
+	}
+}
@@ -0,0 +1,375 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+)
+
+var otlpPool = buffer.NewPool()
+
+// otlpEncoder encodes entries as OTLP LogRecord messages, each prefixed
+// with its varint length (as read by protodelim.UnmarshalFrom). The
+// message is the body and the level the severity; trace_id and span_id
+// become the record's trace context, and every other field an attribute.
+type otlpEncoder struct {
+	cfg *zapcore.EncoderConfig
+	*otlpAttributes
+}
+
+func newOTLPEncoder(cfg zapcore.EncoderConfig) *otlpEncoder {
+	return &otlpEncoder{cfg: &cfg, otlpAttributes: &otlpAttributes{}}
+}
+
+func (e *otlpEncoder) Clone() zapcore.Encoder {
+	return &otlpEncoder{cfg: e.cfg, otlpAttributes: e.otlpAttributes.clone()}
+}
+
+func (e *otlpEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	// Copy the context attributes, deeply only if fields go into an open
+	// namespace
+	attrs := &otlpAttributes{attrs: append([]*commonpb.KeyValue(nil), e.attrs...)}
+	if len(e.namespaces) > 0 {
+		attrs = e.otlpAttributes.clone()
+	}
+
+	if entry.LoggerName != "" && e.cfg.NameKey != "" {
+		attrs.AddString(e.cfg.NameKey, entry.LoggerName)
+	}
+	if entry.Caller.Defined && e.cfg.CallerKey != "" {
+		attrs.AddString(e.cfg.CallerKey, entry.Caller.TrimmedPath())
+	}
+	for _, field := range fields {
+		field.AddTo(attrs)
+	}
+	attrs.namespaces = nil
+	if entry.Stack != "" && e.cfg.StacktraceKey != "" {
+		attrs.AddString(e.cfg.StacktraceKey, entry.Stack)
+	}
+
+	timestamp := uint64(entry.Time.UnixNano())
+	record := &logspb.LogRecord{
+		TimeUnixNano:         timestamp,
+		ObservedTimeUnixNano: timestamp,
+		SeverityNumber:       otlpSeverity(entry.Level),
+		SeverityText:         entry.Level.CapitalString(),
+		Body:                 otlpString(entry.Message),
+	}
+
+	// Move the trace context out of the attributes
+	record.Attributes = attrs.attrs[:0]
+	for _, attr := range attrs.attrs {
+		switch attr.Key {
+		case "trace_id":
+			if id, err := hex.DecodeString(attr.Value.GetStringValue()); err == nil && len(id) == 16 {
+				record.TraceId = id
+				continue
+			}
+		case "span_id":
+			if id, err := hex.DecodeString(attr.Value.GetStringValue()); err == nil && len(id) == 8 {
+				record.SpanId = id
+				continue
+			}
+		}
+		record.Attributes = append(record.Attributes, attr)
+	}
+
+	buf := otlpPool.Get()
+	if _, err := protodelim.MarshalTo(buf, record); err != nil {
+		buf.Free()
+		return nil, err
+	}
+	return buf, nil
+}
+
+// otlpSeverity maps a zap level to the OTLP severity number
+func otlpSeverity(level zapcore.Level) logspb.SeverityNumber {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG
+	case level == zapcore.InfoLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+	case level == zapcore.WarnLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+	case level == zapcore.ErrorLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR
+	default:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_FATAL
+	}
+}
+
+// otlpAttributes collects fields as OTLP key-values. Fields added after
+// OpenNamespace go into a nested key-value list.
+type otlpAttributes struct {
+	attrs []*commonpb.KeyValue
+	// namespaces holds the open namespaces, innermost last
+	namespaces []*commonpb.KeyValueList
+}
+
+// clone copies the attributes deeply, keeping the open namespaces
+func (a *otlpAttributes) clone() *otlpAttributes {
+	clone := &otlpAttributes{attrs: make([]*commonpb.KeyValue, len(a.attrs))}
+	for i, attr := range a.attrs {
+		clone.attrs[i] = proto.Clone(attr).(*commonpb.KeyValue)
+	}
+
+	// Find the copies of the open namespaces, each the last attribute of
+	// the previous one
+	list := clone.attrs
+	for range a.namespaces {
+		namespace := list[len(list)-1].Value.GetKvlistValue()
+		clone.namespaces = append(clone.namespaces, namespace)
+		list = namespace.Values
+	}
+	return clone
+}
+
+// add appends a key-value to the innermost namespace
+func (a *otlpAttributes) add(key string, value *commonpb.AnyValue) {
+	attr := &commonpb.KeyValue{Key: key, Value: value}
+	if n := len(a.namespaces); n > 0 {
+		a.namespaces[n-1].Values = append(a.namespaces[n-1].Values, attr)
+		return
+	}
+	a.attrs = append(a.attrs, attr)
+}
+
+func (a *otlpAttributes) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	array := &otlpArray{}
+	err := marshaler.MarshalLogArray(array)
+	a.add(key, array.value())
+	return err
+}
+
+func (a *otlpAttributes) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	object := &otlpAttributes{}
+	err := marshaler.MarshalLogObject(object)
+	a.add(key, object.value())
+	return err
+}
+
+func (a *otlpAttributes) AddBinary(key string, value []byte) {
+	a.add(key, otlpBytes(value))
+}
+
+func (a *otlpAttributes) AddByteString(key string, value []byte) {
+	a.add(key, otlpString(string(value)))
+}
+
+func (a *otlpAttributes) AddBool(key string, value bool) {
+	a.add(key, otlpBool(value))
+}
+
+func (a *otlpAttributes) AddComplex128(key string, value complex128) {
+	a.add(key, otlpString(strconv.FormatComplex(value, 'g', -1, 128)))
+}
+
+func (a *otlpAttributes) AddComplex64(key string, value complex64) {
+	a.add(key, otlpString(strconv.FormatComplex(complex128(value), 'g', -1, 64)))
+}
+
+// AddDuration adds durations in seconds, like the JSON encoder
+func (a *otlpAttributes) AddDuration(key string, value time.Duration) {
+	a.add(key, otlpDouble(value.Seconds()))
+}
+
+func (a *otlpAttributes) AddFloat64(key string, value float64) {
+	a.add(key, otlpDouble(value))
+}
+
+func (a *otlpAttributes) AddFloat32(key string, value float32) {
+	a.add(key, otlpDouble(float64(value)))
+}
+
+func (a *otlpAttributes) AddInt(key string, value int)     { a.AddInt64(key, int64(value)) }
+func (a *otlpAttributes) AddInt32(key string, value int32) { a.AddInt64(key, int64(value)) }
+func (a *otlpAttributes) AddInt16(key string, value int16) { a.AddInt64(key, int64(value)) }
+func (a *otlpAttributes) AddInt8(key string, value int8)   { a.AddInt64(key, int64(value)) }
+
+func (a *otlpAttributes) AddInt64(key string, value int64) {
+	a.add(key, otlpInt(value))
+}
+
+func (a *otlpAttributes) AddString(key, value string) {
+	a.add(key, otlpString(value))
+}
+
+// AddTime adds times as RFC 3339 strings, as OTLP has no time value
+func (a *otlpAttributes) AddTime(key string, value time.Time) {
+	a.add(key, otlpString(value.Format(time.RFC3339Nano)))
+}
+
+func (a *otlpAttributes) AddUint(key string, value uint)       { a.AddUint64(key, uint64(value)) }
+func (a *otlpAttributes) AddUint32(key string, value uint32)   { a.AddUint64(key, uint64(value)) }
+func (a *otlpAttributes) AddUint16(key string, value uint16)   { a.AddUint64(key, uint64(value)) }
+func (a *otlpAttributes) AddUint8(key string, value uint8)     { a.AddUint64(key, uint64(value)) }
+func (a *otlpAttributes) AddUintptr(key string, value uintptr) { a.AddUint64(key, uint64(value)) }
+
+// AddUint64 adds values beyond int64 as strings, as OTLP has no unsigned
+// value
+func (a *otlpAttributes) AddUint64(key string, value uint64) {
+	a.add(key, otlpUint(value))
+}
+
+func (a *otlpAttributes) AddReflected(key string, value interface{}) error {
+	converted, err := otlpReflected(value)
+	a.add(key, converted)
+	return err
+}
+
+func (a *otlpAttributes) OpenNamespace(key string) {
+	namespace := &commonpb.KeyValueList{}
+	a.add(key, &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: namespace}})
+	a.namespaces = append(a.namespaces, namespace)
+}
+
+// value returns the attributes as a key-value list value
+func (a *otlpAttributes) value() *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: &commonpb.KeyValueList{Values: a.attrs}}}
+}
+
+// otlpArray collects array elements as OTLP values
+type otlpArray struct {
+	values []*commonpb.AnyValue
+}
+
+func (a *otlpArray) value() *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: a.values}}}
+}
+
+func (a *otlpArray) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	array := &otlpArray{}
+	err := marshaler.MarshalLogArray(array)
+	a.values = append(a.values, array.value())
+	return err
+}
+
+func (a *otlpArray) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	object := &otlpAttributes{}
+	err := marshaler.MarshalLogObject(object)
+	a.values = append(a.values, object.value())
+	return err
+}
+
+func (a *otlpArray) AppendReflected(value interface{}) error {
+	converted, err := otlpReflected(value)
+	a.values = append(a.values, converted)
+	return err
+}
+
+func (a *otlpArray) AppendBool(value bool) { a.values = append(a.values, otlpBool(value)) }
+func (a *otlpArray) AppendByteString(value []byte) {
+	a.values = append(a.values, otlpString(string(value)))
+}
+func (a *otlpArray) AppendComplex128(value complex128) {
+	a.AppendString(strconv.FormatComplex(value, 'g', -1, 128))
+}
+func (a *otlpArray) AppendComplex64(value complex64) {
+	a.AppendString(strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+func (a *otlpArray) AppendDuration(value time.Duration) { a.AppendFloat64(value.Seconds()) }
+func (a *otlpArray) AppendFloat64(value float64)        { a.values = append(a.values, otlpDouble(value)) }
+func (a *otlpArray) AppendFloat32(value float32)        { a.AppendFloat64(float64(value)) }
+func (a *otlpArray) AppendInt(value int)                { a.AppendInt64(int64(value)) }
+func (a *otlpArray) AppendInt64(value int64)            { a.values = append(a.values, otlpInt(value)) }
+func (a *otlpArray) AppendInt32(value int32)            { a.AppendInt64(int64(value)) }
+func (a *otlpArray) AppendInt16(value int16)            { a.AppendInt64(int64(value)) }
+func (a *otlpArray) AppendInt8(value int8)              { a.AppendInt64(int64(value)) }
+func (a *otlpArray) AppendString(value string)          { a.values = append(a.values, otlpString(value)) }
+func (a *otlpArray) AppendTime(value time.Time)         { a.AppendString(value.Format(time.RFC3339Nano)) }
+func (a *otlpArray) AppendUint(value uint)              { a.AppendUint64(uint64(value)) }
+func (a *otlpArray) AppendUint64(value uint64)          { a.values = append(a.values, otlpUint(value)) }
+func (a *otlpArray) AppendUint32(value uint32)          { a.AppendUint64(uint64(value)) }
+func (a *otlpArray) AppendUint16(value uint16)          { a.AppendUint64(uint64(value)) }
+func (a *otlpArray) AppendUint8(value uint8)            { a.AppendUint64(uint64(value)) }
+func (a *otlpArray) AppendUintptr(value uintptr)        { a.AppendUint64(uint64(value)) }
+
+func otlpString(value string) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}
+}
+
+func otlpBool(value bool) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: value}}
+}
+
+func otlpInt(value int64) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: value}}
+}
+
+func otlpUint(value uint64) *commonpb.AnyValue {
+	if value > 1<<63-1 {
+		return otlpString(strconv.FormatUint(value, 10))
+	}
+	return otlpInt(int64(value))
+}
+
+func otlpDouble(value float64) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: value}}
+}
+
+func otlpBytes(value []byte) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: append([]byte(nil), value...)}}
+}
+
+// otlpReflected converts value as the JSON encoder would serialize it. If
+// it cannot be serialized, an empty value is returned with the error.
+func otlpReflected(value interface{}) (*commonpb.AnyValue, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return &commonpb.AnyValue{}, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return &commonpb.AnyValue{}, err
+	}
+	return otlpDecoded(decoded), nil
+}
+
+// otlpDecoded converts a value decoded from JSON
+func otlpDecoded(value interface{}) *commonpb.AnyValue {
+	switch v := value.(type) {
+	case bool:
+		return otlpBool(v)
+	case string:
+		return otlpString(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return otlpInt(i)
+		}
+		f, _ := v.Float64()
+		return otlpDouble(f)
+	case []interface{}:
+		array := &otlpArray{}
+		for _, elem := range v {
+			array.values = append(array.values, otlpDecoded(elem))
+		}
+		return array.value()
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		object := &otlpAttributes{}
+		for _, key := range keys {
+			object.add(key, otlpDecoded(v[key]))
+		}
+		return object.value()
+	default:
+		return &commonpb.AnyValue{}
+	}
+}
@@ -0,0 +1,215 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+// decodeOTLP decodes a stream of length-prefixed LogRecords
+func decodeOTLP(t *testing.T, data []byte) []*logspb.LogRecord {
+	t.Helper()
+	reader := bufio.NewReader(bytes.NewReader(data))
+	var records []*logspb.LogRecord
+	for {
+		record := &logspb.LogRecord{}
+		err := protodelim.UnmarshalFrom(reader, record)
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Decoding LogRecord: %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+// otlpAttributeMap indexes attributes by key
+func otlpAttributeMap(attrs []*commonpb.KeyValue) map[string]*commonpb.AnyValue {
+	values := make(map[string]*commonpb.AnyValue, len(attrs))
+	for _, attr := range attrs {
+		values[attr.Key] = attr.Value
+	}
+	return values
+}
+
+func TestOTLPEncoding(t *testing.T) {
+	var output bytes.Buffer
+	logger := New(Config{ServiceName: "otlp-test", Level: LevelINFO, Encoding: EncodingOTLP, Output: &output})
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+
+	logger.Info(ctx, "Order created", LogContext{
+		"order_id": "ord-123",
+		"items":    3,
+		"total":    99.5,
+		"paid":     true,
+		"timeout":  1500 * time.Millisecond,
+		"lines":    []map[string]interface{}{{"sku": "A", "qty": 2}},
+	})
+	logger.Error(context.Background(), "Payment failed", LogContext{"error": errors.New("declined")})
+
+	records := decodeOTLP(t, output.Bytes())
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+
+	record := records[0]
+	if record.Body.GetStringValue() != "Order created" || record.SeverityNumber != logspb.SeverityNumber_SEVERITY_NUMBER_INFO || record.SeverityText != "INFO" {
+		t.Errorf("Unexpected record: %v", record)
+	}
+	if time.Since(time.Unix(0, int64(record.TimeUnixNano))) > time.Minute {
+		t.Errorf("Expected a timestamp, got %d", record.TimeUnixNano)
+	}
+	traceID, spanID := spanContext.TraceID(), spanContext.SpanID()
+	if !bytes.Equal(record.TraceId, traceID[:]) || !bytes.Equal(record.SpanId, spanID[:]) {
+		t.Errorf("Expected trace context, got %x %x", record.TraceId, record.SpanId)
+	}
+
+	attrs := otlpAttributeMap(record.Attributes)
+	if _, ok := attrs["trace_id"]; ok {
+		t.Error("Expected trace_id to move out of the attributes")
+	}
+	if attrs["service.name"].GetStringValue() != "otlp-test" || attrs["log_type"].GetStringValue() != "normal" ||
+		attrs["order_id"].GetStringValue() != "ord-123" || attrs["items"].GetIntValue() != 3 ||
+		attrs["total"].GetDoubleValue() != 99.5 || !attrs["paid"].GetBoolValue() || attrs["timeout"].GetDoubleValue() != 1.5 {
+		t.Errorf("Unexpected attributes: %v", record.Attributes)
+	}
+	line := attrs["lines"].GetArrayValue().GetValues()[0].GetKvlistValue()
+	if values := otlpAttributeMap(line.GetValues()); values["sku"].GetStringValue() != "A" || values["qty"].GetIntValue() != 2 {
+		t.Errorf("Expected nested lines, got %v", attrs["lines"])
+	}
+
+	errorAttrs := otlpAttributeMap(records[1].Attributes)
+	if records[1].SeverityNumber != logspb.SeverityNumber_SEVERITY_NUMBER_ERROR || errorAttrs["error_message"].GetStringValue() != "declined" {
+		t.Errorf("Unexpected error record: %v", records[1])
+	}
+}
+
+func TestOTLPEncoderNamespaces(t *testing.T) {
+	encoder := newOTLPEncoder(encoderConfig())
+	zap.Namespace("request").AddTo(encoder)
+	zap.String("id", "req-1").AddTo(encoder)
+
+	clone := encoder.Clone()
+	zap.Int("attempt", 2).AddTo(clone)
+
+	buf, err := clone.EncodeEntry(zapcore.Entry{Level: zapcore.WarnLevel, Message: "retry", Stack: "stack"}, []zapcore.Field{
+		zap.Uint64("big", 1<<63),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := decodeOTLP(t, buf.Bytes())[0]
+
+	attrs := otlpAttributeMap(record.Attributes)
+	request := otlpAttributeMap(attrs["request"].GetKvlistValue().GetValues())
+	if request["id"].GetStringValue() != "req-1" || request["attempt"].GetIntValue() != 2 || request["big"].GetStringValue() != "9223372036854775808" {
+		t.Errorf("Expected fields nested in the namespace, got %v", request)
+	}
+	if attrs["stacktrace"].GetStringValue() != "stack" {
+		t.Errorf("Expected the stack trace outside the namespace, got %v", record.Attributes)
+	}
+
+	original, _ := encoder.EncodeEntry(zapcore.Entry{Message: "original"}, nil)
+	request = otlpAttributeMap(otlpAttributeMap(decodeOTLP(t, original.Bytes())[0].Attributes)["request"].GetKvlistValue().GetValues())
+	if _, ok := request["attempt"]; ok {
+		t.Error("Expected the clone's fields not to leak into the original")
+	}
+}
+
+// otlpValueString returns the fmt form of a scalar OTLP value
+func otlpValueString(value *commonpb.AnyValue) string {
+	switch v := value.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return fmt.Sprint(v.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return fmt.Sprint(v.IntValue)
+	case *commonpb.AnyValue_DoubleValue:
+		return fmt.Sprint(v.DoubleValue)
+	}
+	return fmt.Sprint(value)
+}
+
+func TestOTLPEncoderTypes(t *testing.T) {
+	encoder := newOTLPEncoder(encoderConfig())
+	buf, err := encoder.EncodeEntry(zapcore.Entry{Message: "types"}, []zapcore.Field{
+		zap.Object("all", everyType{}),
+		zap.Binary("raw", []byte{1, 2}),
+		zap.Reflect("unencodable", func() {}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs := otlpAttributeMap(decodeOTLP(t, buf.Bytes())[0].Attributes)
+
+	all := otlpAttributeMap(attrs["all"].GetKvlistValue().GetValues())
+	for _, field := range everyTypeValues {
+		if got := otlpValueString(all[field.key]); got != field.value {
+			t.Errorf("Expected %s=%s, got %s", field.key, field.value, got)
+		}
+	}
+	if got := all["time"].GetStringValue(); got != time.Unix(1700000000, 0).Format(time.RFC3339Nano) {
+		t.Errorf("Expected an RFC 3339 time, got %s", got)
+	}
+	reflected := otlpAttributeMap(all["reflected"].GetKvlistValue().GetValues())
+	if len(reflected["ids"].GetArrayValue().GetValues()) != 2 || reflected["ratio"].GetDoubleValue() != 0.5 ||
+		!reflected["ok"].GetBoolValue() || reflected["none"].GetValue() != nil {
+		t.Errorf("Expected the reflected value converted from JSON, got %v", reflected)
+	}
+	if object := otlpAttributeMap(all["object"].GetKvlistValue().GetValues()); object["name"].GetStringValue() != "outer" {
+		t.Errorf("Expected the nested object, got %v", object)
+	}
+
+	array := all["array"].GetArrayValue().GetValues()
+	for i, value := range everyTypeArray {
+		if got := otlpValueString(array[i]); got != value {
+			t.Errorf("Expected array[%d]=%s, got %s", i, value, got)
+		}
+	}
+	if array[19].GetArrayValue().GetValues()[0].GetStringValue() != "x" ||
+		otlpAttributeMap(array[20].GetKvlistValue().GetValues())["name"].GetStringValue() != "outer" ||
+		array[21].GetArrayValue().GetValues()[0].GetStringValue() != "nested" {
+		t.Errorf("Expected reflected, object and array elements, got %v", array[19:])
+	}
+
+	if !bytes.Equal(attrs["raw"].GetBytesValue(), []byte{1, 2}) {
+		t.Errorf("Expected bytes, got %v", attrs["raw"])
+	}
+	if attrs["unencodable"] == nil || attrs["unencodableError"] == nil {
+		t.Errorf("Expected an empty value and an error for an unencodable value, got %v", attrs)
+	}
+}
+
+func TestOTLPSeverity(t *testing.T) {
+	severities := map[zapcore.Level]logspb.SeverityNumber{
+		zapcore.DebugLevel: logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG,
+		zapcore.InfoLevel:  logspb.SeverityNumber_SEVERITY_NUMBER_INFO,
+		zapcore.WarnLevel:  logspb.SeverityNumber_SEVERITY_NUMBER_WARN,
+		zapcore.ErrorLevel: logspb.SeverityNumber_SEVERITY_NUMBER_ERROR,
+		zapcore.PanicLevel: logspb.SeverityNumber_SEVERITY_NUMBER_FATAL,
+	}
+	for level, want := range severities {
+		if got := otlpSeverity(level); got != want {
+			t.Errorf("otlpSeverity(%s) = %s, want %s", level, got, want)
+		}
+	}
+}
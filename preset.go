@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"strings"
+	"time"
+)
+
+// Preset environments for ConfigForEnv
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// ConfigForEnv returns the preset Config for env, to be completed with
+// ServiceName and any overrides before calling Initialize:
+//
+//   - development (also "dev" and "local"): console output, DEBUG, caller
+//     and stack traces on
+//   - any other environment (production, staging, ...): JSON output, INFO
+//     and sampling of repeated entries
+func ConfigForEnv(env string) Config {
+	switch strings.ToLower(env) {
+	case EnvDevelopment, "dev", "local":
+		return Config{
+			Env:          env,
+			Level:        LevelDEBUG,
+			Encoding:     EncodingConsole,
+			EnableCaller: true,
+			StackTraces:  true,
+		}
+	default:
+		if env == "" {
+			env = EnvProduction
+		}
+		return Config{
+			Env:      env,
+			Level:    LevelINFO,
+			Encoding: EncodingJSON,
+			Sampling: &SamplingOptions{Initial: 100, Thereafter: 100, Tick: time.Second},
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestConfigForEnv(t *testing.T) {
+	development := ConfigForEnv("dev")
+	if development.Env != "dev" || development.Level != LevelDEBUG || development.Encoding != EncodingConsole ||
+		!development.EnableCaller || !development.StackTraces || development.Sampling != nil {
+		t.Errorf("Unexpected development preset: %+v", development)
+	}
+
+	production := ConfigForEnv("")
+	if production.Env != EnvProduction || production.Level != LevelINFO || production.Encoding != EncodingJSON ||
+		production.EnableCaller || production.Sampling == nil {
+		t.Errorf("Unexpected production preset: %+v", production)
+	}
+
+	if staging := ConfigForEnv("staging"); staging.Env != "staging" || staging.Sampling == nil {
+		t.Errorf("Expected staging to use the production preset, got %+v", staging)
+	}
+}
+
+func TestConsoleEncoding(t *testing.T) {
+	logger := &Logger{config: Config{Encoding: EncodingConsole}}
+	buf, err := logger.newOutputEncoder().EncodeEntry(zapcore.Entry{Level: zapcore.InfoLevel, Time: time.Now(), Message: "hello"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line := buf.String(); strings.HasPrefix(line, "{") || !strings.Contains(line, "INFO\thello") {
+		t.Errorf("Expected console output, got %q", line)
+	}
+}
+
+func TestEpochMillisTimeFormat(t *testing.T) {
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Time: time.UnixMilli(1705314600123).UTC(), Message: "hello"}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"", `"@timestamp":"2024-01-15T10:30:00.123Z"`},
+		{TimeFormatISO8601, `"@timestamp":"2024-01-15T10:30:00.123Z"`},
+		{TimeFormatEpochMillis, `"@timestamp":1705314600123`},
+	}
+
+	for _, tt := range tests {
+		logger := &Logger{config: Config{TimeFormat: tt.format}}
+		buf, err := logger.newOutputEncoder().EncodeEntry(entry, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line := buf.String(); !strings.Contains(line, tt.expected) {
+			t.Errorf("Expected %s for format %q, got %q", tt.expected, tt.format, line)
+		}
+	}
+}
+
+func TestStackTraces(t *testing.T) {
+	logger, observedLogs := setupCallerLogger(Config{ServiceName: "stack-test", Level: LevelDEBUG, StackTraces: true})
+
+	logger.Warn(context.Background(), "warning", nil)
+	logger.Error(context.Background(), "failure", nil)
+
+	entries := observedLogs.All()
+	if entries[0].Stack != "" {
+		t.Errorf("Expected no stack trace below ERROR, got %q", entries[0].Stack)
+	}
+	if !strings.HasPrefix(entries[1].Stack, "github.com/rcommerz/logger-go.TestStackTraces") {
+		t.Errorf("Expected stack trace to start at the logging call, got %q", entries[1].Stack)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	metrics := newRecordedMetrics()
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName: "sampling-test",
+		Level:       LevelDEBUG,
+		Metrics:     metrics,
+		Sampling:    &SamplingOptions{Initial: 2, Thereafter: 3, Tick: time.Hour},
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 8; i++ {
+		logger.Info(ctx, "repeated", nil)
+		logger.Error(ctx, "failure", nil)
+		logger.Audit(ctx, "audited", nil)
+	}
+	logger.Info(ctx, "other", nil)
+
+	// 1st, 2nd, 5th and 8th are kept
+	if n := observedLogs.FilterMessage("repeated").Len(); n != 4 {
+		t.Errorf("Expected 4 sampled entries, got %d", n)
+	}
+	if observedLogs.FilterMessage("failure").Len() != 8 || observedLogs.FilterMessage("audited").Len() != 8 {
+		t.Error("Expected ERROR and audit entries not to be sampled")
+	}
+	if metrics.dropped["sampled"] != 4 {
+		t.Errorf("Expected 4 sampled drops, got %d", metrics.dropped["sampled"])
+	}
+	if observedLogs.FilterMessage("other").Len() != 1 {
+		t.Error("Expected messages to be sampled independently")
+	}
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrivacyMode selects how personal data is removed in privacy mode
+type PrivacyMode string
+
+const (
+	// PrivacyDrop removes personal-data fields from entries
+	PrivacyDrop PrivacyMode = "drop"
+	// PrivacyHash replaces personal-data values with salted hashes (see
+	// Config.SensitiveSalt) so entries can still be correlated
+	PrivacyHash PrivacyMode = "hash"
+)
+
+// DefaultPersonalDataKeys are the keys handled by privacy mode when
+// PrivacyOptions.Keys is empty
+var DefaultPersonalDataKeys = []string{
+	"user_id",
+	"user.id",
+	"ip",
+	"client.address",
+	"forwarded_for",
+	"email",
+	"user.email",
+	"phone",
+}
+
+// PrivacyOptions configures privacy mode for data-minimization requirements
+// such as GDPR
+type PrivacyOptions struct {
+	// Mode is PrivacyDrop (default) or PrivacyHash
+	Mode PrivacyMode
+	// Keys lists the personal-data keys, matched case-insensitively at any
+	// depth (default DefaultPersonalDataKeys)
+	Keys []string
+}
+
+// privacyFilter drops or hashes personal-data fields
+type privacyFilter struct {
+	hash bool
+	keys map[string]struct{}
+	salt []byte
+}
+
+func newPrivacyFilter(opts *PrivacyOptions, salt []byte) *privacyFilter {
+	keys := opts.Keys
+	if len(keys) == 0 {
+		keys = DefaultPersonalDataKeys
+	}
+	return &privacyFilter{
+		hash: opts.Mode == PrivacyHash,
+		keys: nameSet(keys),
+		salt: salt,
+	}
+}
+
+// hashPersonalData hashes a personal-data value, element-wise for lists
+// such as the forwarded_for chain
+func hashPersonalData(value interface{}, salt []byte) interface{} {
+	if list, ok := value.([]string); ok {
+		hashed := make([]string, len(list))
+		for i, item := range list {
+			hashed[i] = sensitiveValue{value: item, salt: salt}.String()
+		}
+		return hashed
+	}
+	return sensitiveValue{value: fmt.Sprint(value), salt: salt}.String()
+}
+
+// apply returns a copy of context without personal data
+func (f *privacyFilter) apply(context LogContext) LogContext {
+	filtered := make(LogContext, len(context))
+	for key, value := range context {
+		if _, ok := f.keys[strings.ToLower(key)]; ok {
+			if f.hash {
+				filtered[key] = hashPersonalData(value, f.salt)
+			}
+			continue
+		}
+
+		switch v := value.(type) {
+		case LogContext:
+			filtered[key] = f.apply(v)
+		case map[string]interface{}:
+			filtered[key] = map[string]interface{}(f.apply(v))
+		default:
+			filtered[key] = value
+		}
+	}
+	return filtered
+}
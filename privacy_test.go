@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPrivacyMode(t *testing.T) {
+	t.Run("should drop personal data keys", func(t *testing.T) {
+		logger, observedLogs := setupObservedLogger(Config{
+			ServiceName:    "privacy-drop-test",
+			ServiceVersion: "1.0.0",
+			Env:            "test",
+			Level:          LevelDEBUG,
+			Privacy:        &PrivacyOptions{},
+		})
+
+		logger.With(LogContext{"user_id": "u-1"}).Info(context.Background(), "signup", LogContext{
+			"IP":      "203.0.113.7",
+			"plan":    "pro",
+			"profile": map[string]interface{}{"email": "jane@example.com", "country": "DE"},
+		})
+
+		fields := observedLogs.TakeAll()[0].ContextMap()
+		for _, key := range []string{"user_id", "IP"} {
+			if _, ok := fields[key]; ok {
+				t.Errorf("Expected %s to be dropped", key)
+			}
+		}
+		profile := fields["profile"].(map[string]interface{})
+		if _, ok := profile["email"]; ok || profile["country"] != "DE" || fields["plan"] != "pro" {
+			t.Errorf("Expected only personal data to be dropped, got %v", fields)
+		}
+	})
+
+	t.Run("should hash configured keys", func(t *testing.T) {
+		logger, observedLogs := setupObservedLogger(Config{
+			ServiceName:    "privacy-hash-test",
+			ServiceVersion: "1.0.0",
+			Env:            "test",
+			Level:          LevelDEBUG,
+			Privacy:        &PrivacyOptions{Mode: PrivacyHash, Keys: []string{"customer_ref"}},
+		})
+
+		logger.Info(context.Background(), "order", LogContext{"customer_ref": "C-42", "ip": "203.0.113.7"})
+
+		fields := observedLogs.TakeAll()[0].ContextMap()
+		if ref, _ := fields["customer_ref"].(string); !strings.HasPrefix(ref, "hmac:") {
+			t.Errorf("Expected customer_ref to be hashed, got %v", fields["customer_ref"])
+		}
+		if fields["ip"] != "203.0.113.7" {
+			t.Errorf("Expected keys outside the configured set to be kept, got %v", fields["ip"])
+		}
+	})
+}
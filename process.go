@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// containerIDPattern matches the 64-character hex id Docker, containerd
+// and CRI-O use in cgroup paths and container mounts
+var containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// processFields returns process.pid and, when running in a container,
+// container.id
+func processFields() []zap.Field {
+	fields := []zap.Field{zap.Int("process.pid", os.Getpid())}
+	if id := containerID(); id != "" {
+		fields = append(fields, zap.String("container.id", id))
+	}
+	return fields
+}
+
+// containerID reads the container id from the process's cgroup, falling
+// back to its mounts for cgroup v2, where the cgroup path is usually "/"
+func containerID() string {
+	for _, path := range []string{"/proc/self/cgroup", "/proc/self/mountinfo"} {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if id := parseContainerID(string(content)); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// parseContainerID returns the first container id in cgroup or mountinfo
+// content. In mountinfo only container directories are considered, since
+// other mounts (e.g. overlay layers) also carry 64-character ids.
+func parseContainerID(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.Contains(line, " - ") && !strings.Contains(line, "/containers/") {
+			continue
+		}
+		if id := containerIDPattern.FindString(line); id != "" {
+			return id
+		}
+	}
+	return ""
+}
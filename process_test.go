@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestParseContainerID(t *testing.T) {
+	const id = "3f4e2b8c9d0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f6071829304"
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"docker cgroup v1", "12:memory:/docker/" + id + "\n11:cpu:/docker/" + id, id},
+		{"kubernetes cgroup v1", "4:pids:/kubepods/burstable/pod1234/cri-containerd-" + id + ".scope", id},
+		{"cgroup v2 mountinfo", "2345 2300 0:21 / /sys rw - sysfs sysfs rw\n" +
+			"2350 2300 254:1 /var/lib/docker/containers/" + id + "/hostname /etc/hostname rw - ext4 /dev/vda1 rw", id},
+		{"overlay layers are not containers", "2300 2200 0:50 / / rw - overlay overlay rw,lowerdir=/var/lib/docker/overlay2/" + id + "/diff", ""},
+		{"not in a container", "0::/user.slice/user-1000.slice/session-2.scope", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseContainerID(tt.content); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProcessFields(t *testing.T) {
+	fields := processFields()
+	if fields[0].Key != "process.pid" || fields[0].Integer != int64(os.Getpid()) {
+		t.Errorf("Expected process.pid=%d, got %s=%d", os.Getpid(), fields[0].Key, fields[0].Integer)
+	}
+}
+
+func TestGoroutineCount(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "process-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+		GoroutineCount: true,
+	})
+
+	logger.Info(context.Background(), "Worker pool resized", nil)
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(logs))
+	}
+	if goroutines, ok := logs[0].ContextMap()["runtime.goroutines"].(int64); !ok || goroutines < 1 {
+		t.Errorf("Expected runtime.goroutines, got %v", logs[0].ContextMap()["runtime.goroutines"])
+	}
+}
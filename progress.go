@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// localsProgress is the c.Locals key of the ProgressInterval settings
+// read by TrackStream
+const localsProgress = "logger.progress"
+
+// progressOptions carries the ProgressInterval settings of a request to
+// TrackStream
+type progressOptions struct {
+	logger   *Logger
+	start    time.Time
+	interval time.Duration
+}
+
+// progressTracker logs "in progress" entries for a request that is still
+// running or streaming
+type progressTracker struct {
+	logger   *Logger
+	ctx      context.Context
+	method   string
+	path     string
+	start    time.Time
+	interval time.Duration
+	// counted is set when bytes tracks the response body
+	counted bool
+	bytes   atomic.Int64
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	stopped bool
+}
+
+// startProgress logs an entry every interval until stop is called, with
+// the time elapsed since start
+func startProgress(logger *Logger, ctx context.Context, method, path string, start time.Time, interval time.Duration, counted bool) *progressTracker {
+	p := &progressTracker{
+		logger:   logger,
+		ctx:      ctx,
+		method:   method,
+		path:     path,
+		start:    start,
+		interval: interval,
+		counted:  counted,
+	}
+	p.timer = time.AfterFunc(interval, p.tick)
+	return p
+}
+
+func (p *progressTracker) tick() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		return
+	}
+
+	p.logger.HTTP(p.ctx, p.method+" "+p.path+" in progress", p.fields("in_progress"))
+	p.timer.Reset(p.interval)
+}
+
+// stop ends the progress entries
+func (p *progressTracker) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopped = true
+	p.timer.Stop()
+}
+
+// finish ends the progress entries and logs a summary of the stream
+func (p *progressTracker) finish() {
+	p.stop()
+	p.logger.HTTP(p.ctx, p.method+" "+p.path+" stream finished", p.fields("stream_finished"))
+}
+
+func (p *progressTracker) fields(phase string) LogContext {
+	fields := LogContext{
+		"method":        p.method,
+		"path":          p.path,
+		"request_phase": phase,
+		"elapsed_ms":    p.logger.since(p.start).Milliseconds(),
+	}
+	if p.counted {
+		fields["response_bytes"] = p.bytes.Load()
+	}
+	return fields
+}
+
+// progressWriter counts the bytes of a streamed response
+type progressWriter struct {
+	w        *bufio.Writer
+	progress *progressTracker
+}
+
+// Write writes to the connection's buffer and flushes it, since the
+// handler's own Flush only reaches this writer
+func (w progressWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	w.progress.bytes.Add(int64(n))
+	if err != nil {
+		return n, err
+	}
+	return n, w.w.Flush()
+}
+
+// TrackStream wraps the stream writer of a streamed Fiber response (e.g.
+// server-sent events) so that, with MiddlewareOptions.ProgressInterval,
+// in-progress entries with elapsed_ms and response_bytes are logged while
+// it runs and a "stream finished" summary when it returns:
+//
+//	c.Context().SetBodyStreamWriter(logger.TrackStream(c, func(w *bufio.Writer) {
+//		for event := range events {
+//			fmt.Fprintf(w, "data: %s\n\n", event)
+//			w.Flush()
+//		}
+//	}))
+//
+// Without ProgressInterval it returns sw unchanged.
+func TrackStream(c *fiber.Ctx, sw func(w *bufio.Writer)) func(w *bufio.Writer) {
+	opts, ok := c.Locals(localsProgress).(progressOptions)
+	if !ok {
+		return sw
+	}
+
+	// The context, method and path outlive c, which is reused once the
+	// handler returns
+	ctx, method, path := c.UserContext(), c.Method(), c.Path()
+	return func(w *bufio.Writer) {
+		progress := startProgress(opts.logger, ctx, method, path, opts.start, opts.interval, true)
+		defer progress.finish()
+
+		buffered := bufio.NewWriter(progressWriter{w: w, progress: progress})
+		sw(buffered)
+		_ = buffered.Flush()
+	}
+}
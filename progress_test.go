@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestProgressInterval(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "progress-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	phase := func(logs []observer.LoggedEntry, phase string) []map[string]interface{} {
+		var fields []map[string]interface{}
+		for _, entry := range logs {
+			if context := entry.ContextMap(); context["request_phase"] == phase {
+				fields = append(fields, context)
+			}
+		}
+		return fields
+	}
+
+	t.Run("net/http", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		handler := HTTPMiddleware(&HTTPMiddlewareOptions{ProgressInterval: 10 * time.Millisecond})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("data: 1\n\n"))
+			time.Sleep(50 * time.Millisecond)
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/events", nil))
+
+		logs := observedLogs.TakeAll()
+		progress := phase(logs, "in_progress")
+		if len(progress) == 0 {
+			t.Fatalf("Expected in-progress entries, got %v", logs)
+		}
+		if progress[0]["response_bytes"] != int64(9) || progress[0]["path"] != "/events" {
+			t.Errorf("Unexpected progress fields %v", progress[0])
+		}
+		if logs[len(logs)-1].Message != "GET /events 200" {
+			t.Errorf("Expected the completion entry last, got %q", logs[len(logs)-1].Message)
+		}
+	})
+
+	t.Run("fiber stream", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		app := fiber.New()
+		app.Use(FiberMiddleware(&MiddlewareOptions{ProgressInterval: 10 * time.Millisecond}))
+		app.Get("/events", func(c *fiber.Ctx) error {
+			c.Context().SetBodyStreamWriter(TrackStream(c, func(w *bufio.Writer) {
+				_, _ = w.WriteString("data: 1\n\n")
+				_ = w.Flush()
+				time.Sleep(50 * time.Millisecond)
+				_, _ = w.WriteString("data: 2\n\n")
+			}))
+			return nil
+		})
+		resp, err := app.Test(httptest.NewRequest("GET", "/events", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if string(body) != "data: 1\n\ndata: 2\n\n" {
+			t.Errorf("Unexpected body %q", body)
+		}
+
+		logs := observedLogs.TakeAll()
+		progress := phase(logs, "in_progress")
+		if len(progress) == 0 || progress[0]["response_bytes"] != int64(9) {
+			t.Errorf("Expected in-progress entries with response_bytes=9, got %v", progress)
+		}
+		summary := phase(logs, "stream_finished")
+		if len(summary) != 1 || summary[0]["response_bytes"] != int64(18) {
+			t.Errorf("Expected a stream summary with response_bytes=18, got %v", summary)
+		}
+	})
+
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Headers carrying the client address set by proxies
+const (
+	headerForwardedFor = "X-Forwarded-For"
+	headerRealIP       = "X-Real-IP"
+)
+
+// proxyResolver finds the client IP of requests arriving through trusted
+// proxies
+type proxyResolver struct {
+	trusted []*net.IPNet
+	chain   bool
+}
+
+// newProxyResolver parses the trusted proxy IPs and CIDRs, returning nil
+// when there are none. It panics on invalid entries, like other
+// configuration errors found at startup.
+func newProxyResolver(proxies []string, chain bool) *proxyResolver {
+	if len(proxies) == 0 {
+		return nil
+	}
+
+	r := &proxyResolver{chain: chain}
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil {
+				bits := 8 * len(ip.To16())
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 32
+				}
+				r.trusted = append(r.trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
+			}
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			panic(fmt.Sprintf("Invalid trusted proxy %q: expected an IP or CIDR", proxy))
+		}
+		r.trusted = append(r.trusted, network)
+	}
+	return r
+}
+
+// isTrusted reports whether the address belongs to a trusted proxy
+func (r *proxyResolver) isTrusted(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range r.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve returns the client IP of a request from peer, the address of
+// the connection, and its X-Forwarded-For and X-Real-IP headers, which are
+// only believed when peer is a trusted proxy. X-Forwarded-For is read from
+// the right, skipping trusted proxies, so clients cannot spoof it. The
+// chain is the forwarded addresses followed by peer, when recorded.
+func (r *proxyResolver) resolve(peer, forwardedFor, realIP string) (string, []string) {
+	var forwarded []string
+	for _, addr := range strings.Split(forwardedFor, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			forwarded = append(forwarded, addr)
+		}
+	}
+
+	var chain []string
+	if r.chain && len(forwarded) > 0 {
+		chain = append(forwarded[:len(forwarded):len(forwarded)], peer)
+	}
+
+	if !r.isTrusted(peer) {
+		return peer, chain
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		if !r.isTrusted(forwarded[i]) {
+			return forwarded[i], chain
+		}
+	}
+	if len(forwarded) > 0 {
+		return forwarded[0], chain
+	}
+	if realIP = strings.TrimSpace(realIP); realIP != "" {
+		return realIP, chain
+	}
+	return peer, chain
+}
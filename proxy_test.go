@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestProxyResolver(t *testing.T) {
+	resolver := newProxyResolver([]string{"10.0.0.0/8", "192.0.2.10", "2001:db8::/32"}, true)
+
+	tests := []struct {
+		name         string
+		peer         string
+		forwardedFor string
+		realIP       string
+		expectedIP   string
+		chain        []string
+	}{
+		{"untrusted peer ignores headers", "198.51.100.9", "203.0.113.7", "203.0.113.8", "198.51.100.9", []string{"203.0.113.7", "198.51.100.9"}},
+		{"trusted peer without headers", "10.1.2.3", "", "", "10.1.2.3", nil},
+		{"rightmost untrusted forwarded address", "10.1.2.3", "1.1.1.1, 203.0.113.7, 10.0.0.2", "", "203.0.113.7", []string{"1.1.1.1", "203.0.113.7", "10.0.0.2", "10.1.2.3"}},
+		{"all forwarded addresses trusted", "192.0.2.10", "10.0.0.5, 10.0.0.2", "", "10.0.0.5", []string{"10.0.0.5", "10.0.0.2", "192.0.2.10"}},
+		{"x-real-ip fallback", "2001:db8::5", "", "203.0.113.7", "203.0.113.7", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip, chain := resolver.resolve(tt.peer, tt.forwardedFor, tt.realIP)
+			if ip != tt.expectedIP {
+				t.Errorf("Expected ip %s, got %s", tt.expectedIP, ip)
+			}
+			if !reflect.DeepEqual(chain, tt.chain) {
+				t.Errorf("Expected chain %v, got %v", tt.chain, chain)
+			}
+		})
+	}
+
+	if newProxyResolver(nil, true) != nil {
+		t.Error("Expected no resolver without trusted proxies")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for an invalid trusted proxy")
+		}
+	}()
+	newProxyResolver([]string{"not-an-ip"}, false)
+}
+
+func TestMiddlewareTrustedProxies(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "proxy-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	t.Run("fiber", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		app := fiber.New()
+		app.Use(FiberMiddleware(&MiddlewareOptions{TrustedProxies: []string{"0.0.0.0"}, LogForwardingChain: true}))
+		app.Get("/", func(c *fiber.Ctx) error {
+			return c.SendStatus(200)
+		})
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		_, _ = app.Test(req)
+
+		fields := observedLogs.TakeAll()[0].ContextMap()
+		if fields["ip"] != "203.0.113.7" {
+			t.Errorf("Expected the forwarded client ip, got %v", fields["ip"])
+		}
+		if !reflect.DeepEqual(fields["forwarded_for"], []interface{}{"203.0.113.7", "0.0.0.0"}) {
+			t.Errorf("Expected the forwarding chain, got %v", fields["forwarded_for"])
+		}
+	})
+
+	t.Run("net/http", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		handler := HTTPMiddleware(&HTTPMiddlewareOptions{
+			TrustedProxies: []string{"192.0.2.0/24"},
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Add("X-Forwarded-For", "203.0.113.7")
+		req.Header.Add("X-Forwarded-For", "192.0.2.44")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		fields := observedLogs.TakeAll()[0].ContextMap()
+		if fields["ip"] != "203.0.113.7" {
+			t.Errorf("Expected the forwarded client ip, got %v", fields["ip"])
+		}
+		if _, ok := fields["forwarded_for"]; ok {
+			t.Error("Expected no forwarding chain unless requested")
+		}
+	})
+}
+
+func TestForwardingChainPersonalData(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		expected interface{}
+	}{
+		{"anonymized", Config{AnonymizeIP: IPTruncate}, []interface{}{"203.0.113.0", "192.0.2.0", "0.0.0.0"}},
+		{"dropped", Config{Privacy: &PrivacyOptions{}}, nil},
+		{"hashed", Config{Privacy: &PrivacyOptions{Mode: PrivacyHash}}, []interface{}{
+			sensitiveValue{value: "203.0.113.7"}.String(),
+			sensitiveValue{value: "192.0.2.44"}.String(),
+			sensitiveValue{value: "0.0.0.0"}.String(),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.ServiceName = "proxy-privacy-test"
+			tt.config.Level = LevelDEBUG
+			_, observedLogs := setupObservedLogger(tt.config)
+
+			app := fiber.New()
+			app.Use(FiberMiddleware(&MiddlewareOptions{TrustedProxies: []string{"0.0.0.0", "192.0.2.0/24"}, LogForwardingChain: true}))
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendStatus(200)
+			})
+			handler := HTTPMiddleware(&HTTPMiddlewareOptions{
+				TrustedProxies:     []string{"192.0.2.0/24"},
+				LogForwardingChain: true,
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("X-Forwarded-For", "203.0.113.7, 192.0.2.44")
+			_, _ = app.Test(req)
+
+			req = httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = "0.0.0.0:1234"
+			req.Header.Set("X-Forwarded-For", "203.0.113.7, 192.0.2.44")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			logs := observedLogs.TakeAll()
+			if len(logs) != 2 {
+				t.Fatalf("Expected 2 entries, got %d", len(logs))
+			}
+			for _, entry := range logs {
+				if chain := entry.ContextMap()["forwarded_for"]; !reflect.DeepEqual(chain, tt.expected) {
+					t.Errorf("Expected forwarded_for=%v, got %v", tt.expected, chain)
+				}
+			}
+		})
+	}
+}
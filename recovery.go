@@ -0,0 +1,202 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// maxGoroutineDumpBytes caps the size of the goroutine dump
+const maxGoroutineDumpBytes = 64 << 10
+
+// PanicEvent describes a panic recovered by RecoveryMiddleware
+type PanicEvent struct {
+	Method string
+	Path   string
+	// Route is the matched route pattern (e.g. /users/:id)
+	Route string
+	// Value is the recovered panic value
+	Value interface{}
+	// Stack is the stack from the panic site, as logged in panic_stack
+	Stack string
+	// Fingerprint identifies the panic site independently of arguments and
+	// goroutine, so repeated panics can be grouped
+	Fingerprint string
+}
+
+// PanicHook is notified of every recovered panic, e.g. to increment metrics
+// or trigger alerts
+type PanicHook interface {
+	HandlePanic(ctx context.Context, event PanicEvent)
+}
+
+// PanicHookFunc adapts a function to the PanicHook interface
+type PanicHookFunc func(ctx context.Context, event PanicEvent)
+
+// HandlePanic calls f(ctx, event)
+func (f PanicHookFunc) HandlePanic(ctx context.Context, event PanicEvent) {
+	f(ctx, event)
+}
+
+// RecoveryOptions configures the panic recovery middleware
+type RecoveryOptions struct {
+	// GoroutineDump adds a dump of all goroutines as panic_goroutines. It
+	// stops the world while collecting, so keep it for debugging.
+	GoroutineDump bool
+	// Response is sent as the JSON body of the 500 response
+	// (default {"error":"internal server error"})
+	Response interface{}
+	// Renderer writes the response for a recovered panic, taking precedence
+	// over Response
+	Renderer func(c *fiber.Ctx, recovered interface{}) error
+	// OnPanic is called after the panic is logged, e.g. to report it to an
+	// error tracker or record metrics
+	OnPanic func(c *fiber.Ctx, recovered interface{}, stack string)
+	// Repanic re-raises the panic after logging so upstream handlers (e.g.
+	// development tooling) see it. No response is written.
+	Repanic bool
+	// Hooks are notified of every recovered panic after it is logged
+	Hooks []PanicHook
+}
+
+// RecoveryMiddleware returns a Fiber middleware that recovers from panics and logs them
+func RecoveryMiddleware(opts ...*RecoveryOptions) fiber.Handler {
+	options := &RecoveryOptions{}
+	if len(opts) > 0 && opts[0] != nil {
+		options = opts[0]
+	}
+
+	response := options.Response
+	if response == nil {
+		response = fiber.Map{"error": "internal server error"}
+	}
+
+	logger := GetInstance()
+
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				stack := trimStack(debug.Stack())
+				panicFingerprint := fingerprint(fmt.Sprintf("%T", r) + "\n" + stackFunctions(stack))
+				context := LogContext{
+					"method":            c.Method(),
+					"path":              c.Path(),
+					"panic":             panicMessage(r),
+					"panic_type":        fmt.Sprintf("%T", r),
+					"panic_stack":       stack,
+					"panic_fingerprint": panicFingerprint,
+					"status_code":       500,
+				}
+				if options.GoroutineDump {
+					context["panic_goroutines"] = goroutineDump()
+				}
+
+				logger.Error(c.UserContext(), "Panic recovered", context)
+				if len(options.Hooks) > 0 {
+					// Copy Fiber's pooled strings since hooks may retain the event
+					event := PanicEvent{
+						Method:      utils.CopyString(c.Method()),
+						Path:        utils.CopyString(c.Path()),
+						Route:       utils.CopyString(c.Route().Path),
+						Value:       r,
+						Stack:       stack,
+						Fingerprint: panicFingerprint,
+					}
+					for _, hook := range options.Hooks {
+						hook.HandlePanic(c.UserContext(), event)
+					}
+				}
+				if options.OnPanic != nil {
+					options.OnPanic(c, r, stack)
+				}
+				if options.Repanic {
+					panic(r)
+				}
+
+				c.Status(fiber.StatusInternalServerError)
+				if options.Renderer != nil {
+					err = options.Renderer(c, r)
+				} else {
+					err = c.JSON(response)
+				}
+			}
+		}()
+
+		return c.Next()
+	}
+}
+
+// panicMessage formats a recovered panic value
+func panicMessage(r interface{}) string {
+	switch value := r.(type) {
+	case error:
+		return value.Error()
+	case fmt.Stringer:
+		return value.String()
+	case string:
+		return value
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// trimStack drops the goroutine header, the frames of the recovery handler
+// and runtime frames from a debug.Stack() trace, leaving the frames from
+// the panic site down
+func trimStack(stack []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(stack)), "\n")
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "goroutine ") {
+		lines = lines[1:]
+	}
+
+	// Frames are a function line followed by an indented file:line line
+	var frames []string
+	for i := 0; i+1 < len(lines); i += 2 {
+		frames = append(frames, lines[i]+"\n"+lines[i+1])
+	}
+
+	// Skip everything up to and including the last panic frame
+	for i := len(frames) - 1; i >= 0; i-- {
+		if strings.HasPrefix(frames[i], "panic(") {
+			frames = frames[i+1:]
+			break
+		}
+	}
+
+	kept := frames[:0]
+	for _, frame := range frames {
+		if !strings.HasPrefix(frame, "runtime.") && !strings.HasPrefix(frame, "runtime/debug.") {
+			kept = append(kept, frame)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// stackFunctions returns the function names of a trimmed stack without
+// arguments or file positions, which vary between occurrences
+func stackFunctions(stack string) string {
+	var functions []string
+	for _, line := range strings.Split(stack, "\n") {
+		if line == "" || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		if i := strings.LastIndex(line, "("); i > 0 {
+			line = line[:i]
+		}
+		functions = append(functions, line)
+	}
+	return strings.Join(functions, "\n")
+}
+
+// goroutineDump returns the stacks of all goroutines, truncated to
+// maxGoroutineDumpBytes
+func goroutineDump() string {
+	buf := make([]byte, maxGoroutineDumpBytes)
+	n := runtime.Stack(buf, true)
+	return string(buf[:n])
+}
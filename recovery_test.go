@@ -0,0 +1,209 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func panickingHandler(c *fiber.Ctx) error {
+	panic(errors.New("nil order"))
+}
+
+func TestRecoveryMiddlewareStack(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "recovery-stack-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(RecoveryMiddleware(&RecoveryOptions{GoroutineDump: true}))
+	app.Get("/panic", panickingHandler)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/panic", nil))
+	if resp.StatusCode != 500 {
+		t.Errorf("Expected status 500, got %d", resp.StatusCode)
+	}
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(logs))
+	}
+	ctx := logs[0].ContextMap()
+
+	if ctx["panic"] != "nil order" {
+		t.Errorf("Expected error panic to be formatted, got %v", ctx["panic"])
+	}
+	if ctx["panic_type"] != "*errors.errorString" {
+		t.Errorf("Expected panic_type *errors.errorString, got %v", ctx["panic_type"])
+	}
+
+	stack, _ := ctx["panic_stack"].(string)
+	if !strings.HasPrefix(stack, "github.com/rcommerz/logger-go.panickingHandler") {
+		t.Errorf("Expected stack to start at the panic site, got %q", stack)
+	}
+	if strings.Contains(stack, "runtime/debug.Stack") || strings.Contains(stack, "RecoveryMiddleware.func1.1") {
+		t.Errorf("Expected recovery frames to be trimmed, got %q", stack)
+	}
+
+	if dump, _ := ctx["panic_goroutines"].(string); !strings.HasPrefix(dump, "goroutine ") {
+		t.Errorf("Expected goroutine dump, got %q", dump)
+	}
+}
+
+func TestPanicMessage(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{"boom", "boom"},
+		{errors.New("failed"), "failed"},
+		{2 * time.Second, "2s"},
+		{42, "42"},
+	}
+
+	for _, tt := range tests {
+		if got := panicMessage(tt.value); got != tt.expected {
+			t.Errorf("panicMessage(%#v) = %q, expected %q", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestRecoveryOptions(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "recovery-options-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	t.Run("should send custom response", func(t *testing.T) {
+		app := fiber.New()
+		app.Use(RecoveryMiddleware(&RecoveryOptions{
+			Response: fiber.Map{"code": "INTERNAL"},
+		}))
+		app.Get("/panic", panickingHandler)
+
+		resp, _ := app.Test(httptest.NewRequest("GET", "/panic", nil))
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != 500 || string(body) != `{"code":"INTERNAL"}` {
+			t.Errorf("Expected custom 500 response, got %d %s", resp.StatusCode, body)
+		}
+	})
+
+	t.Run("should use renderer, callback and hooks", func(t *testing.T) {
+		var reported, callbackReported interface{}
+		var reportedStack, callbackStack string
+
+		app := fiber.New()
+		app.Use(RecoveryMiddleware(&RecoveryOptions{
+			Renderer: func(c *fiber.Ctx, recovered interface{}) error {
+				return c.SendString("oops: " + panicMessage(recovered))
+			},
+			OnPanic: func(c *fiber.Ctx, recovered interface{}, stack string) {
+				callbackReported = recovered
+				callbackStack = stack
+			},
+			Hooks: []PanicHook{PanicHookFunc(func(ctx context.Context, event PanicEvent) {
+				reported = event.Value
+				reportedStack = event.Stack
+			})},
+		}))
+		app.Get("/panic", panickingHandler)
+
+		resp, _ := app.Test(httptest.NewRequest("GET", "/panic", nil))
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != 500 || string(body) != "oops: nil order" {
+			t.Errorf("Expected rendered 500 response, got %d %s", resp.StatusCode, body)
+		}
+		if err, ok := reported.(error); !ok || err.Error() != "nil order" {
+			t.Errorf("Expected hook to receive panic value, got %v", reported)
+		}
+		if !strings.Contains(reportedStack, "panickingHandler") {
+			t.Errorf("Expected hook to receive stack, got %q", reportedStack)
+		}
+		if callbackReported != reported || callbackStack != reportedStack {
+			t.Errorf("Expected callback to receive the panic value and stack, got %v %q", callbackReported, callbackStack)
+		}
+	})
+
+	t.Run("should re-panic after logging", func(t *testing.T) {
+		var upstream interface{}
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					upstream = r
+					err = c.SendStatus(503)
+				}
+			}()
+			return c.Next()
+		})
+		app.Use(RecoveryMiddleware(&RecoveryOptions{Repanic: true}))
+		app.Get("/panic", panickingHandler)
+
+		observedLogs.TakeAll()
+		resp, _ := app.Test(httptest.NewRequest("GET", "/panic", nil))
+		if resp.StatusCode != 503 || upstream == nil {
+			t.Errorf("Expected panic to reach upstream handler, got status %d", resp.StatusCode)
+		}
+		if logs := observedLogs.TakeAll(); len(logs) != 1 {
+			t.Errorf("Expected panic to be logged before re-panicking, got %d entries", len(logs))
+		}
+	})
+}
+
+func TestRecoveryPanicHooks(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "recovery-hooks-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	var events []PanicEvent
+	app := fiber.New()
+	app.Use(RecoveryMiddleware(&RecoveryOptions{
+		Hooks: []PanicHook{PanicHookFunc(func(ctx context.Context, event PanicEvent) {
+			events = append(events, event)
+		})},
+	}))
+	app.Get("/orders/:id", panickingHandler)
+	app.Get("/other", func(c *fiber.Ctx) error {
+		panic("different site")
+	})
+
+	_, _ = app.Test(httptest.NewRequest("GET", "/orders/1", nil))
+	_, _ = app.Test(httptest.NewRequest("GET", "/orders/2", nil))
+	_, _ = app.Test(httptest.NewRequest("GET", "/other", nil))
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 panic events, got %d", len(events))
+	}
+	if events[0].Route != "/orders/:id" || events[0].Path != "/orders/1" || events[0].Method != "GET" {
+		t.Errorf("Unexpected panic event: %+v", events[0])
+	}
+	if err, ok := events[0].Value.(error); !ok || err.Error() != "nil order" {
+		t.Errorf("Expected panic value in event, got %v", events[0].Value)
+	}
+	if events[0].Fingerprint == "" || events[0].Fingerprint != events[1].Fingerprint {
+		t.Errorf("Expected stable fingerprint for the same panic site, got %q and %q", events[0].Fingerprint, events[1].Fingerprint)
+	}
+	if events[0].Fingerprint == events[2].Fingerprint {
+		t.Error("Expected different fingerprints for different panic sites")
+	}
+
+	logs := observedLogs.TakeAll()
+	if logs[0].ContextMap()["panic_fingerprint"] != events[0].Fingerprint {
+		t.Errorf("Expected panic_fingerprint in log entry, got %v", logs[0].ContextMap()["panic_fingerprint"])
+	}
+}
@@ -0,0 +1,290 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"mime"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces sensitive values in log entries
+const RedactedValue = "[REDACTED]"
+
+// defaultRedactedHeaders lists credential-bearing headers that are never
+// logged verbatim
+var defaultRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"Api-Key",
+	"X-Auth-Token",
+	"X-Csrf-Token",
+}
+
+// defaultRedactedQueryParams lists query parameters that commonly carry
+// credentials or one-time codes
+var defaultRedactedQueryParams = []string{
+	"token",
+	"access_token",
+	"refresh_token",
+	"api_key",
+	"apikey",
+	"password",
+	"secret",
+	"code",
+}
+
+// nameSet builds a case-insensitive lookup of the given name lists
+func nameSet(lists ...[]string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, list := range lists {
+		for _, name := range list {
+			set[strings.ToLower(name)] = struct{}{}
+		}
+	}
+	return set
+}
+
+// headerRedactor masks the values of sensitive headers
+type headerRedactor struct {
+	names map[string]struct{}
+	hash  bool
+}
+
+// newHeaderRedactor builds a redactor for the default headers plus extra
+func newHeaderRedactor(extra []string, hash bool) *headerRedactor {
+	return &headerRedactor{
+		names: nameSet(defaultRedactedHeaders, extra),
+		hash:  hash,
+	}
+}
+
+// redact returns the loggable value of a header
+func (r *headerRedactor) redact(name, value string) string {
+	if _, ok := r.names[strings.ToLower(name)]; !ok {
+		return value
+	}
+	if r.hash {
+		return hashValue(value)
+	}
+	return RedactedValue
+}
+
+// redactQuery masks the values of sensitive parameters in a raw query string,
+// preserving parameter order and the encoding of everything else
+func redactQuery(rawQuery string, names map[string]struct{}) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+
+	pairs := strings.Split(rawQuery, "&")
+	for i, pair := range pairs {
+		key, _, hasValue := strings.Cut(pair, "=")
+		if !hasValue {
+			continue
+		}
+
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		if _, ok := names[strings.ToLower(name)]; ok {
+			pairs[i] = key + "=" + RedactedValue
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// hashValue returns a short, non-reversible SHA-256 digest of a value so it
+// can still be correlated across entries
+func hashValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(sum[:])[:16]
+}
+
+// fingerprint returns the first 8 hex characters of the SHA-256 of a value,
+// enough to trace a credential across entries without storing it
+func fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+// DefaultRedactKeys are the key patterns redacted by DefaultRedactor. "*"
+// matches any characters and matching is case-insensitive.
+var DefaultRedactKeys = []string{
+	"*password*",
+	"*passwd*",
+	"*secret*",
+	"*token*",
+	"*_key",
+	"api_key",
+	"apikey",
+	"authorization",
+	"cookie",
+	"set-cookie",
+	"credential*",
+}
+
+// DefaultRedactValues are the value patterns redacted by DefaultRedactor:
+// bearer credentials and JWTs
+var DefaultRedactValues = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)bearer\s+[a-z0-9\-._~+/]+=*`),
+	regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+}
+
+// Redactor masks the values of sensitive keys and sensitive substrings of
+// string values, recursively through nested maps and slices
+type Redactor struct {
+	keys   []*regexp.Regexp
+	values []valueRule
+}
+
+// valueRule replaces matches of a pattern in string values
+type valueRule struct {
+	pattern *regexp.Regexp
+	replace func(match string) string
+}
+
+// NewRedactor builds a Redactor for key patterns ("*" matches any
+// characters, case-insensitive) and value regular expressions whose matches
+// are replaced with RedactedValue
+func NewRedactor(keyPatterns []string, valuePatterns ...*regexp.Regexp) *Redactor {
+	r := &Redactor{}
+	for _, pattern := range keyPatterns {
+		r.keys = append(r.keys, compileKeyGlob(pattern))
+	}
+	for _, pattern := range valuePatterns {
+		r.values = append(r.values, valueRule{
+			pattern: pattern,
+			replace: func(string) string { return RedactedValue },
+		})
+	}
+	return r
+}
+
+// DefaultRedactor returns a Redactor for DefaultRedactKeys and
+// DefaultRedactValues
+func DefaultRedactor() *Redactor {
+	return NewRedactor(DefaultRedactKeys, DefaultRedactValues...)
+}
+
+// Redact returns a copy of context with sensitive values masked. The input
+// is not modified.
+func (r *Redactor) Redact(context LogContext) LogContext {
+	redacted := make(LogContext, len(context))
+	for key, value := range context {
+		redacted[key] = r.redactField(key, value)
+	}
+	return redacted
+}
+
+// RedactString masks sensitive substrings of s
+func (r *Redactor) RedactString(s string) string {
+	for _, rule := range r.values {
+		s = rule.pattern.ReplaceAllStringFunc(s, rule.replace)
+	}
+	return s
+}
+
+// sensitiveKey reports whether a key matches a redacted key pattern
+func (r *Redactor) sensitiveKey(key string) bool {
+	for _, pattern := range r.keys {
+		if pattern.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactField masks a value stored under key
+func (r *Redactor) redactField(key string, value interface{}) interface{} {
+	if r.sensitiveKey(key) {
+		return RedactedValue
+	}
+	return r.redactValue(value)
+}
+
+// redactValue walks nested maps and slices, masking sensitive keys and
+// string values
+func (r *Redactor) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return r.RedactString(v)
+	case LogContext:
+		return r.Redact(v)
+	case map[string]interface{}:
+		return map[string]interface{}(r.Redact(v))
+	case map[string]string:
+		redacted := make(map[string]string, len(v))
+		for key, item := range v {
+			if r.sensitiveKey(key) {
+				redacted[key] = RedactedValue
+			} else {
+				redacted[key] = r.RedactString(item)
+			}
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = r.redactValue(item)
+		}
+		return redacted
+	case []map[string]interface{}:
+		redacted := make([]map[string]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = r.Redact(item)
+		}
+		return redacted
+	case []string:
+		redacted := make([]string, len(v))
+		for i, item := range v {
+			redacted[i] = r.RedactString(item)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// RedactBody masks sensitive fields of a captured request or response body.
+// JSON and form bodies are redacted by key; other bodies by value pattern.
+func (r *Redactor) RedactBody(contentType string, body []byte) []byte {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+
+		var parsed interface{}
+		if err := decoder.Decode(&parsed); err == nil {
+			if redacted, err := json.Marshal(r.redactValue(parsed)); err == nil {
+				return redacted
+			}
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		pairs := strings.Split(string(body), "&")
+		for i, pair := range pairs {
+			key, value, hasValue := strings.Cut(pair, "=")
+			name, err := url.QueryUnescape(key)
+			if err != nil {
+				name = key
+			}
+			if hasValue && r.sensitiveKey(name) {
+				pairs[i] = key + "=" + RedactedValue
+			} else if hasValue {
+				pairs[i] = key + "=" + r.RedactString(value)
+			}
+		}
+		return []byte(strings.Join(pairs, "&"))
+	}
+
+	return []byte(r.RedactString(string(body)))
+}
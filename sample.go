@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// pathSampler keeps 1 of every N successful requests for configured paths
+type pathSampler struct {
+	rules []*sampleRule
+}
+
+type sampleRule struct {
+	exact   string
+	glob    *regexp.Regexp
+	rate    uint64
+	counter atomic.Uint64
+}
+
+// newPathSampler compiles path → N rules; exact paths are checked before
+// globs, and globs in lexical order, so matching is deterministic
+func newPathSampler(paths map[string]int) *pathSampler {
+	keys := make([]string, 0, len(paths))
+	for path := range paths {
+		keys = append(keys, path)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		gi, gj := isGlob(keys[i]), isGlob(keys[j])
+		if gi != gj {
+			return !gi
+		}
+		return keys[i] < keys[j]
+	})
+
+	s := &pathSampler{}
+	for _, path := range keys {
+		rate := paths[path]
+		if rate < 1 {
+			rate = 1
+		}
+
+		rule := &sampleRule{rate: uint64(rate)}
+		if isGlob(path) {
+			rule.glob = compileGlob(path)
+		} else {
+			rule.exact = path
+		}
+		s.rules = append(s.rules, rule)
+	}
+	return s
+}
+
+// rule returns the sampling rule for a path, if any
+func (s *pathSampler) rule(path string) *sampleRule {
+	for _, rule := range s.rules {
+		if (rule.glob == nil && rule.exact == path) || (rule.glob != nil && rule.glob.MatchString(path)) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// keep reports whether this request is the 1 in N that gets logged; the
+// first request is always kept
+func (r *sampleRule) keep() bool {
+	return (r.counter.Add(1)-1)%r.rate == 0
+}
+
+// SamplingOptions limits entries repeating the same level and message:
+// within each Tick, the first Initial are logged, then 1 of every
+// Thereafter
+type SamplingOptions struct {
+	// Initial defaults to 100
+	Initial int
+	// Thereafter defaults to 100
+	Thereafter int
+	// Tick defaults to 1s
+	Tick time.Duration
+}
+
+// entrySampler counts entries by level and message per tick
+type entrySampler struct {
+	initial    uint64
+	thereafter uint64
+	tick       time.Duration
+	// clock is Config.Clock, if set
+	clock Clock
+
+	mu     sync.Mutex
+	window time.Time
+	counts map[string]uint64
+}
+
+func newEntrySampler(opts *SamplingOptions, clock Clock) *entrySampler {
+	s := &entrySampler{initial: 100, thereafter: 100, tick: time.Second, clock: clock, counts: make(map[string]uint64)}
+	if opts.Initial > 0 {
+		s.initial = uint64(opts.Initial)
+	}
+	if opts.Thereafter > 0 {
+		s.thereafter = uint64(opts.Thereafter)
+	}
+	if opts.Tick > 0 {
+		s.tick = opts.Tick
+	}
+	return s
+}
+
+// allow reports whether an entry is logged. ERROR, security and audit
+// entries always are.
+func (s *entrySampler) allow(level LogLevel, logType LogType, message string) bool {
+	if level == LevelERROR || logType == TypeSecurity || logType == TypeAudit {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Counts restart with each tick, which also bounds the map to the
+	// messages of one tick
+	if now := s.now(); now.Sub(s.window) >= s.tick {
+		s.window = now
+		clear(s.counts)
+	}
+
+	key := string(level) + "\x00" + message
+	s.counts[key]++
+	n := s.counts[key]
+	return n <= s.initial || (n-s.initial)%s.thereafter == 0
+}
+
+func (s *entrySampler) now() time.Time {
+	if s.clock != nil {
+		return s.clock.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"io"
+	"regexp"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogSchemaVersion is written as log_schema_version on every entry and
+// changes whenever standard field names or types change, so pipelines can
+// route entries by the contract they follow
+const LogSchemaVersion = "1"
+
+// SchemaOptions enables strict mode, which validates the fields of every
+// entry against a contract
+type SchemaOptions struct {
+	// Required lists the fields each entry of a log type must carry, e.g.
+	// {TypeAudit: {"actor", "action"}}
+	Required map[LogType][]string
+	// KeyPattern, when set, must match every field key, e.g.
+	// ^[a-z][a-z0-9_.]*$
+	KeyPattern *regexp.Regexp
+	// Quarantine, when set, receives non-conforming entries instead of the
+	// output. Otherwise they are dropped, except for audit and security
+	// entries, which are written. Either way the entry lists its problems
+	// under schema_violations.
+	Quarantine io.Writer
+}
+
+// validate returns the schema violations of an entry's fields
+func (s *SchemaOptions) validate(logType LogType, fields LogContext) []string {
+	var violations []string
+	for _, key := range s.Required[logType] {
+		if _, ok := fields[key]; !ok {
+			violations = append(violations, "missing field "+key)
+		}
+	}
+
+	if s.KeyPattern != nil {
+		var invalid []string
+		for key := range fields {
+			if !s.KeyPattern.MatchString(key) {
+				invalid = append(invalid, "invalid key "+key)
+			}
+		}
+		sort.Strings(invalid)
+		violations = append(violations, invalid...)
+	}
+	return violations
+}
+
+// schemaViolation decides how a non-conforming entry is written, returning
+// the logger to write it to, or false when it is dropped
+func (l *Logger) schemaViolation(out *zap.Logger, skip int, logType LogType) (*zap.Logger, bool) {
+	if l.quarantine != nil {
+		out = l.quarantine
+		if skip > 0 && (l.config.EnableCaller || l.config.StackTraces) {
+			out = out.WithOptions(zap.AddCallerSkip(skip))
+		}
+		return out, true
+	}
+
+	if logType == TypeAudit || logType == TypeSecurity {
+		return out, true
+	}
+	if l.config.Metrics != nil {
+		l.config.Metrics.EntryDropped("schema")
+	}
+	return nil, false
+}
+
+// newQuarantineLogger writes entries to the Schema.Quarantine writer with
+// the same encoding and constant fields as the output
+func (l *Logger) newQuarantineLogger(constants []zap.Field) *zap.Logger {
+	core := zapcore.NewCore(l.newOutputEncoder(), zapcore.AddSync(l.config.Schema.Quarantine), zapcore.DebugLevel)
+	return zap.New(core, l.zapOptions()...).With(constants...)
+}
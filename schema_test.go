@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLogSchemaVersion(t *testing.T) {
+	var output bytes.Buffer
+	logger := New(Config{ServiceName: "schema-test", Level: LevelINFO, Output: &output})
+	logger.Info(context.Background(), "hello", nil)
+
+	if !strings.Contains(output.String(), `"log_schema_version":"`+LogSchemaVersion+`"`) {
+		t.Errorf("Expected log_schema_version on the entry, got %q", output.String())
+	}
+}
+
+func TestSchemaStrictMode(t *testing.T) {
+	schema := func(quarantine *bytes.Buffer) *SchemaOptions {
+		options := &SchemaOptions{
+			Required:   map[LogType][]string{TypeAudit: {"actor", "action"}, TypeNormal: {"order_id"}},
+			KeyPattern: regexp.MustCompile(`^[a-z][a-z0-9_.]*$`),
+		}
+		if quarantine != nil {
+			options.Quarantine = quarantine
+		}
+		return options
+	}
+
+	t.Run("should drop non-conforming entries without a quarantine", func(t *testing.T) {
+		var output bytes.Buffer
+		metrics := newRecordedMetrics()
+		logger := New(Config{ServiceName: "schema-test", Level: LevelINFO, Output: &output, Metrics: metrics, Schema: schema(nil)})
+
+		ctx := context.Background()
+		logger.Info(ctx, "conforming", LogContext{"order_id": "123"})
+		logger.Info(ctx, "missing", nil)
+		logger.Info(ctx, "bad key", LogContext{"order_id": "123", "Order ID": "123"})
+
+		if lines := strings.Count(output.String(), "\n"); lines != 1 || !strings.Contains(output.String(), "conforming") {
+			t.Errorf("Expected only the conforming entry, got %q", output.String())
+		}
+		if metrics.dropped["schema"] != 2 {
+			t.Errorf("Expected 2 entries dropped for the schema, got %v", metrics.dropped)
+		}
+	})
+
+	t.Run("should write audit entries with their violations", func(t *testing.T) {
+		var output bytes.Buffer
+		logger := New(Config{ServiceName: "schema-test", Level: LevelINFO, Output: &output, Schema: schema(nil)})
+
+		logger.Audit(context.Background(), "Role changed", LogContext{"actor": "admin"})
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(output.Bytes(), &entry); err != nil {
+			t.Fatalf("Expected the audit entry, got %q", output.String())
+		}
+		expected := []interface{}{"missing field action"}
+		if !reflect.DeepEqual(entry["schema_violations"], expected) {
+			t.Errorf("Expected schema_violations=%v, got %v", expected, entry["schema_violations"])
+		}
+	})
+
+	t.Run("should quarantine non-conforming entries", func(t *testing.T) {
+		var output, quarantine bytes.Buffer
+		logger := New(Config{ServiceName: "schema-test", Level: LevelINFO, Output: &output, Schema: schema(&quarantine)})
+
+		ctx := context.Background()
+		logger.Info(ctx, "conforming", LogContext{"order_id": "123"})
+		logger.Info(ctx, "bad", LogContext{"Order ID": "123"})
+
+		if strings.Contains(output.String(), "bad") {
+			t.Errorf("Expected the non-conforming entry not to reach the output, got %q", output.String())
+		}
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(quarantine.Bytes(), &entry); err != nil {
+			t.Fatalf("Expected one quarantined entry, got %q", quarantine.String())
+		}
+		expected := []interface{}{"missing field order_id", "invalid key Order ID"}
+		if !reflect.DeepEqual(entry["schema_violations"], expected) {
+			t.Errorf("Expected schema_violations=%v, got %v", expected, entry["schema_violations"])
+		}
+		if entry["message"] != "bad" || entry["service.name"] != "schema-test" || entry["Order ID"] != "123" {
+			t.Errorf("Expected the full entry in quarantine, got %v", entry)
+		}
+	})
+}
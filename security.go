@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"context"
+	"time"
+)
+
+// SecurityEventCode identifies the kind of a security event. Codes are
+// stable across services so SIEM rules can match on event_code.
+type SecurityEventCode string
+
+const (
+	SecurityEventLoginFailure SecurityEventCode = "SEC-AUTH-001"
+	SecurityEventAccessDenied SecurityEventCode = "SEC-AUTHZ-001"
+	SecurityEventRateLimited  SecurityEventCode = "SEC-RATE-001"
+	SecurityEventTokenAnomaly SecurityEventCode = "SEC-TOKEN-001"
+)
+
+// securityEventCategories groups event codes for coarse SIEM filtering
+var securityEventCategories = map[SecurityEventCode]string{
+	SecurityEventLoginFailure: "authentication",
+	SecurityEventAccessDenied: "authorization",
+	SecurityEventRateLimited:  "rate_limit",
+	SecurityEventTokenAnomaly: "token",
+}
+
+// SecurityLoginFailure logs a failed login attempt by user from ip
+func (l *Logger) SecurityLoginFailure(ctx context.Context, user, ip string, fields LogContext) {
+	l.securityEvent(ctx, SecurityEventLoginFailure, "login failed", fields, LogContext{
+		"user_id": user,
+		"ip":      ip,
+	})
+}
+
+// SecurityAccessDenied logs user being denied access to resource
+func (l *Logger) SecurityAccessDenied(ctx context.Context, user, resource string, fields LogContext) {
+	l.securityEvent(ctx, SecurityEventAccessDenied, "access denied", fields, LogContext{
+		"user_id":  user,
+		"resource": resource,
+	})
+}
+
+// SecurityRateLimited logs a client, identified by key (e.g. an IP or user
+// ID), exceeding limit requests per window
+func (l *Logger) SecurityRateLimited(ctx context.Context, key string, limit int, window time.Duration, fields LogContext) {
+	l.securityEvent(ctx, SecurityEventRateLimited, "rate limit exceeded", fields, LogContext{
+		"rate_limit_key":       key,
+		"rate_limit":           limit,
+		"rate_limit_window_ms": window.Milliseconds(),
+	})
+}
+
+// SecurityTokenAnomaly logs a suspicious token presented by user, e.g. an
+// expired, reused or badly signed token described by anomaly
+func (l *Logger) SecurityTokenAnomaly(ctx context.Context, user, anomaly string, fields LogContext) {
+	l.securityEvent(ctx, SecurityEventTokenAnomaly, "token anomaly", fields, LogContext{
+		"user_id": user,
+		"anomaly": anomaly,
+	})
+}
+
+// securityEvent logs a standardized security event. Messages are constant
+// so users, resources and keys stay in fields, where redaction, privacy
+// mode and IP anonymization apply. The standard fields take precedence over
+// caller fields with the same key.
+func (l *Logger) securityEvent(ctx context.Context, code SecurityEventCode, message string, fields, standard LogContext) {
+	context := make(LogContext, len(fields)+len(standard)+2)
+	for key, value := range fields {
+		context[key] = value
+	}
+	for key, value := range standard {
+		context[key] = value
+	}
+	context["event_code"] = string(code)
+	context["event_category"] = securityEventCategories[code]
+
+	l.log(ctx, 1, LevelWARN, TypeSecurity, message, context)
+}
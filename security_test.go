@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSecurityEvents(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "security-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelINFO,
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		log      func()
+		message  string
+		expected map[string]interface{}
+	}{
+		{
+			name:    "login failure",
+			log:     func() { logger.SecurityLoginFailure(ctx, "alice", "10.0.0.1", LogContext{"method": "password"}) },
+			message: "login failed",
+			expected: map[string]interface{}{
+				"event_code":     "SEC-AUTH-001",
+				"event_category": "authentication",
+				"user_id":        "alice",
+				"ip":             "10.0.0.1",
+				"method":         "password",
+			},
+		},
+		{
+			name:    "access denied",
+			log:     func() { logger.SecurityAccessDenied(ctx, "bob", "invoice/7", nil) },
+			message: "access denied",
+			expected: map[string]interface{}{
+				"event_code":     "SEC-AUTHZ-001",
+				"event_category": "authorization",
+				"user_id":        "bob",
+				"resource":       "invoice/7",
+			},
+		},
+		{
+			name:    "rate limited",
+			log:     func() { logger.SecurityRateLimited(ctx, "10.0.0.2", 100, time.Minute, nil) },
+			message: "rate limit exceeded",
+			expected: map[string]interface{}{
+				"event_code":           "SEC-RATE-001",
+				"event_category":       "rate_limit",
+				"rate_limit_key":       "10.0.0.2",
+				"rate_limit":           int64(100),
+				"rate_limit_window_ms": int64(60000),
+			},
+		},
+		{
+			name:    "token anomaly",
+			log:     func() { logger.SecurityTokenAnomaly(ctx, "carol", "reused", LogContext{"event_code": "custom"}) },
+			message: "token anomaly",
+			expected: map[string]interface{}{
+				"event_code":     "SEC-TOKEN-001",
+				"event_category": "token",
+				"user_id":        "carol",
+				"anomaly":        "reused",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.log()
+
+			logs := observedLogs.TakeAll()
+			if len(logs) != 1 {
+				t.Fatalf("Expected 1 log entry, got %d", len(logs))
+			}
+			if logs[0].Message != tt.message {
+				t.Errorf("Expected message %q, got %q", tt.message, logs[0].Message)
+			}
+
+			fields := logs[0].ContextMap()
+			if fields["log_type"] != "security" || logs[0].Level.String() != "warn" {
+				t.Errorf("Expected a WARN security entry, got %v %v", logs[0].Level, fields["log_type"])
+			}
+			for key, value := range tt.expected {
+				if fields[key] != value {
+					t.Errorf("Expected %s=%v, got %v", key, value, fields[key])
+				}
+			}
+		})
+	}
+}
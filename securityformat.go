@@ -0,0 +1,207 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// SecurityFormat is a SIEM format for security entries
+type SecurityFormat string
+
+const (
+	// SecurityFormatCEF renders ArcSight Common Event Format
+	SecurityFormatCEF SecurityFormat = "cef"
+	// SecurityFormatLEEF renders QRadar Log Event Extended Format 1.0
+	SecurityFormatLEEF SecurityFormat = "leef"
+)
+
+// SecurityFormatOptions renders security entries in a SIEM format while
+// other entries stay JSON
+type SecurityFormatOptions struct {
+	Format SecurityFormat
+	// Vendor is the device vendor in the header (default "rcommerz").
+	// The product and version are the service name and version.
+	Vendor string
+}
+
+// cefKeys and leefKeys map field names to the formats' standard keys
+var (
+	cefKeys = map[string]string{
+		"ip":             "src",
+		"user_id":        "suser",
+		"resource":       "request",
+		"event_category": "cat",
+	}
+	leefKeys = map[string]string{
+		"ip":             "src",
+		"user_id":        "usrName",
+		"resource":       "resource",
+		"event_category": "cat",
+	}
+)
+
+// securityHeaderFields are rendered in the header, not the extension
+var securityHeaderFields = map[string]bool{
+	"@timestamp":      true,
+	"log.level":       true,
+	"log_type":        true,
+	"event_code":      true,
+	"service.name":    true,
+	"service.version": true,
+}
+
+var securityBufferPool = buffer.NewPool()
+
+// securityEncoder encodes security entries as CEF or LEEF, delegating
+// other entries to the JSON encoder
+type securityEncoder struct {
+	zapcore.Encoder
+	opts SecurityFormatOptions
+}
+
+func newSecurityEncoder(enc zapcore.Encoder, opts *SecurityFormatOptions) zapcore.Encoder {
+	e := &securityEncoder{Encoder: enc, opts: *opts}
+	if e.opts.Vendor == "" {
+		e.opts.Vendor = "rcommerz"
+	}
+	return e
+}
+
+func (e *securityEncoder) Clone() zapcore.Encoder {
+	return &securityEncoder{Encoder: e.Encoder.Clone(), opts: e.opts}
+}
+
+func (e *securityEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(entry, fields)
+	if err != nil || !hasLogType(fields, TypeSecurity) {
+		return buf, err
+	}
+	defer buf.Free()
+
+	// Decode the JSON line so fields added with With are rendered too
+	decoder := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+	decoder.UseNumber()
+	var decoded map[string]interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(decoded))
+	flattenSecurityFields("", decoded, values)
+
+	out := securityBufferPool.Get()
+	product, version := values["service.name"], values["service.version"]
+	eventID := values["event_code"]
+	if eventID == "" {
+		eventID = string(TypeSecurity)
+	}
+	severity := securitySeverity(entry.Level)
+
+	switch e.opts.Format {
+	case SecurityFormatLEEF:
+		fmt.Fprintf(out, "LEEF:1.0|%s|%s|%s|%s|",
+			escapeSecurityHeader(e.opts.Vendor), escapeSecurityHeader(product),
+			escapeSecurityHeader(version), escapeSecurityHeader(eventID))
+		values["message"] = entry.Message
+		extension := map[string]string{
+			"sev":     fmt.Sprint(severity),
+			"devTime": entry.Time.UTC().Format("Jan 02 2006 15:04:05"),
+		}
+		writeSecurityExtension(out, values, extension, leefKeys, "\t", escapeLEEFValue)
+	default:
+		fmt.Fprintf(out, "CEF:0|%s|%s|%s|%s|%s|%d|",
+			escapeSecurityHeader(e.opts.Vendor), escapeSecurityHeader(product),
+			escapeSecurityHeader(version), escapeSecurityHeader(eventID),
+			escapeSecurityHeader(entry.Message), severity)
+		delete(values, "message")
+		extension := map[string]string{
+			"rt": fmt.Sprint(entry.Time.UnixMilli()),
+		}
+		writeSecurityExtension(out, values, extension, cefKeys, " ", escapeCEFValue)
+	}
+	out.AppendString(zapcore.DefaultLineEnding)
+	return out, nil
+}
+
+// writeSecurityExtension appends the fixed extension keys followed by the
+// remaining fields, renamed to standard keys and sorted
+func writeSecurityExtension(out *buffer.Buffer, values, extension, keys map[string]string, separator string, escape func(string) string) {
+	for key, value := range values {
+		if securityHeaderFields[key] {
+			continue
+		}
+		if standard, ok := keys[key]; ok {
+			key = standard
+		}
+		extension[key] = value
+	}
+
+	names := make([]string, 0, len(extension))
+	for key := range extension {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+
+	for i, key := range names {
+		if i > 0 {
+			out.AppendString(separator)
+		}
+		out.AppendString(key)
+		out.AppendByte('=')
+		out.AppendString(escape(extension[key]))
+	}
+}
+
+// flattenSecurityFields flattens nested objects into dotted keys, since
+// CEF and LEEF extensions are flat
+func flattenSecurityFields(prefix string, fields map[string]interface{}, out map[string]string) {
+	for key, value := range fields {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flattenSecurityFields(key, v, out)
+		case string:
+			out[key] = v
+		case nil:
+			out[key] = ""
+		case []interface{}:
+			encoded, _ := json.Marshal(v)
+			out[key] = string(encoded)
+		default:
+			out[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// securitySeverity maps a level to the 0-10 severity used by CEF and LEEF
+func securitySeverity(level zapcore.Level) int {
+	switch {
+	case level >= zapcore.DPanicLevel:
+		return 10
+	case level == zapcore.ErrorLevel:
+		return 8
+	case level == zapcore.WarnLevel:
+		return 6
+	case level == zapcore.InfoLevel:
+		return 3
+	default:
+		return 1
+	}
+}
+
+var (
+	securityHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	cefValueEscaper       = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+	leefValueEscaper      = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+)
+
+func escapeSecurityHeader(s string) string { return securityHeaderEscaper.Replace(s) }
+func escapeCEFValue(s string) string       { return cefValueEscaper.Replace(s) }
+func escapeLEEFValue(s string) string      { return leefValueEscaper.Replace(s) }
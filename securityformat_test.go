@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newSecurityFormatLogger returns a logger writing to buf with security
+// entries rendered in format
+func newSecurityFormatLogger(buf *bytes.Buffer, format SecurityFormat) *Logger {
+	enc := newSecurityEncoder(newEncoder(), &SecurityFormatOptions{Format: format})
+	core := zapcore.NewCore(enc, zapcore.AddSync(buf), zapcore.DebugLevel)
+	return &Logger{zap: zap.New(core).With(
+		zap.String("service.name", "auth"),
+		zap.String("service.version", "1.2.0"),
+	)}
+}
+
+func TestSecurityFormatCEF(t *testing.T) {
+	var buf bytes.Buffer
+	l := newSecurityFormatLogger(&buf, SecurityFormatCEF)
+	ctx := context.Background()
+
+	l.SecurityLoginFailure(ctx, "alice", "10.0.0.1", LogContext{"note": "a=b|c"})
+	l.Info(ctx, "not security", nil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+
+	prefix := `CEF:0|rcommerz|auth|1.2.0|SEC-AUTH-001|login failed|6|`
+	if !strings.HasPrefix(lines[0], prefix) {
+		t.Errorf("Expected CEF header %q, got %s", prefix, lines[0])
+	}
+	for _, want := range []string{"cat=authentication", "src=10.0.0.1", "suser=alice", `note=a\=b|c`, "rt="} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("Expected %q in %s", want, lines[0])
+		}
+	}
+	if strings.Contains(lines[0], "log_type") {
+		t.Errorf("Expected header fields excluded from the extension, got %s", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "{") {
+		t.Errorf("Expected non-security entries to stay JSON, got %s", lines[1])
+	}
+}
+
+func TestSecurityFormatLEEF(t *testing.T) {
+	var buf bytes.Buffer
+	l := newSecurityFormatLogger(&buf, SecurityFormatLEEF)
+
+	l.Security(context.Background(), "suspicious\tpayload", LogContext{"details": LogContext{"rule": "sqli"}})
+
+	line := strings.TrimSpace(buf.String())
+	prefix := "LEEF:1.0|rcommerz|auth|1.2.0|security|"
+	if !strings.HasPrefix(line, prefix) {
+		t.Fatalf("Expected LEEF header %q, got %s", prefix, line)
+	}
+
+	attributes := map[string]string{}
+	for _, pair := range strings.Split(strings.TrimPrefix(line, prefix), "\t") {
+		key, value, _ := strings.Cut(pair, "=")
+		attributes[key] = value
+	}
+	expected := map[string]string{
+		"sev":          "6",
+		"message":      "suspicious payload",
+		"details.rule": "sqli",
+	}
+	for key, value := range expected {
+		if attributes[key] != value {
+			t.Errorf("Expected %s=%q, got %q", key, value, attributes[key])
+		}
+	}
+	if attributes["devTime"] == "" {
+		t.Errorf("Expected devTime attribute, got %v", attributes)
+	}
+}
+
+func TestSecurityFormatHelpers(t *testing.T) {
+	severities := map[zapcore.Level]int{
+		zapcore.DebugLevel:  1,
+		zapcore.InfoLevel:   3,
+		zapcore.WarnLevel:   6,
+		zapcore.ErrorLevel:  8,
+		zapcore.DPanicLevel: 10,
+		zapcore.FatalLevel:  10,
+	}
+	for level, want := range severities {
+		if got := securitySeverity(level); got != want {
+			t.Errorf("securitySeverity(%s) = %d, want %d", level, got, want)
+		}
+	}
+
+	out := make(map[string]string)
+	flattenSecurityFields("", map[string]interface{}{
+		"actor":  map[string]interface{}{"id": "u1", "roles": []interface{}{"admin"}},
+		"reason": nil,
+		"count":  3.0,
+	}, out)
+	want := map[string]string{"actor.id": "u1", "actor.roles": `["admin"]`, "reason": "", "count": "3"}
+	for key, value := range want {
+		if out[key] != value {
+			t.Errorf("Expected %s=%s, got %q", key, value, out[key])
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package logger
+
+// semconvFieldNames maps the middleware's default field names to their
+// OpenTelemetry HTTP semantic-convention equivalents
+var semconvFieldNames = map[string]string{
+	"method":           "http.request.method",
+	"path":             "url.path",
+	"query":            "url.query",
+	"status_code":      "http.response.status_code",
+	"ip":               "client.address",
+	"user_agent":       "user_agent.original",
+	"request_bytes":    "http.request.body.size",
+	"response_bytes":   "http.response.body.size",
+	"headers":          "http.request.header",
+	"response_headers": "http.response.header",
+}
+
+// applySemanticConventions renames HTTP fields in place to the OpenTelemetry
+// semantic-convention names so logs line up with span attributes
+func applySemanticConventions(context LogContext) {
+	for from, to := range semconvFieldNames {
+		if value, ok := context[from]; ok {
+			context[to] = value
+			delete(context, from)
+		}
+	}
+}
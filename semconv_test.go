@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestApplySemanticConventions(t *testing.T) {
+	context := LogContext{
+		"method":      "GET",
+		"path":        "/api/orders",
+		"status_code": 200,
+		"http.route":  "/api/orders",
+		"order_id":    "ORD-1",
+	}
+
+	applySemanticConventions(context)
+
+	expected := LogContext{
+		"http.request.method":       "GET",
+		"url.path":                  "/api/orders",
+		"http.response.status_code": 200,
+		"http.route":                "/api/orders",
+		"order_id":                  "ORD-1",
+	}
+
+	if len(context) != len(expected) {
+		t.Fatalf("Expected %d fields, got %v", len(expected), context)
+	}
+	for key, value := range expected {
+		if context[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, context[key])
+		}
+	}
+}
+
+func TestFiberMiddlewareSemanticConventions(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "semconv-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{SemanticConventions: true}))
+	app.Get("/api/items", func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	req := httptest.NewRequest("GET", "/api/items?page=2", nil)
+	req.Header.Set("User-Agent", "test-agent")
+	_, _ = app.Test(req)
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(logs))
+	}
+
+	fields := logs[0].ContextMap()
+	for _, key := range []string{"http.request.method", "url.path", "url.query", "http.response.status_code", "client.address", "user_agent.original"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("Expected field %s", key)
+		}
+	}
+	for _, key := range []string{"method", "path", "status_code", "ip", "user_agent"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("Expected legacy field %s to be renamed", key)
+		}
+	}
+}
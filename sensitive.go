@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// defaultSensitiveSalt keys the pseudonyms of loggers without
+// Config.SensitiveSalt, and of values rendered outside a logger. It is
+// random per process and never changed.
+var defaultSensitiveSalt = randomSalt()
+
+// sensitiveValue is a value that is only ever rendered as a pseudonym or
+// its last four characters. salt is set by the logger writing the value.
+type sensitiveValue struct {
+	value string
+	last4 bool
+	salt  []byte
+}
+
+// Sensitive returns a field whose value is logged as a salted hash
+// ("hmac:" + 16 hex characters), so entries can be correlated on an
+// identifier such as an SSN without exposing it
+func Sensitive(key string, value interface{}) LogContext {
+	return LogContext{key: sensitiveValue{value: fmt.Sprint(value)}}
+}
+
+// SensitiveLast4 returns a field whose value is logged as its last four
+// characters only (e.g. "***6789")
+func SensitiveLast4(key string, value interface{}) LogContext {
+	return LogContext{key: sensitiveValue{value: fmt.Sprint(value), last4: true}}
+}
+
+// String renders the masked form, so the raw value never leaks through
+// fmt or zap
+func (v sensitiveValue) String() string {
+	if v.last4 {
+		if len(v.value) <= 4 {
+			return "***"
+		}
+		return "***" + v.value[len(v.value)-4:]
+	}
+
+	salt := v.salt
+	if salt == nil {
+		salt = defaultSensitiveSalt
+	}
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(v.value))
+	return "hmac:" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// MarshalJSON renders the masked form when nested in other values
+func (v sensitiveValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
+}
+
+// sensitiveSalt returns the salt keying the pseudonyms of a logger
+func sensitiveSalt(config Config) []byte {
+	if config.SensitiveSalt != "" {
+		return []byte(config.SensitiveSalt)
+	}
+	return defaultSensitiveSalt
+}
+
+// randomSalt returns a random 32-byte salt
+func randomSalt() []byte {
+	salt := make([]byte, 32)
+	_, _ = rand.Read(salt)
+	return salt
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSensitive(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "sensitive-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+		SensitiveSalt:  "pepper",
+	})
+
+	ctx := context.Background()
+	logger.Info(ctx, "lookup", Sensitive("ssn", "123-45-6789"))
+	logger.Info(ctx, "lookup", Sensitive("ssn", "123-45-6789"))
+	logger.Info(ctx, "lookup", Sensitive("ssn", "987-65-4321"))
+	logger.Info(ctx, "charge", SensitiveLast4("card", 4111111111111111))
+
+	logs := observedLogs.TakeAll()
+	first, _ := logs[0].ContextMap()["ssn"].(string)
+	if !strings.HasPrefix(first, "hmac:") || len(first) != len("hmac:")+16 {
+		t.Errorf("Expected hmac pseudonym, got %q", first)
+	}
+	if first != logs[1].ContextMap()["ssn"] {
+		t.Error("Expected the same value to produce the same pseudonym")
+	}
+	if first == logs[2].ContextMap()["ssn"] {
+		t.Error("Expected different values to produce different pseudonyms")
+	}
+	if card := logs[3].ContextMap()["card"]; card != "***1111" {
+		t.Errorf("Expected last-4 form, got %v", card)
+	}
+}
+
+func TestSensitiveValueNeverLeaks(t *testing.T) {
+	value := Sensitive("ssn", "123-45-6789")["ssn"]
+
+	nested, _ := json.Marshal(map[string]interface{}{"user": map[string]interface{}{"ssn": value}})
+	printed := fmt.Sprintf("%v %+v", value, value)
+
+	for _, out := range []string{string(nested), printed} {
+		if strings.Contains(out, "6789") {
+			t.Errorf("Expected raw value to be hidden, got %s", out)
+		}
+	}
+	if last4 := SensitiveLast4("pin", "12")["pin"]; fmt.Sprint(last4) != "***" {
+		t.Errorf("Expected short values to be fully masked, got %v", last4)
+	}
+}
+
+func TestSensitiveSaltPerLogger(t *testing.T) {
+	var pepper, other bytes.Buffer
+	first := New(Config{ServiceName: "salt-test", Level: LevelINFO, SensitiveSalt: "pepper", Output: &pepper})
+	second := New(Config{ServiceName: "salt-test", Level: LevelINFO, SensitiveSalt: "other", Output: &other})
+
+	ctx := context.Background()
+	fields := LogContext{"user": map[string]interface{}{"ssn": Sensitive("ssn", "123-45-6789")["ssn"]}}
+	for key, value := range Sensitive("ssn", "123-45-6789") {
+		fields[key] = value
+	}
+	first.Info(ctx, "lookup", fields)
+	second.Info(ctx, "lookup", fields)
+
+	expected := sensitiveValue{value: "123-45-6789", salt: []byte("pepper")}.String()
+	var entry struct {
+		SSN  string `json:"ssn"`
+		User struct {
+			SSN string `json:"ssn"`
+		} `json:"user"`
+	}
+	if err := json.Unmarshal(pepper.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected JSON output, got %v", err)
+	}
+	if entry.SSN != expected || entry.User.SSN != expected {
+		t.Errorf("Expected %s keyed with the first logger's salt, got %+v", expected, entry)
+	}
+	if strings.Contains(other.String(), expected) {
+		t.Error("Expected the second logger to use its own salt")
+	}
+}
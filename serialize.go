@@ -0,0 +1,203 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// defaultMaxDepth is the nesting limit when Config.MaxDepth is unset
+const defaultMaxDepth = 10
+
+// safeValue returns a representation of value that serializes without
+// unbounded recursion: cycles and values nested deeper than maxDepth are
+// replaced by a type-name placeholder, and values JSON cannot encode
+// (funcs, channels, failing marshalers) by their fmt form, and nested
+// Sensitive values by their pseudonym keyed with salt. Values that need no
+// changes are returned as is.
+func safeValue(value interface{}, maxDepth int, salt []byte) interface{} {
+	switch value.(type) {
+	case nil, string, bool, int, int64, int32, float64, float32, uint, uint64,
+		time.Time, time.Duration, error, fmt.Stringer, []byte:
+		return value
+	}
+
+	s := &serializer{maxDepth: maxDepth, salt: salt, seen: make(map[visit]bool)}
+	safe, _ := s.walk(reflect.ValueOf(value), 0)
+	return safe
+}
+
+// serializer tracks the containers on the current path for cycle detection
+type serializer struct {
+	maxDepth int
+	// salt keys nested Sensitive values
+	salt []byte
+	seen map[visit]bool
+}
+
+// visit identifies a container by address and type, since a slice and its
+// first element can share an address
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// walk returns the safe form of v and whether it differs from v
+func (s *serializer) walk(v reflect.Value, depth int) (interface{}, bool) {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil, false
+	}
+
+	if v.CanInterface() {
+		switch value := v.Interface().(type) {
+		case sensitiveValue:
+			value.salt = s.salt
+			return value.String(), true
+		case json.Marshaler:
+			if v.Kind() == reflect.Ptr && v.IsNil() {
+				return nil, false
+			}
+			if _, err := value.MarshalJSON(); err != nil {
+				return fmt.Sprintf("%v", value), true
+			}
+			return value, false
+		case error, fmt.Stringer:
+			return value, false
+		}
+	}
+
+	if depth > s.maxDepth {
+		return placeholder("max depth", v), true
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, false
+		}
+		if s.enter(v) {
+			return placeholder("cycle", v), true
+		}
+		defer s.leave(v)
+
+		elem, changed := s.walk(v.Elem(), depth)
+		if !changed {
+			return v.Interface(), false
+		}
+		return elem, true
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v.Interface(), false
+		}
+		if s.enter(v) {
+			return placeholder("cycle", v), true
+		}
+		defer s.leave(v)
+
+		converted := make(map[string]interface{}, v.Len())
+		changed := v.Type().Key().Kind() != reflect.String
+		iter := v.MapRange()
+		for iter.Next() {
+			elem, elemChanged := s.walk(iter.Value(), depth+1)
+			converted[fmt.Sprint(iter.Key().Interface())] = elem
+			changed = changed || elemChanged
+		}
+		if !changed {
+			return v.Interface(), false
+		}
+		return converted, true
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice {
+			if v.IsNil() {
+				return v.Interface(), false
+			}
+			if s.enter(v) {
+				return placeholder("cycle", v), true
+			}
+			defer s.leave(v)
+		}
+
+		converted := make([]interface{}, v.Len())
+		changed := false
+		for i := 0; i < v.Len(); i++ {
+			elem, elemChanged := s.walk(v.Index(i), depth+1)
+			converted[i] = elem
+			changed = changed || elemChanged
+		}
+		if !changed {
+			return v.Interface(), false
+		}
+		return converted, true
+
+	case reflect.Struct:
+		converted := make(map[string]interface{}, v.NumField())
+		changed := false
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name, ok := jsonFieldName(field)
+			if !ok {
+				continue
+			}
+			elem, elemChanged := s.walk(v.Field(i), depth+1)
+			converted[name] = elem
+			changed = changed || elemChanged
+		}
+		if !changed && v.CanInterface() {
+			return v.Interface(), false
+		}
+		return converted, true
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return placeholder(v.Kind().String(), v), true
+
+	default:
+		if !v.CanInterface() {
+			return placeholder("unexported", v), true
+		}
+		return v.Interface(), false
+	}
+}
+
+// enter marks a container as on the current path, reporting a cycle if it
+// already is
+func (s *serializer) enter(v reflect.Value) bool {
+	key := visit{ptr: v.Pointer(), typ: v.Type()}
+	if s.seen[key] {
+		return true
+	}
+	s.seen[key] = true
+	return false
+}
+
+func (s *serializer) leave(v reflect.Value) {
+	delete(s.seen, visit{ptr: v.Pointer(), typ: v.Type()})
+}
+
+// placeholder describes a value that cannot be serialized
+func placeholder(reason string, v reflect.Value) string {
+	return fmt.Sprintf("<%s: %s>", reason, v.Type())
+}
+
+// jsonFieldName returns the JSON name of an exported struct field, or false
+// if encoding/json would skip it
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return field.Name, true
+}
@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type node struct {
+	Name   string `json:"name"`
+	Next   *node  `json:"next,omitempty"`
+	secret string
+}
+
+type failingMarshaler struct{}
+
+func (failingMarshaler) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("cannot marshal")
+}
+
+func TestSafeValue(t *testing.T) {
+	t.Run("should replace cycles with a placeholder", func(t *testing.T) {
+		a := &node{Name: "a", secret: "x"}
+		a.Next = &node{Name: "b", Next: a}
+
+		got := safeValue(a, 10, nil)
+		expected := map[string]interface{}{
+			"name": "a",
+			"next": map[string]interface{}{
+				"name": "b",
+				"next": "<cycle: *logger.node>",
+			},
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected %v, got %v", expected, got)
+		}
+		if _, err := json.Marshal(got); err != nil {
+			t.Errorf("Expected safe value to marshal, got %v", err)
+		}
+	})
+
+	t.Run("should detect self-referencing maps", func(t *testing.T) {
+		m := map[string]interface{}{"id": 1}
+		m["self"] = m
+
+		got := safeValue(m, 10, nil).(map[string]interface{})
+		if got["self"] != "<cycle: map[string]interface {}>" || got["id"] != 1 {
+			t.Errorf("Unexpected map serialization: %v", got)
+		}
+	})
+
+	t.Run("should limit depth", func(t *testing.T) {
+		deep := map[string]interface{}{"l1": map[string]interface{}{"l2": map[string]interface{}{"l3": 1}}}
+
+		got := safeValue(deep, 1, nil).(map[string]interface{})
+		l1 := got["l1"].(map[string]interface{})
+		if l1["l2"] != "<max depth: map[string]interface {}>" {
+			t.Errorf("Expected depth placeholder, got %v", l1["l2"])
+		}
+	})
+
+	t.Run("should fall back for unencodable values", func(t *testing.T) {
+		got := safeValue(map[string]interface{}{
+			"callback": func() {},
+			"broken":   failingMarshaler{},
+		}, 10, nil).(map[string]interface{})
+
+		if got["callback"] != "<func: func()>" || got["broken"] != "{}" {
+			t.Errorf("Unexpected fallbacks: %v", got)
+		}
+	})
+
+	t.Run("should return safe values unchanged", func(t *testing.T) {
+		headers := map[string]string{"Accept": "*/*"}
+		if got, ok := safeValue(headers, 10, nil).(map[string]string); !ok || got["Accept"] != "*/*" {
+			t.Errorf("Expected map[string]string to be kept, got %#v", got)
+		}
+
+		items := []node{{Name: "a"}}
+		if _, ok := safeValue(items, 10, nil).([]node); !ok {
+			t.Error("Expected acyclic slice of structs to be kept")
+		}
+	})
+}
+
+func TestLoggerSerializesCyclicValues(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "serialize-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+		MaxDepth:       3,
+	})
+
+	a := &node{Name: "a"}
+	a.Next = a
+	logger.Info(context.Background(), "graph", LogContext{"node": a})
+
+	fields := observedLogs.TakeAll()[0].ContextMap()
+	if _, err := json.Marshal(fields["node"]); err != nil {
+		t.Errorf("Expected cyclic value to be serializable, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"sort"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Field shapes for Config.FieldShape
+const (
+	// FieldShapeNested expands dotted keys into nested objects, e.g.
+	// "http.route" becomes {"http": {"route": ...}}, as Elasticsearch
+	// mappings expect
+	FieldShapeNested = "nested"
+	// FieldShapeFlat flattens nested maps into dotted keys, e.g.
+	// {"order": {"id": 1}} becomes "order.id", for column stores such as
+	// BigQuery
+	FieldShapeFlat = "flat"
+)
+
+// shapeFields applies Config.FieldShape to the fields of an entry
+func shapeFields(fields LogContext, shape string) LogContext {
+	switch shape {
+	case FieldShapeNested:
+		return nestFields(fields)
+	case FieldShapeFlat:
+		flat := make(LogContext, len(fields))
+		for key, value := range fields {
+			flattenField(flat, key, value)
+		}
+		return flat
+	default:
+		return fields
+	}
+}
+
+// flattenField adds value to flat under key, or its entries under dotted
+// keys when it is a map
+func flattenField(flat LogContext, key string, value interface{}) {
+	switch v := value.(type) {
+	case LogContext:
+		flattenField(flat, key, map[string]interface{}(v))
+	case map[string]interface{}:
+		if len(v) == 0 {
+			flat[key] = v
+		}
+		for nestedKey, nested := range v {
+			flattenField(flat, key+"."+nestedKey, nested)
+		}
+	case map[string]string:
+		if len(v) == 0 {
+			flat[key] = v
+		}
+		for nestedKey, nested := range v {
+			flat[key+"."+nestedKey] = nested
+		}
+	default:
+		flat[key] = value
+	}
+}
+
+// fieldObject is an object created by nestFields, which unlike maps
+// logged by the caller may receive further keys
+type fieldObject map[string]interface{}
+
+// nestFields expands dotted keys into nested objects. A key that would
+// replace or extend a value logged under one of its prefixes is kept
+// dotted, so no value is lost.
+func nestFields(fields LogContext) LogContext {
+	// Sorted keys place "a" before "a.b", making conflicts deterministic
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	nested := make(LogContext, len(fields))
+	for _, key := range keys {
+		if !nestField(fieldObject(nested), key, fields[key]) {
+			nested[key] = fields[key]
+		}
+	}
+	return nested
+}
+
+// nestField sets value under the path of key, reporting false when an
+// existing value is in the way
+func nestField(object fieldObject, key string, value interface{}) bool {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		if part == "" {
+			return false
+		}
+		switch child := object[part].(type) {
+		case nil:
+			if _, exists := object[part]; exists {
+				return false
+			}
+			next := fieldObject{}
+			object[part] = next
+			object = next
+		case fieldObject:
+			object = child
+		default:
+			return false
+		}
+	}
+
+	last := parts[len(parts)-1]
+	if _, exists := object[last]; exists || last == "" {
+		return false
+	}
+	object[last] = value
+	return true
+}
+
+// namespacedFields encodes the fields of an entry as the Config.FieldNamespace
+// object
+type namespacedFields struct {
+	logger       *Logger
+	transformers []fieldTransformer
+	fields       LogContext
+}
+
+func (n namespacedFields) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for key, value := range n.fields {
+		n.logger.field(n.transformers, key, value).AddTo(enc)
+	}
+	return nil
+}
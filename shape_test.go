@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFieldShape(t *testing.T) {
+	fields := func() LogContext {
+		return LogContext{
+			"http.request.method": "GET",
+			"http.route":          "/orders/:id",
+			"order":               LogContext{"id": "123", "customer": map[string]interface{}{"tier": "gold"}},
+			"headers":             map[string]string{"Accept": "json"},
+			"user":                "u1",
+			"user.role":           "admin",
+			"empty":               map[string]interface{}{},
+		}
+	}
+
+	tests := []struct {
+		shape    string
+		expected map[string]interface{}
+	}{
+		{
+			shape: FieldShapeNested,
+			expected: map[string]interface{}{
+				"http": map[string]interface{}{
+					"request": map[string]interface{}{"method": "GET"},
+					"route":   "/orders/:id",
+				},
+				"order":     map[string]interface{}{"id": "123", "customer": map[string]interface{}{"tier": "gold"}},
+				"headers":   map[string]interface{}{"Accept": "json"},
+				"user":      "u1",
+				"user.role": "admin",
+				"empty":     map[string]interface{}{},
+			},
+		},
+		{
+			shape: FieldShapeFlat,
+			expected: map[string]interface{}{
+				"http.request.method": "GET",
+				"http.route":          "/orders/:id",
+				"order.id":            "123",
+				"order.customer.tier": "gold",
+				"headers.Accept":      "json",
+				"user":                "u1",
+				"user.role":           "admin",
+				"empty":               map[string]interface{}{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shape, func(t *testing.T) {
+			var output bytes.Buffer
+			logger := New(Config{ServiceName: "shape-test", Level: LevelINFO, Output: &output, FieldShape: tt.shape})
+			logger.Info(context.Background(), "hello", fields())
+
+			var entry map[string]interface{}
+			if err := json.Unmarshal(output.Bytes(), &entry); err != nil {
+				t.Fatalf("Invalid JSON %q: %v", output.String(), err)
+			}
+			for key, value := range tt.expected {
+				if !reflect.DeepEqual(entry[key], value) {
+					t.Errorf("Expected %s=%v, got %v", key, value, entry[key])
+				}
+			}
+			if entry["service.name"] != "shape-test" {
+				t.Errorf("Expected standard fields to keep dotted names, got %v", entry)
+			}
+		})
+	}
+}
+
+func TestFieldNamespace(t *testing.T) {
+	var output bytes.Buffer
+	logger := New(Config{ServiceName: "namespace-test", Level: LevelINFO, Output: &output, FieldNamespace: "labels"})
+	logger.Info(context.Background(), "hello", LogContext{"order_id": "123", "items": 3})
+	logger.Info(context.Background(), "empty", nil)
+
+	lines := bytes.Split(bytes.TrimSpace(output.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 entries, got %q", output.String())
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(lines[0], &entry); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", lines[0], err)
+	}
+	expected := map[string]interface{}{"order_id": "123", "items": 3.0}
+	if !reflect.DeepEqual(entry["labels"], expected) {
+		t.Errorf("Expected labels=%v, got %v", expected, entry["labels"])
+	}
+	if entry["order_id"] != nil || entry["service.name"] != "namespace-test" || entry["log_type"] != "normal" {
+		t.Errorf("Expected only standard fields at the top level, got %v", entry)
+	}
+
+	entry = nil
+	if err := json.Unmarshal(lines[1], &entry); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", lines[1], err)
+	}
+	if _, ok := entry["labels"]; ok {
+		t.Errorf("Expected no labels object without fields, got %v", entry)
+	}
+}
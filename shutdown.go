@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ShutdownOptions configures RegisterShutdown
+type ShutdownOptions struct {
+	// Timeout bounds flushing buffered entries on shutdown (default 5s)
+	Timeout time.Duration
+	// Signals that start the shutdown (default SIGTERM and SIGINT)
+	Signals []os.Signal
+}
+
+// RegisterShutdown returns a context that is canceled when the process
+// receives SIGTERM or SIGINT, logging the signal as the shutdown reason,
+// and a function to call once the application has stopped. It flushes
+// and closes the logger's outputs within Timeout, so no entries are lost
+// on pod eviction:
+//
+//	ctx, shutdown := log.RegisterShutdown(context.Background(), nil)
+//	defer shutdown()
+//	<-ctx.Done()
+//	server.Shutdown(...)
+func (l *Logger) RegisterShutdown(ctx context.Context, opts *ShutdownOptions) (context.Context, func() error) {
+	if opts == nil {
+		opts = &ShutdownOptions{}
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	signals := opts.Signals
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGTERM, syscall.SIGINT}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+
+	var (
+		mu     sync.Mutex
+		reason = "application exit"
+	)
+	go func() {
+		select {
+		case sig := <-received:
+			mu.Lock()
+			reason = fmt.Sprintf("signal: %s", sig)
+			mu.Unlock()
+			l.withDebug().Log(ctx, LevelINFO, TypeNormal, "Shutdown signal received", LogContext{
+				"signal": sig.String(),
+			})
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var once sync.Once
+	var err error
+	shutdown := func() error {
+		once.Do(func() {
+			signal.Stop(received)
+			cancel()
+
+			mu.Lock()
+			shutdownReason := reason
+			mu.Unlock()
+
+			flushCtx, flushCancel := context.WithTimeout(context.Background(), timeout)
+			defer flushCancel()
+			err = l.Shutdown(flushCtx, shutdownReason)
+		})
+		return err
+	}
+	return ctx, shutdown
+}
+
+// Shutdown logs the shutdown reason, stops the heartbeat and level signal
+// handling, logs pending error suppression summaries, delivers queued
+// audit entries and closes the audit sink, giving up when ctx is done.
+// Entries logged afterwards are still written to the output.
+func (l *Logger) Shutdown(ctx context.Context, reason string) error {
+	l.withDebug().Log(ctx, LevelINFO, TypeNormal, "Logger shutting down", LogContext{
+		"reason": reason,
+	})
+
+	if l.heartbeat != nil {
+		l.heartbeat.stop()
+	}
+	if l.levelSignals != nil {
+		l.levelSignals.stop()
+	}
+	if l.suppressor != nil {
+		l.suppressor.flush()
+	}
+
+	var err error
+	if delivery := l.auditDelivery; delivery != nil {
+		err = delivery.drain(ctx)
+		if closer, ok := delivery.opts.Sink.(io.Closer); ok {
+			if closeErr := closer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	}
+
+	// Syncing stdout fails on pipes and terminals, which need no flushing
+	_ = l.zap.Sync()
+	return err
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// closingSink is a flakySink that records whether it was closed
+type closingSink struct {
+	flakySink
+	closed bool
+}
+
+func (s *closingSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestShutdown(t *testing.T) {
+	t.Run("should deliver queued audit entries and close the sink", func(t *testing.T) {
+		sink := &closingSink{}
+		logger, observedLogs := setupObservedLogger(Config{
+			ServiceName:    "shutdown-test",
+			ServiceVersion: "1.0.0",
+			Env:            "test",
+			Level:          LevelERROR,
+		})
+		logger.auditDelivery = newAuditDelivery(&AuditDeliveryOptions{Sink: sink, RetryInterval: time.Millisecond}, nil)
+
+		sink.setFail(true)
+		if err := logger.auditDelivery.deliver([]byte("audit entry")); err != nil {
+			t.Fatalf("Expected entry to be queued, got %v", err)
+		}
+		sink.setFail(false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := logger.Shutdown(ctx, "deploy"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(sink.delivered()) != 1 || !sink.closed {
+			t.Errorf("Expected queued entry delivered and sink closed, got %d lines, closed=%v", len(sink.delivered()), sink.closed)
+		}
+		logs := observedLogs.FilterMessage("Logger shutting down").All()
+		if len(logs) != 1 || logs[0].ContextMap()["reason"] != "deploy" {
+			t.Errorf("Expected shutdown reason despite the ERROR level, got %v", logs)
+		}
+	})
+
+	t.Run("should give up at the deadline", func(t *testing.T) {
+		sink := &closingSink{}
+		logger, _ := setupObservedLogger(Config{ServiceName: "shutdown-test"})
+		logger.auditDelivery = newAuditDelivery(&AuditDeliveryOptions{Sink: sink, RetryInterval: time.Millisecond}, nil)
+
+		sink.setFail(true)
+		_ = logger.auditDelivery.deliver([]byte("audit entry"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := logger.Shutdown(ctx, "deploy"); err == nil {
+			t.Error("Expected undelivered entries to be reported")
+		}
+		if !sink.closed {
+			t.Error("Expected sink to be closed")
+		}
+	})
+}
@@ -0,0 +1,44 @@
+//go:build unix
+
+package logger
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRegisterShutdown(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "shutdown-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	ctx, shutdown := logger.RegisterShutdown(context.Background(), &ShutdownOptions{
+		Signals: []os.Signal{syscall.SIGUSR2},
+	})
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatalf("Failed to send signal: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected context to be canceled by the signal")
+	}
+	if err := shutdown(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if logs := observedLogs.FilterMessage("Shutdown signal received").All(); len(logs) != 1 {
+		t.Errorf("Expected signal entry, got %d", len(logs))
+	}
+	logs := observedLogs.FilterMessage("Logger shutting down").All()
+	if len(logs) != 1 || logs[0].ContextMap()["reason"] != "signal: user defined signal 2" {
+		t.Errorf("Expected signal as shutdown reason, got %v", logs)
+	}
+}
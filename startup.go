@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+// LogStartup logs one INFO entry summarizing the effective configuration
+// (with secrets masked), the Go runtime, the build's module version and VCS
+// revision, and the enabled outputs. Call it once after Initialize so
+// incident timelines show exactly what was running and how it was
+// configured.
+func (l *Logger) LogStartup(ctx context.Context) {
+	context := LogContext{
+		"config":        l.configSummary(),
+		"go.version":    runtime.Version(),
+		"go.os":         runtime.GOOS,
+		"go.arch":       runtime.GOARCH,
+		"go.gomaxprocs": runtime.GOMAXPROCS(0),
+		"go.num_cpu":    runtime.NumCPU(),
+		"sinks":         l.sinks(),
+	}
+
+	build := readBuildInfo()
+	if build.Module != "" {
+		context["build.module"] = build.Module
+		context["build.version"] = build.Version
+	}
+	if build.Revision != "" {
+		context["build.revision"] = build.Revision
+		context["build.time"] = build.Time
+		context["build.modified"] = build.Modified
+	}
+
+	l.Log(ctx, LevelINFO, TypeNormal, fmt.Sprintf("Starting %s %s", l.config.ServiceName, l.config.ServiceVersion), context)
+}
+
+// configSummary returns the effective configuration, including the
+// current level, with secrets masked and options that hold callbacks or keys reduced to whether they are set
+func (l *Logger) configSummary() map[string]interface{} {
+	config := l.config
+
+	level := l.Level()
+	maxDepth := config.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxDepth
+	}
+
+	summary := map[string]interface{}{
+		"service_name":      config.ServiceName,
+		"service_version":   config.ServiceVersion,
+		"env":               config.Env,
+		"level":             string(level),
+		"mask_pii":          config.MaskPII,
+		"max_field_bytes":   config.MaxFieldBytes,
+		"max_message_bytes": config.MaxMessageBytes,
+		"max_depth":         maxDepth,
+		"redactor":          config.Redactor != nil,
+		"privacy":           config.Privacy != nil,
+		"metrics":           config.Metrics != nil,
+		"error_rate_alert":  config.ErrorRateAlert != nil,
+		"audit_chain":       config.AuditChain != nil,
+		"audit_delivery":    config.AuditDelivery != nil,
+		"process_fields":    config.ProcessFields,
+		"goroutine_count":   config.GoroutineCount,
+		"build_metadata":    config.BuildMetadata,
+		"cloud_metadata":    config.CloudMetadata != nil,
+		"heartbeat":         config.Heartbeat != nil,
+		"level_signals":     config.LevelSignals,
+		"stack_traces":      config.StackTraces,
+		"sampling":          config.Sampling != nil,
+		"schema":            config.Schema != nil,
+		"enrichment":        config.Enrichment != nil,
+		"unit_conventions":  config.UnitConventions,
+	}
+	if config.Encoding != "" {
+		summary["encoding"] = config.Encoding
+	}
+	if config.TimeFormat != "" {
+		summary["time_format"] = config.TimeFormat
+	}
+	if config.FieldShape != "" {
+		summary["field_shape"] = config.FieldShape
+	}
+	if config.AnonymizeIP != "" {
+		summary["anonymize_ip"] = string(config.AnonymizeIP)
+	}
+	if config.FieldNamespace != "" {
+		summary["field_namespace"] = config.FieldNamespace
+	}
+	if config.SensitiveSalt != "" {
+		summary["sensitive_salt"] = RedactedValue
+	}
+	if config.AuditChain != nil && len(config.AuditChain.Key) > 0 {
+		summary["audit_chain_key"] = RedactedValue
+	}
+	if config.SecurityFormat != nil {
+		summary["security_format"] = string(config.SecurityFormat.Format)
+	}
+	if len(config.TypeLevels) > 0 {
+		typeLevels := make(map[string]string, len(config.TypeLevels))
+		for logType, typeLevel := range config.TypeLevels {
+			typeLevels[string(logType)] = string(typeLevel)
+		}
+		summary["type_levels"] = typeLevels
+	}
+	return summary
+}
+
+// sinks lists where entries are written
+func (l *Logger) sinks() []string {
+	sinks := []string{"stdout"}
+	if l.config.Output != nil {
+		sinks[0] = fmt.Sprintf("%T", l.config.Output)
+	}
+	if l.config.AuditDelivery != nil && l.config.AuditDelivery.Sink != nil {
+		sinks = append(sinks, fmt.Sprintf("audit:%T", l.config.AuditDelivery.Sink))
+	}
+	return sinks
+}
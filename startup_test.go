@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+func TestLogStartup(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "startup-test",
+		ServiceVersion: "1.2.3",
+		Env:            "test",
+		Level:          LevelDEBUG,
+		SensitiveSalt:  "salt-secret",
+		AuditChain:     &AuditChainOptions{Key: []byte("chain-secret")},
+		TypeLevels:     map[LogType]LogLevel{TypeHTTP: LevelWARN},
+	})
+
+	logger.LogStartup(context.Background())
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(logs))
+	}
+	if logs[0].Message != "Starting startup-test 1.2.3" {
+		t.Errorf("Expected startup message, got %q", logs[0].Message)
+	}
+
+	fields := logs[0].ContextMap()
+	if fields["go.version"] != runtime.Version() {
+		t.Errorf("Expected go.version %s, got %v", runtime.Version(), fields["go.version"])
+	}
+	if fields["go.gomaxprocs"] != int64(runtime.GOMAXPROCS(0)) {
+		t.Errorf("Expected go.gomaxprocs, got %v", fields["go.gomaxprocs"])
+	}
+
+	config, ok := fields["config"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected config summary, got %T", fields["config"])
+	}
+	if config["sensitive_salt"] != RedactedValue || config["audit_chain_key"] != RedactedValue {
+		t.Errorf("Expected secrets to be masked, got %v", config)
+	}
+	if config["level"] != "DEBUG" || config["audit_chain"] != true {
+		t.Errorf("Expected effective config, got %v", config)
+	}
+	if typeLevels, _ := config["type_levels"].(map[string]string); typeLevels["http"] != "WARN" {
+		t.Errorf("Expected type levels, got %v", config["type_levels"])
+	}
+}
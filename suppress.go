@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ErrorSuppressionOptions limits repeated ERROR entries, e.g. during a
+// dependency outage: within each Window, the first Initial entries of the
+// same error are logged, the rest are counted and reported by one summary
+// entry when the window ends
+type ErrorSuppressionOptions struct {
+	// Initial defaults to 10
+	Initial int
+	// Window defaults to 1m
+	Window time.Duration
+}
+
+// errorSuppressor counts ERROR entries by error per window
+type errorSuppressor struct {
+	logger  *Logger
+	initial uint64
+	window  time.Duration
+
+	mu     sync.Mutex
+	errors map[string]*repeatedError
+}
+
+// repeatedError is the count of one error in the current window
+type repeatedError struct {
+	start      time.Time
+	message    string
+	fields     LogContext
+	count      uint64
+	suppressed uint64
+	timer      *time.Timer
+}
+
+func newErrorSuppressor(logger *Logger, opts *ErrorSuppressionOptions) *errorSuppressor {
+	s := &errorSuppressor{logger: logger, initial: 10, window: time.Minute, errors: make(map[string]*repeatedError)}
+	if opts.Initial > 0 {
+		s.initial = uint64(opts.Initial)
+	}
+	if opts.Window > 0 {
+		s.window = opts.Window
+	}
+	return s
+}
+
+// allow reports whether an ERROR entry is logged, counting entries by
+// their error fingerprint
+func (s *errorSuppressor) allow(key, message string, context LogContext) bool {
+	s.mu.Lock()
+
+	// The window starts with the first occurrence and ends with a summary
+	// of the entries it suppressed. Its timer ends it when the error stops
+	// repeating; the configured Clock ends it when the error repeats later.
+	now := s.logger.now()
+	e, ok := s.errors[key]
+	var ended *repeatedError
+	if ok && now.Sub(e.start) >= s.window {
+		e.timer.Stop()
+		delete(s.errors, key)
+		ended, ok = e, false
+	}
+	if !ok {
+		e = &repeatedError{start: now, message: message, fields: LogContext{}}
+		for _, name := range []string{"error_type", "error_message", "panic_type", "error.fingerprint"} {
+			if value, ok := context[name]; ok {
+				e.fields[name] = value
+			}
+		}
+		e.timer = time.AfterFunc(s.window, func() { s.expire(key, e) })
+		s.errors[key] = e
+	}
+
+	e.count++
+	allowed := e.count <= s.initial
+	if !allowed {
+		e.suppressed++
+	}
+	s.mu.Unlock()
+
+	if ended != nil {
+		s.summarize(ended, ended.suppressed)
+	}
+	return allowed
+}
+
+// expire ends the window of an error when its timer fires, unless it was
+// already ended
+func (s *errorSuppressor) expire(key string, e *repeatedError) {
+	s.mu.Lock()
+	if s.errors[key] != e {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.errors, key)
+	suppressed := e.suppressed
+	s.mu.Unlock()
+
+	s.summarize(e, suppressed)
+}
+
+// flush ends every window early, e.g. on Shutdown
+func (s *errorSuppressor) flush() {
+	s.mu.Lock()
+	errors := s.errors
+	s.errors = make(map[string]*repeatedError)
+	s.mu.Unlock()
+
+	for _, e := range errors {
+		e.timer.Stop()
+		s.summarize(e, e.suppressed)
+	}
+}
+
+// summarize logs the number of suppressed entries of an error, if any. The
+// summary stands in for ERROR entries, so it is written whatever the
+// configured level.
+func (s *errorSuppressor) summarize(e *repeatedError, suppressed uint64) {
+	if suppressed == 0 {
+		return
+	}
+
+	fields := LogContext{
+		"suppressed_count":   suppressed,
+		"suppressed_message": e.message,
+		"window_ms":          s.window.Milliseconds(),
+	}
+	for key, value := range e.fields {
+		fields[key] = value
+	}
+	s.logger.withDebug().log(context.Background(), 0, LevelWARN, TypeNormal, "Repeated error suppressed", fields)
+}
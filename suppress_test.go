@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestErrorSuppression(t *testing.T) {
+	metrics := newRecordedMetrics()
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:      "suppress-test",
+		ServiceVersion:   "1.0.0",
+		Env:              "test",
+		Level:            LevelDEBUG,
+		Metrics:          metrics,
+		ErrorSuppression: &ErrorSuppressionOptions{Initial: 2, Window: 50 * time.Millisecond},
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		logger.Error(ctx, "Payment provider unreachable", LogContext{"error": errors.New("dial tcp: timeout")})
+	}
+	logger.Error(ctx, "Inventory sync failed", nil)
+
+	if logs := observedLogs.TakeAll(); len(logs) != 3 {
+		t.Fatalf("Expected 3 entries before the window ends, got %d", len(logs))
+	}
+	if metrics.dropped["suppressed"] != 3 {
+		t.Errorf("Expected 3 entries dropped as suppressed, got %v", metrics.dropped)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for observedLogs.Len() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 || logs[0].Message != "Repeated error suppressed" {
+		t.Fatalf("Expected one summary entry, got %v", logs)
+	}
+	fields := logs[0].ContextMap()
+	expected := map[string]interface{}{
+		"suppressed_count":   uint64(3),
+		"suppressed_message": "Payment provider unreachable",
+		"error_message":      "dial tcp: timeout",
+		"window_ms":          int64(50),
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, fields[key])
+		}
+	}
+
+	// A new window logs the error again
+	logger.Error(ctx, "Payment provider unreachable", LogContext{"error": errors.New("dial tcp: timeout")})
+	if observedLogs.Len() != 1 {
+		t.Errorf("Expected the error to be logged in the next window")
+	}
+
+	t.Run("should summarize on shutdown", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			logger.Error(ctx, "Cache unavailable", nil)
+		}
+		observedLogs.TakeAll()
+
+		_ = logger.Shutdown(ctx, "test")
+		if observedLogs.FilterMessage("Repeated error suppressed").Len() != 1 {
+			t.Errorf("Expected a summary on shutdown, got %v", observedLogs.AllUntimed())
+		}
+	})
+}
+
+func TestErrorSuppressionPanics(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:      "suppress-panic-test",
+		ServiceVersion:   "1.0.0",
+		Env:              "test",
+		Level:            LevelDEBUG,
+		ErrorFingerprint: true,
+		ErrorSuppression: &ErrorSuppressionOptions{Initial: 1, Window: time.Minute},
+	})
+
+	app := fiber.New()
+	app.Use(RecoveryMiddleware())
+	app.Get("/orders/:id", panickingHandler)
+	app.Get("/other", func(c *fiber.Ctx) error {
+		panic("different site")
+	})
+
+	_, _ = app.Test(httptest.NewRequest("GET", "/orders/1", nil))
+	_, _ = app.Test(httptest.NewRequest("GET", "/orders/2", nil))
+	_, _ = app.Test(httptest.NewRequest("GET", "/other", nil))
+
+	logs := observedLogs.FilterMessage("Panic recovered").AllUntimed()
+	if len(logs) != 2 {
+		t.Fatalf("Expected the repeated panic suppressed and the other logged, got %d entries", len(logs))
+	}
+	for _, entry := range logs {
+		fields := entry.ContextMap()
+		if fields["error.fingerprint"] != fields["panic_fingerprint"] {
+			t.Errorf("Expected error.fingerprint=%v, got %v", fields["panic_fingerprint"], fields["error.fingerprint"])
+		}
+	}
+}
+
+func TestErrorSuppressionSummaryLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Config{
+		ServiceName:      "suppress-level-test",
+		Level:            LevelERROR,
+		Output:           &buf,
+		ErrorSuppression: &ErrorSuppressionOptions{Initial: 1, Window: time.Minute},
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		logger.Error(ctx, "Payment provider unreachable", nil)
+	}
+	logger.suppressor.flush()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected the error and its summary, got %d lines: %s", len(lines), buf.String())
+	}
+	var summary map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary["message"] != "Repeated error suppressed" || summary["suppressed_count"] != 2.0 {
+		t.Errorf("Expected a summary of 2 suppressed entries at Level ERROR, got %v", summary)
+	}
+}
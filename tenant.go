@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TenantSource configures where the middleware reads the tenant identifier
+// from. Sources are tried in order: LocalsKey, JWTClaim, Header, Subdomain.
+type TenantSource struct {
+	// LocalsKey reads the tenant from c.Locals, as set by upstream auth middleware
+	LocalsKey string
+	// JWTClaim reads a claim from the bearer token payload. The token is NOT
+	// verified; the value is only used for log correlation.
+	JWTClaim string
+	// Header reads the tenant from a request header (e.g. X-Tenant-ID)
+	Header string
+	// Subdomain uses the first label of the host (acme.shop.example.com →
+	// acme). IP addresses are skipped.
+	Subdomain bool
+	// BaseDomain, when set, makes Subdomain use the label directly below it:
+	// with shop.example.com, eu.acme.shop.example.com → acme, and hosts
+	// outside it have no tenant
+	BaseDomain string
+}
+
+// extract returns the tenant identifier for the request, or an empty string
+func (s *TenantSource) extract(c *fiber.Ctx) string {
+	if s.LocalsKey != "" {
+		if value := c.Locals(s.LocalsKey); value != nil {
+			if tenant := fmt.Sprint(value); tenant != "" {
+				return tenant
+			}
+		}
+	}
+
+	if s.JWTClaim != "" {
+		if tenant := jwtClaim(c.Get(fiber.HeaderAuthorization), s.JWTClaim); tenant != "" {
+			return tenant
+		}
+	}
+
+	if s.Header != "" {
+		if tenant := c.Get(s.Header); tenant != "" {
+			return tenant
+		}
+	}
+
+	if s.Subdomain {
+		return subdomain(c.Hostname(), s.BaseDomain)
+	}
+
+	return ""
+}
+
+// subdomain returns the first label of host, or the label directly below
+// baseDomain when it is set. IP addresses and hosts without one have none.
+func subdomain(host, baseDomain string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if net.ParseIP(strings.Trim(host, "[]")) != nil {
+		return ""
+	}
+
+	if baseDomain != "" {
+		prefix, ok := strings.CutSuffix(host, "."+strings.ToLower(strings.Trim(baseDomain, ".")))
+		if !ok || prefix == "" {
+			return ""
+		}
+		return prefix[strings.LastIndex(prefix, ".")+1:]
+	}
+
+	if labels := strings.Split(host, "."); len(labels) > 2 {
+		return labels[0]
+	}
+	return ""
+}
+
+// jwtClaim decodes the payload of a bearer token without verifying it and
+// returns the named claim as a string
+func jwtClaim(authorization, claim string) string {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return ""
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+
+	value, ok := claims[claim]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func testJWT(payload string) string {
+	return "Bearer e30." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".sig"
+}
+
+func TestJWTClaim(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		expected      string
+	}{
+		{"string claim", testJWT(`{"tenant":"acme"}`), "acme"},
+		{"numeric claim", testJWT(`{"tenant":42}`), "42"},
+		{"missing claim", testJWT(`{"sub":"u1"}`), ""},
+		{"not a bearer token", "Basic dXNlcjpwYXNz", ""},
+		{"malformed token", "Bearer abc", ""},
+		{"invalid payload", "Bearer a.!!!.c", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jwtClaim(tt.authorization, "tenant"); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFiberMiddlewareTenant(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "tenant-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	newApp := func(source *TenantSource) *fiber.App {
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			if org := c.Get("X-Auth-Org"); org != "" {
+				c.Locals("org", org)
+			}
+			return c.Next()
+		})
+		app.Use(FiberMiddleware(&MiddlewareOptions{Tenant: source}))
+		app.Get("/api/orders", func(c *fiber.Ctx) error {
+			logger.Info(c.UserContext(), "Handler log", nil)
+			return c.SendStatus(200)
+		})
+		return app
+	}
+
+	tests := []struct {
+		name     string
+		source   *TenantSource
+		setup    func(req *fiberRequest)
+		expected string
+	}{
+		{
+			name:     "header",
+			source:   &TenantSource{Header: "X-Tenant-ID"},
+			setup:    func(r *fiberRequest) { r.header["X-Tenant-ID"] = "acme" },
+			expected: "acme",
+		},
+		{
+			name:     "subdomain",
+			source:   &TenantSource{Subdomain: true},
+			setup:    func(r *fiberRequest) { r.host = "globex.shop.example.com" },
+			expected: "globex",
+		},
+		{
+			name:     "jwt claim",
+			source:   &TenantSource{JWTClaim: "tid"},
+			setup:    func(r *fiberRequest) { r.header["Authorization"] = testJWT(`{"tid":"initech"}`) },
+			expected: "initech",
+		},
+		{
+			name:   "locals win over header",
+			source: &TenantSource{LocalsKey: "org", Header: "X-Tenant-ID"},
+			setup: func(r *fiberRequest) {
+				r.header["X-Auth-Org"] = "umbrella"
+				r.header["X-Tenant-ID"] = "spoofed"
+			},
+			expected: "umbrella",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observedLogs.TakeAll()
+
+			r := &fiberRequest{header: map[string]string{}, host: "example.com"}
+			tt.setup(r)
+			req := httptest.NewRequest("GET", "/api/orders", nil)
+			req.Host = r.host
+			for key, value := range r.header {
+				req.Header.Set(key, value)
+			}
+			_, _ = newApp(tt.source).Test(req)
+
+			logs := observedLogs.TakeAll()
+			if len(logs) != 2 {
+				t.Fatalf("Expected handler and request entries, got %d", len(logs))
+			}
+			for _, entry := range logs {
+				if tenant := entry.ContextMap()["tenant_id"]; tenant != tt.expected {
+					t.Errorf("%s: expected tenant_id=%s, got %v", entry.Message, tt.expected, tenant)
+				}
+			}
+		})
+	}
+}
+
+// fiberRequest collects request customizations for table-driven tests
+type fiberRequest struct {
+	header map[string]string
+	host   string
+}
+
+func TestSubdomain(t *testing.T) {
+	tests := []struct {
+		host       string
+		baseDomain string
+		expected   string
+	}{
+		{"acme.shop.example.com", "", "acme"},
+		{"acme.shop.example.com:8080", "", "acme"},
+		{"example.com", "", ""},
+		{"10.0.0.12", "", ""},
+		{"10.0.0.12:3000", "", ""},
+		{"[2001:db8::1]:443", "", ""},
+		{"acme.shop.example.com", "shop.example.com", "acme"},
+		{"eu.acme.shop.example.com", "shop.example.com", "acme"},
+		{"Acme.Shop.Example.com.", "shop.example.com", "acme"},
+		{"shop.example.com", "shop.example.com", ""},
+		{"acme.other.example.com", "shop.example.com", ""},
+		{"10.0.0.12", "0.0.12", ""},
+	}
+
+	for _, tt := range tests {
+		if got := subdomain(tt.host, tt.baseDomain); got != tt.expected {
+			t.Errorf("subdomain(%q, %q) = %q, expected %q", tt.host, tt.baseDomain, got, tt.expected)
+		}
+	}
+}
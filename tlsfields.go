@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// addTLSFields adds http.version and, for TLS connections, tls.version,
+// tls.cipher and the client certificate's tls.client.subject and
+// tls.client.fingerprint (SHA-256 of the DER certificate)
+func addTLSFields(context LogContext, httpVersion string, state *tls.ConnectionState) {
+	if httpVersion != "" {
+		context["http.version"] = httpVersion
+	}
+	if state == nil {
+		return
+	}
+
+	context["tls.version"] = strings.TrimPrefix(tls.VersionName(state.Version), "TLS ")
+	if state.CipherSuite != 0 {
+		context["tls.cipher"] = tls.CipherSuiteName(state.CipherSuite)
+	}
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		sum := sha256.Sum256(cert.Raw)
+		context["tls.client.subject"] = cert.Subject.String()
+		context["tls.client.fingerprint"] = hex.EncodeToString(sum[:])
+	}
+}
+
+// httpVersion formats a protocol version as logged in http.version
+// ("1.1", "2", "3")
+func httpVersion(major, minor int) string {
+	if major >= 2 {
+		return strconv.Itoa(major)
+	}
+	return strconv.Itoa(major) + "." + strconv.Itoa(minor)
+}
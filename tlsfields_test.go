@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAddTLSFields(t *testing.T) {
+	cert := &x509.Certificate{Raw: []byte("client-cert"), Subject: pkix.Name{CommonName: "orders-svc", Organization: []string{"rcommerz"}}}
+	sum := sha256.Sum256(cert.Raw)
+
+	context := LogContext{}
+	addTLSFields(context, "2", &tls.ConnectionState{
+		Version:          tls.VersionTLS13,
+		CipherSuite:      tls.TLS_AES_128_GCM_SHA256,
+		PeerCertificates: []*x509.Certificate{cert},
+	})
+
+	expected := LogContext{
+		"http.version":           "2",
+		"tls.version":            "1.3",
+		"tls.cipher":             "TLS_AES_128_GCM_SHA256",
+		"tls.client.subject":     "CN=orders-svc,O=rcommerz",
+		"tls.client.fingerprint": hex.EncodeToString(sum[:]),
+	}
+	if len(context) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, context)
+	}
+	for key, value := range expected {
+		if context[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, context[key])
+		}
+	}
+
+	if v := httpVersion(1, 1); v != "1.1" {
+		t.Errorf("Expected 1.1, got %s", v)
+	}
+	if v := httpVersion(3, 0); v != "3" {
+		t.Errorf("Expected 3, got %s", v)
+	}
+}
+
+func TestMiddlewareIncludeTLS(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "tls-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	t.Run("net/http", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		handler := HTTPMiddleware(&HTTPMiddlewareOptions{IncludeTLS: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "https://shop.example/", nil))
+
+		fields := observedLogs.TakeAll()[0].ContextMap()
+		if fields["http.version"] != "1.1" || fields["tls.version"] != "1.2" {
+			t.Errorf("Expected protocol and TLS fields, got %v", fields)
+		}
+	})
+
+	t.Run("fiber without TLS", func(t *testing.T) {
+		observedLogs.TakeAll()
+
+		app := fiber.New()
+		app.Use(FiberMiddleware(&MiddlewareOptions{IncludeTLS: true}))
+		app.Get("/", func(c *fiber.Ctx) error {
+			return c.SendStatus(200)
+		})
+		_, _ = app.Test(httptest.NewRequest("GET", "/", nil))
+
+		fields := observedLogs.TakeAll()[0].ContextMap()
+		if fields["http.version"] != "1.1" {
+			t.Errorf("Expected http.version=1.1, got %v", fields["http.version"])
+		}
+		if _, ok := fields["tls.version"]; ok {
+			t.Error("Expected no TLS fields for a plain connection")
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// FieldTransformer rewrites the value of a field before it is encoded
+type FieldTransformer func(key string, value interface{}) interface{}
+
+// fieldTransformer is a transformer registered for a field name or glob
+type fieldTransformer struct {
+	key       string
+	glob      *regexp.Regexp
+	transform FieldTransformer
+}
+
+// transformerSet holds the transformers shared by a logger and its children
+type transformerSet struct {
+	mu           sync.RWMutex
+	transformers []fieldTransformer
+}
+
+// RegisterFieldTransformer applies transform to every field whose key
+// matches pattern, either an exact name (e.g. "email") or a glob where "*"
+// matches any characters (e.g. "*_email"). Transformers run in
+// registration order.
+func (l *Logger) RegisterFieldTransformer(pattern string, transform FieldTransformer) {
+	if l.transformers == nil {
+		l.transformers = &transformerSet{}
+	}
+
+	t := fieldTransformer{key: pattern, transform: transform}
+	if isGlob(pattern) {
+		t.glob = compileGlob(strings.ReplaceAll(pattern, "*", "**"))
+	}
+
+	l.transformers.mu.Lock()
+	defer l.transformers.mu.Unlock()
+	l.transformers.transformers = append(l.transformers.transformers, t)
+}
+
+// list returns the registered transformers
+func (s *transformerSet) list() []fieldTransformer {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.transformers
+}
+
+// field builds a zap field, applying any matching transformers, safe
+// serialization of nested values and the MaxFieldBytes limit
+func (l *Logger) field(transformers []fieldTransformer, key string, value interface{}) zap.Field {
+	for _, t := range transformers {
+		if (t.glob == nil && t.key == key) || (t.glob != nil && t.glob.MatchString(key)) {
+			value = t.transform(key, value)
+		}
+	}
+	if l.config.UnitConventions {
+		key, value = unitField(key, value)
+	}
+
+	// Encode common types directly, without reflection or zap.Any
+	switch v := value.(type) {
+	case string:
+		if limit := l.config.MaxFieldBytes; limit > 0 {
+			v = truncateString(v, limit)
+		}
+		return zap.String(key, v)
+	case int:
+		return zap.Int(key, v)
+	case int64:
+		return zap.Int64(key, v)
+	case float64:
+		return zap.Float64(key, v)
+	case bool:
+		return zap.Bool(key, v)
+	case time.Duration:
+		return zap.Duration(key, v)
+	case time.Time:
+		return zap.Time(key, v)
+	case sensitiveValue:
+		v.salt = l.salt
+		return zap.String(key, v.String())
+	}
+
+	maxDepth := l.config.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxDepth
+	}
+	value = safeValue(value, maxDepth, l.salt)
+
+	if limit := l.config.MaxFieldBytes; limit > 0 {
+		switch v := value.(type) {
+		case string:
+			value = truncateString(v, limit)
+		case []byte:
+			value = truncateString(string(v), limit)
+		}
+	}
+	return zap.Any(key, value)
+}
+
+// LowercaseTransform lowercases string values, e.g. to normalize emails
+func LowercaseTransform(key string, value interface{}) interface{} {
+	if s, ok := value.(string); ok {
+		return strings.ToLower(s)
+	}
+	return value
+}
+
+// DurationMillisTransform converts time.Duration values to milliseconds
+func DurationMillisTransform(key string, value interface{}) interface{} {
+	if d, ok := value.(time.Duration); ok {
+		return float64(d) / float64(time.Millisecond)
+	}
+	return value
+}
+
+// StatusClassTransform buckets HTTP status codes into classes (e.g. 404 →
+// "4xx")
+func StatusClassTransform(key string, value interface{}) interface{} {
+	if code, ok := value.(int); ok && code >= 100 && code < 600 {
+		return fmt.Sprintf("%dxx", code/100)
+	}
+	return value
+}
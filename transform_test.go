@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRegisterFieldTransformer(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "transform-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	logger.RegisterFieldTransformer("*email", LowercaseTransform)
+	logger.RegisterFieldTransformer("elapsed", DurationMillisTransform)
+	logger.RegisterFieldTransformer("status_code", StatusClassTransform)
+
+	child := logger.With(LogContext{"owner_email": "Ops@Example.com"})
+	child.Info(context.Background(), "request", LogContext{
+		"email":       "Jane.Doe@Example.COM",
+		"elapsed":     1500 * time.Microsecond,
+		"status_code": 404,
+		"name":        "Jane",
+	})
+
+	fields := observedLogs.TakeAll()[0].ContextMap()
+	expected := map[string]interface{}{
+		"email":       "jane.doe@example.com",
+		"owner_email": "ops@example.com",
+		"elapsed":     1.5,
+		"status_code": "4xx",
+		"name":        "Jane",
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, fields[key])
+		}
+	}
+}
+
+func TestBuiltinTransformersIgnoreOtherTypes(t *testing.T) {
+	if v := LowercaseTransform("email", 42); v != 42 {
+		t.Errorf("Expected non-string to be unchanged, got %v", v)
+	}
+	if v := DurationMillisTransform("elapsed", "1s"); v != "1s" {
+		t.Errorf("Expected non-duration to be unchanged, got %v", v)
+	}
+	if v := StatusClassTransform("status_code", 42); v != 42 {
+		t.Errorf("Expected out-of-range status to be unchanged, got %v", v)
+	}
+}
+
+func TestTruncationLimits(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:     "truncation-test",
+		ServiceVersion:  "1.0.0",
+		Env:             "test",
+		Level:           LevelDEBUG,
+		MaxFieldBytes:   10,
+		MaxMessageBytes: 12,
+	})
+
+	logger.Info(context.Background(), "query failed after retries", LogContext{
+		"sql":     "SELECT * FROM orders WHERE id = 1",
+		"payload": []byte("0123456789abcdef"),
+		"short":   "ok",
+		"count":   12345678901,
+	})
+
+	entry := observedLogs.TakeAll()[0]
+	if entry.Message != "query failed...(truncated, 26 bytes)" {
+		t.Errorf("Expected truncated message, got %q", entry.Message)
+	}
+
+	fields := entry.ContextMap()
+	expected := map[string]interface{}{
+		"sql":     "SELECT * F...(truncated, 33 bytes)",
+		"payload": "0123456789...(truncated, 16 bytes)",
+		"short":   "ok",
+		"count":   int64(12345678901),
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, fields[key])
+		}
+	}
+}
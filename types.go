@@ -1,6 +1,9 @@
 package logger
 
-import "time"
+import (
+	"io"
+	"time"
+)
 
 // LogLevel represents the severity of a log entry
 type LogLevel string
@@ -10,18 +13,24 @@ const (
 	LevelERROR LogLevel = "ERROR"
 	LevelWARN  LogLevel = "WARN"
 	LevelDEBUG LogLevel = "DEBUG"
+	// LevelOFF disables logging, e.g. for a log type in Config.TypeLevels
+	LevelOFF LogLevel = "OFF"
 )
 
 // LogType represents the category of a log entry
 type LogType string
 
 const (
-	TypeNormal   LogType = "normal"
-	TypeHTTP     LogType = "http"
-	TypeError    LogType = "error"
-	TypeSecurity LogType = "security"
-	TypeAudit    LogType = "audit"
-	TypeDebug    LogType = "debug"
+	TypeNormal    LogType = "normal"
+	TypeHTTP      LogType = "http"
+	TypeError     LogType = "error"
+	TypeSecurity  LogType = "security"
+	TypeAudit     LogType = "audit"
+	TypeDebug     LogType = "debug"
+	TypeRPC       LogType = "rpc"
+	TypeDB        LogType = "db"
+	TypeMessaging LogType = "messaging"
+	TypeJob       LogType = "job"
 )
 
 // Config holds logger initialization configuration
@@ -30,8 +39,159 @@ type Config struct {
 	ServiceVersion string
 	Env            string
 	Level          LogLevel
+	// Output receives the encoded entries (default os.Stdout)
+	Output io.Writer
+	// Clock, when set, supplies the time for @timestamp, MeasureDuration,
+	// the durations logged by the middleware and InstrumentJob, and the
+	// Sampling and ErrorSuppression windows
+	Clock Clock
+	// Metrics, when set, receives counts of written and dropped entries
+	Metrics EntryMetrics
+	// ErrorRateAlert, when set, fires a callback when ERROR entries exceed a
+	// threshold per time window
+	ErrorRateAlert *ErrorRateAlert
+	// Redactor, when set, masks sensitive keys and values in every entry,
+	// including bodies captured by the HTTP middleware
+	Redactor *Redactor
+	// MaskPII partially masks card numbers, emails, phone numbers and IBANs
+	// in string values (e.g. enable only where Env is "production")
+	MaskPII bool
+	// SensitiveSalt keys the hashes logged by Sensitive, IPHash and
+	// PrivacyHash for this logger. Set it (from a secret) to correlate values
+	// across processes; otherwise a random salt is used per process.
+	SensitiveSalt string
+	// Privacy, when set, drops or hashes personal-data keys (user_id, ip,
+	// email, ...) in every entry
+	Privacy *PrivacyOptions
+	// MaxFieldBytes truncates string field values longer than this with a
+	// "...(truncated, N bytes)" suffix (0 = no limit)
+	MaxFieldBytes int
+	// MaxMessageBytes truncates messages longer than this (0 = no limit)
+	MaxMessageBytes int
+	// MaxDepth limits how deeply nested field values are serialized; deeper
+	// values and cycles are replaced with a type placeholder (default 10)
+	MaxDepth int
+	// AuditChain, when set, hash-chains audit entries so tampering can be
+	// detected with VerifyAuditChain
+	AuditChain *AuditChainOptions
+	// AuditDelivery, when set, writes audit entries synchronously to a
+	// dedicated sink, regardless of Level, hooks or the normal output
+	AuditDelivery *AuditDeliveryOptions
+	// SecurityFormat, when set, writes security entries in CEF or LEEF
+	// instead of JSON for direct SIEM ingestion
+	SecurityFormat *SecurityFormatOptions
+	// TypeLevels sets the minimum level of individual log types,
+	// independently of Level (e.g. TypeHTTP: LevelWARN, TypeDebug: LevelOFF,
+	// TypeAudit: LevelDEBUG to always log audit entries)
+	TypeLevels map[LogType]LogLevel
+	// EnableCaller adds the file and line of the logging call as caller
+	EnableCaller bool
+	// CallerSkip skips additional stack frames when reporting the caller,
+	// for applications that wrap the logger in their own helpers
+	CallerSkip int
+	// EnableFunction adds the name of the calling function and its package
+	// as function and package, with the same CallerSkip correction
+	EnableFunction bool
+	// ProcessFields adds process.pid and, when running in a container,
+	// container.id (read from the process's cgroup) to every entry
+	ProcessFields bool
+	// GoroutineCount adds the current number of goroutines to every entry
+	// as runtime.goroutines, e.g. to diagnose goroutine leaks
+	GoroutineCount bool
+	// BuildMetadata adds service.revision and service.build_time to every
+	// entry, from Revision and BuildTime or the binary's VCS stamp
+	BuildMetadata bool
+	// LevelSignals switches the level to DEBUG on SIGUSR1 and back on
+	// SIGUSR2 (e.g. kill -USR1 <pid>), on platforms with these signals
+	LevelSignals bool
+	// Heartbeat, when set, logs an "alive" entry with uptime and entry
+	// counts at a fixed interval, regardless of Level
+	Heartbeat *HeartbeatOptions
+	// FatalTimeout bounds the OnFatal hooks and flushing before Fatal
+	// exits the process (default 5s)
+	FatalTimeout time.Duration
+	// CloudMetadata, when set, detects the AWS, GCP or Azure instance at
+	// startup and adds its region, zone, account and instance id to every
+	// entry
+	CloudMetadata *CloudMetadataOptions
+	// Encoding selects the output format, EncodingJSON (default),
+	// EncodingConsole for human-readable output during development, or
+	// EncodingMsgpack or EncodingOTLP for collectors and agents accepting
+	// binary framing
+	Encoding string
+	// StackTraces adds the stack of the logging call to ERROR entries as
+	// stacktrace
+	StackTraces bool
+	// Sampling, when set, limits entries repeating the same level and
+	// message; ERROR, security and audit entries are never sampled
+	Sampling *SamplingOptions
+	// ErrorSuppression, when set, logs only the first entries of an error
+	// (by fingerprint, see ErrorFingerprint) repeating within a window and
+	// then a summary with the number of entries suppressed; security and
+	// audit entries are never suppressed
+	ErrorSuppression *ErrorSuppressionOptions
+	// ErrorFingerprint adds error.fingerprint to ERROR entries: a hash of
+	// the error type, the error message with IDs and numbers normalized
+	// and the logging function, for grouping occurrences of the same error
+	ErrorFingerprint bool
+	// ClassifyErrors adds error.class (see ErrorClass) to WARN and ERROR
+	// entries from a context deadline or cancellation, network errors and
+	// status codes, unless the entry already has one (see Class)
+	ClassifyErrors bool
+	// UnitConventions emits time.Duration values as *_ms float fields,
+	// other *_ms fields as floats and *_bytes fields as integers,
+	// whichever call site logs them
+	UnitConventions bool
+	// TimeFormat selects how @timestamp and time fields are written by the
+	// JSON and console encodings, TimeFormatISO8601 (default) or
+	// TimeFormatEpochMillis
+	TimeFormat string
+	// FieldShape, when set, reshapes the fields logged with an entry:
+	// FieldShapeNested expands dotted keys into objects and FieldShapeFlat
+	// flattens nested maps into dotted keys. The standard fields
+	// (@timestamp, log.level, service.name, ...) keep their names.
+	FieldShape string
+	// FieldNamespace, when set, places the fields logged with an entry
+	// under an object with this key (e.g. "labels"), leaving the top level
+	// to the standard fields and bounding Elasticsearch index mappings
+	FieldNamespace string
+	// Schema, when set, enables strict mode: entries missing required
+	// fields or using disallowed keys are quarantined or dropped
+	Schema *SchemaOptions
+	// Enrichment, when set, adds fields derived from the client IP (the ip
+	// or client.address field) of entries, e.g. geo.country and geo.city
+	// from loggeoip
+	Enrichment Enrichment
+	// AnonymizeIP anonymizes the client IP (ip and client.address fields,
+	// each address of forwarded_for, and access log lines) with IPTruncate
+	// or IPHash
+	AnonymizeIP IPAnonymization
 }
 
+// Output encodings for Config.Encoding
+const (
+	EncodingJSON    = "json"
+	EncodingConsole = "console"
+	// EncodingMsgpack writes each entry as a MessagePack map, back to back
+	// without separators. Times are MessagePack timestamps and durations
+	// seconds; SecurityFormat does not apply.
+	EncodingMsgpack = "msgpack"
+	// EncodingOTLP writes each entry as an OTLP LogRecord protobuf message
+	// prefixed with its varint length, for agents that forward OTLP without
+	// parsing JSON. The message is the body, trace_id and span_id the trace
+	// context and other fields attributes; SecurityFormat does not apply.
+	EncodingOTLP = "otlp"
+)
+
+// Timestamp formats for Config.TimeFormat
+const (
+	// TimeFormatISO8601 writes timestamps like 2024-01-15T10:30:00.123Z
+	TimeFormatISO8601 = "iso8601"
+	// TimeFormatEpochMillis writes timestamps as milliseconds since the
+	// Unix epoch, e.g. 1705314600123.456
+	TimeFormatEpochMillis = "epoch_millis"
+)
+
 // LogContext holds arbitrary key-value pairs for structured logging
 type LogContext map[string]interface{}
 
@@ -53,7 +213,11 @@ func Fields(keysAndValues ...interface{}) LogContext {
 	return context
 }
 
-// MeasureDuration calculates the duration in milliseconds since the given start time
+// MeasureDuration calculates the duration in milliseconds since the given start time,
+// on the singleton's Clock when one is configured
 func MeasureDuration(start time.Time) float64 {
+	if instance != nil {
+		return float64(instance.since(start).Milliseconds())
+	}
 	return float64(time.Since(start).Milliseconds())
 }
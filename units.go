@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Millis returns name_ms with the duration in fractional milliseconds,
+// e.g. Millis("db_query", d) → db_query_ms: 12.5
+func Millis(name string, d time.Duration) Pair {
+	return Pair{Key: name + "_ms", Value: durationMillis(d)}
+}
+
+// Bytes returns name_bytes with the byte count as an integer, e.g.
+// Bytes("upload", n) → upload_bytes: 2048
+func Bytes(name string, n int64) Pair {
+	return Pair{Key: name + "_bytes", Value: n}
+}
+
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// unitField applies Config.UnitConventions to a field: durations become
+// *_ms floats, integers under *_ms become floats and floats under *_bytes
+// are rounded to integers
+func unitField(key string, value interface{}) (string, interface{}) {
+	if d, ok := value.(time.Duration); ok {
+		if !strings.HasSuffix(key, "_ms") {
+			key += "_ms"
+		}
+		return key, durationMillis(d)
+	}
+
+	switch {
+	case strings.HasSuffix(key, "_ms"):
+		switch v := value.(type) {
+		case int:
+			return key, float64(v)
+		case int32:
+			return key, float64(v)
+		case int64:
+			return key, float64(v)
+		case uint64:
+			return key, float64(v)
+		case float32:
+			return key, float64(v)
+		}
+	case strings.HasSuffix(key, "_bytes"):
+		switch v := value.(type) {
+		case int:
+			return key, int64(v)
+		case int32:
+			return key, int64(v)
+		case float32:
+			return key, int64(math.Round(float64(v)))
+		case float64:
+			return key, int64(math.Round(v))
+		}
+	}
+	return key, value
+}
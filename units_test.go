@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestUnitHelpers(t *testing.T) {
+	context := Pairs(
+		Millis("db_query", 12500*time.Microsecond),
+		Bytes("upload", 2048),
+	)
+
+	if context["db_query_ms"] != 12.5 {
+		t.Errorf("Expected db_query_ms=12.5, got %v", context["db_query_ms"])
+	}
+	if context["upload_bytes"] != int64(2048) {
+		t.Errorf("Expected upload_bytes=2048, got %v", context["upload_bytes"])
+	}
+}
+
+func TestUnitConventions(t *testing.T) {
+	logger, observedLogs := setupObservedLogger(Config{
+		ServiceName:     "units-test",
+		ServiceVersion:  "1.0.0",
+		Env:             "test",
+		Level:           LevelDEBUG,
+		UnitConventions: true,
+	})
+
+	logger.Info(context.Background(), "Export finished", LogContext{
+		"timeout":        1500 * time.Microsecond,
+		"elapsed_ms":     2 * time.Second,
+		"duration_ms":    int64(42),
+		"payload_bytes":  1024.4,
+		"response_bytes": 10,
+		"retries":        3,
+	})
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(logs))
+	}
+
+	fields := logs[0].ContextMap()
+	expected := map[string]interface{}{
+		"timeout_ms":     1.5,
+		"elapsed_ms":     2000.0,
+		"duration_ms":    42.0,
+		"payload_bytes":  int64(1024),
+		"response_bytes": int64(10),
+		"retries":        int64(3),
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Expected %s=%v (%T), got %v (%T)", key, value, value, fields[key], fields[key])
+		}
+	}
+	if _, ok := fields["timeout"]; ok {
+		t.Error("Expected timeout to be renamed to timeout_ms")
+	}
+}
+
+func TestUnitField(t *testing.T) {
+	tests := []struct {
+		key   string
+		value interface{}
+		want  interface{}
+	}{
+		{"wait_ms", 5, 5.0},
+		{"wait_ms", int32(5), 5.0},
+		{"wait_ms", int64(5), 5.0},
+		{"wait_ms", uint64(5), 5.0},
+		{"wait_ms", float32(2.5), 2.5},
+		{"body_bytes", 5, int64(5)},
+		{"body_bytes", int32(5), int64(5)},
+		{"body_bytes", float32(4.6), int64(5)},
+		{"body_bytes", 4.4, int64(4)},
+		{"count", 5, 5},
+	}
+	for _, tt := range tests {
+		if key, got := unitField(tt.key, tt.value); key != tt.key || got != tt.want {
+			t.Errorf("unitField(%s, %T %v) = %s %T %v, want %T %v", tt.key, tt.value, tt.value, key, got, got, tt.want, tt.want)
+		}
+	}
+}
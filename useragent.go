@@ -0,0 +1,38 @@
+package logger
+
+import "github.com/mssola/useragent"
+
+// addUserAgentFields adds the browser, OS and device class parsed from a
+// User-Agent header as user_agent.name, user_agent.version, user_agent.os,
+// user_agent.os_version and user_agent.device (bot, mobile or desktop)
+func addUserAgentFields(context LogContext, header string) {
+	if header == "" {
+		return
+	}
+
+	ua := useragent.New(header)
+	name, version := ua.Browser()
+	if name != "" {
+		context["user_agent.name"] = name
+	}
+	if version != "" {
+		context["user_agent.version"] = version
+	}
+
+	platform := ua.OSInfo()
+	if platform.Name != "" {
+		context["user_agent.os"] = platform.Name
+	}
+	if platform.Version != "" {
+		context["user_agent.os_version"] = platform.Version
+	}
+
+	switch {
+	case ua.Bot():
+		context["user_agent.device"] = "bot"
+	case ua.Mobile():
+		context["user_agent.device"] = "mobile"
+	default:
+		context["user_agent.device"] = "desktop"
+	}
+}
@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAddUserAgentFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected LogContext
+	}{
+		{
+			name:   "desktop browser",
+			header: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected: LogContext{
+				"user_agent.name":       "Chrome",
+				"user_agent.version":    "120.0.0.0",
+				"user_agent.os":         "Windows",
+				"user_agent.os_version": "10",
+				"user_agent.device":     "desktop",
+			},
+		},
+		{
+			name:   "mobile browser",
+			header: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+			expected: LogContext{
+				"user_agent.name":       "Safari",
+				"user_agent.version":    "17.1",
+				"user_agent.os":         "iPhone OS",
+				"user_agent.os_version": "17.1",
+				"user_agent.device":     "mobile",
+			},
+		},
+		{
+			name:   "crawler",
+			header: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			expected: LogContext{
+				"user_agent.name":    "Googlebot",
+				"user_agent.version": "2.1",
+				"user_agent.device":  "bot",
+			},
+		},
+		{
+			name:     "missing header",
+			header:   "",
+			expected: LogContext{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			context := LogContext{}
+			addUserAgentFields(context, tt.header)
+
+			if len(context) != len(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, context)
+			}
+			for key, value := range tt.expected {
+				if context[key] != value {
+					t.Errorf("Expected %s=%v, got %v", key, value, context[key])
+				}
+			}
+		})
+	}
+}
+
+func TestFiberMiddlewareParseUserAgent(t *testing.T) {
+	_, observedLogs := setupObservedLogger(Config{
+		ServiceName:    "useragent-test",
+		ServiceVersion: "1.0.0",
+		Env:            "test",
+		Level:          LevelDEBUG,
+	})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(&MiddlewareOptions{ParseUserAgent: true}))
+	app.Get("/products", func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	req := httptest.NewRequest("GET", "/products", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)")
+	_, _ = app.Test(req)
+
+	logs := observedLogs.TakeAll()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(logs))
+	}
+	fields := logs[0].ContextMap()
+	if fields["user_agent.device"] != "bot" || fields["user_agent.name"] != "bingbot" {
+		t.Errorf("Expected parsed bot fields, got %v", fields)
+	}
+	if fields["user_agent"] == nil {
+		t.Error("Expected the raw user_agent to be kept")
+	}
+}